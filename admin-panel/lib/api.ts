@@ -5,13 +5,40 @@ import {
   Course, 
   Post, 
   Mentor, 
+  MentorProfile,
+  AssignedMentor,
+  MentorAssignment,
+  MentorAvailability,
+  BookingSlot,
+  Booking,
+  BookingStatus,
   User, 
   Enrollment,
   DashboardStats,
+  ScheduleEntry,
+  Category,
+  CategoryRequest,
+  Tag,
+  Taxonomy,
+  TaxonomyContentType,
+  Comment,
+  CommentStatus,
+  Review,
+  ReviewStatus,
+  Notification,
+  ArticleImportResult,
+  CatalogImportReport,
+  CatalogKind,
+  ItemTranslations,
+  TranslatableContentType,
+  TranslationLocale,
+  TranslationReport,
   FilterOptions,
   CreateCourseRequest,
   CreatePostRequest,
-  CreateMentorRequest
+  CreateMentorRequest,
+  MSCer,
+  CreateMSCerRequest
 } from '@/types'
 
 class ApiClient {
@@ -67,6 +94,12 @@ class ApiClient {
     return response.data
   }
 
+  // Publishing schedule
+  async getSchedule(params?: { from?: string; to?: string }): Promise<ApiResponse<ScheduleEntry[]>> {
+    const response = await this.client.get('/schedule', { params })
+    return response.data
+  }
+
   // Courses
   async getCourses(filters?: FilterOptions): Promise<PaginatedResponse<Course>> {
     const response = await this.client.get('/courses', { params: filters })
@@ -103,6 +136,29 @@ class ApiClient {
     return response.data
   }
 
+  async getCourseMentors(id: string): Promise<ApiResponse<AssignedMentor[]>> {
+    const response = await this.client.get(`/courses/${id}/mentors`)
+    return response.data
+  }
+
+  // Replaces the mentors of the course, in the given order
+  async setCourseMentors(id: string, mentors: MentorAssignment[]): Promise<ApiResponse<AssignedMentor[]>> {
+    const response = await this.client.put(`/courses/${id}/mentors`, { mentors })
+    return response.data
+  }
+
+  // Programs
+  async getProgramMentors(id: string): Promise<ApiResponse<AssignedMentor[]>> {
+    const response = await this.client.get(`/programs/${id}/mentors`)
+    return response.data
+  }
+
+  // Replaces the mentors of the program, in the given order
+  async setProgramMentors(id: string, mentors: MentorAssignment[]): Promise<ApiResponse<AssignedMentor[]>> {
+    const response = await this.client.put(`/programs/${id}/mentors`, { mentors })
+    return response.data
+  }
+
   // Posts
   async getPosts(filters?: FilterOptions): Promise<PaginatedResponse<Post>> {
     const response = await this.client.get('/posts', { params: filters })
@@ -165,6 +221,89 @@ class ApiClient {
     return response.data
   }
 
+  async getMentorProfile(id: string): Promise<ApiResponse<MentorProfile>> {
+    const response = await this.client.get(`/mentors/${id}/profile`)
+    return response.data
+  }
+
+  // Sections left out are kept; an empty list clears a section
+  async setMentorProfile(id: string, data: Partial<MentorProfile>): Promise<ApiResponse<MentorProfile>> {
+    const response = await this.client.put(`/mentors/${id}/profile`, data)
+    return response.data
+  }
+
+  // Mentor booking
+  async getMentorAvailability(id: string): Promise<ApiResponse<MentorAvailability[]>> {
+    const response = await this.client.get(`/mentors/${id}/availability`)
+    return response.data
+  }
+
+  async setMentorAvailability(id: string, availability: MentorAvailability[]): Promise<ApiResponse<MentorAvailability[]>> {
+    const response = await this.client.put(`/mentors/${id}/availability`, { availability })
+    return response.data
+  }
+
+  async getMentorSlots(id: string, params?: { from?: string; to?: string }): Promise<ApiResponse<BookingSlot[]>> {
+    const response = await this.client.get(`/mentors/${id}/slots`, { params })
+    return response.data
+  }
+
+  async getMentorCalendarURL(id: string): Promise<ApiResponse<{ url: string }>> {
+    const response = await this.client.get(`/mentors/${id}/calendar`)
+    return response.data
+  }
+
+  async regenerateMentorCalendarURL(id: string): Promise<ApiResponse<{ url: string }>> {
+    const response = await this.client.post(`/mentors/${id}/calendar/regenerate`)
+    return response.data
+  }
+
+  async getBookings(params?: { mentor_id?: string; status?: BookingStatus; upcoming?: boolean; page?: number; limit?: number }): Promise<ApiResponse<PaginatedResponse<Booking>>> {
+    const response = await this.client.get('/bookings', { params })
+    return response.data
+  }
+
+  async createBooking(data: { mentor_id: string; starts_at: string; timezone?: string; note?: string }): Promise<ApiResponse<Booking>> {
+    const response = await this.client.post('/bookings', data)
+    return response.data
+  }
+
+  async rescheduleBooking(id: string, startsAt: string): Promise<ApiResponse<Booking>> {
+    const response = await this.client.post(`/bookings/${id}/reschedule`, { starts_at: startsAt })
+    return response.data
+  }
+
+  async cancelBooking(id: string, reason?: string): Promise<ApiResponse<Booking>> {
+    const response = await this.client.post(`/bookings/${id}/cancel`, { reason })
+    return response.data
+  }
+
+  // MSCers
+  async getMSCers(filters?: FilterOptions & { course?: string; graduation_year?: number }): Promise<PaginatedResponse<MSCer>> {
+    const response = await this.client.get('/mscers', { params: filters })
+    return response.data
+  }
+
+  async getMSCer(id: string): Promise<ApiResponse<MSCer>> {
+    const response = await this.client.get(`/mscers/${id}`)
+    return response.data
+  }
+
+  async createMSCer(data: CreateMSCerRequest): Promise<ApiResponse<MSCer>> {
+    const response = await this.client.post('/mscers', data)
+    return response.data
+  }
+
+  async updateMSCer(id: string, data: CreateMSCerRequest): Promise<ApiResponse<MSCer>> {
+    const response = await this.client.put(`/mscers/${id}`, data)
+    return response.data
+  }
+
+  async deleteMSCer(id: string): Promise<ApiResponse<null>> {
+    const response = await this.client.delete(`/mscers/${id}`)
+    return response.data
+  }
+
   // Users
   async getUsers(filters?: FilterOptions): Promise<PaginatedResponse<User>> {
     const response = await this.client.get('/users', { params: filters })
@@ -192,6 +331,167 @@ class ApiClient {
     return response.data
   }
 
+  // Categories & tags
+  async getCategories(flat = false): Promise<ApiResponse<Category[]>> {
+    const response = await this.client.get('/categories', { params: flat ? { flat: true } : undefined })
+    return response.data
+  }
+
+  async createCategory(data: CategoryRequest): Promise<ApiResponse<Category>> {
+    const response = await this.client.post('/categories', data)
+    return response.data
+  }
+
+  async updateCategory(id: string, data: CategoryRequest): Promise<ApiResponse<Category>> {
+    const response = await this.client.put(`/categories/${id}`, data)
+    return response.data
+  }
+
+  async deleteCategory(id: string): Promise<ApiResponse<null>> {
+    const response = await this.client.delete(`/categories/${id}`)
+    return response.data
+  }
+
+  async getTags(q?: string): Promise<ApiResponse<Tag[]>> {
+    const response = await this.client.get('/tags', { params: q ? { q } : undefined })
+    return response.data
+  }
+
+  async updateTag(id: string, data: { name: string; slug?: string }): Promise<ApiResponse<Tag>> {
+    const response = await this.client.put(`/tags/${id}`, data)
+    return response.data
+  }
+
+  async deleteTag(id: string): Promise<ApiResponse<null>> {
+    const response = await this.client.delete(`/tags/${id}`)
+    return response.data
+  }
+
+  async getTaxonomy(type: TaxonomyContentType, id: string): Promise<ApiResponse<Taxonomy>> {
+    const response = await this.client.get(`/taxonomy/${type}/${id}`)
+    return response.data
+  }
+
+  async setTaxonomy(type: TaxonomyContentType, id: string, data: { category_ids: string[]; tags: string[] }): Promise<ApiResponse<Taxonomy>> {
+    const response = await this.client.put(`/taxonomy/${type}/${id}`, data)
+    return response.data
+  }
+
+  // Public blog: published posts appear there automatically
+  async importLegacyBlogPosts(): Promise<ApiResponse<{ imported: number }>> {
+    const response = await this.client.post('/allblogposts/import')
+    return response.data
+  }
+
+  // Markdown/MDX articles; images are matched to references by file name
+  async importArticles(files: File[], images: File[] = []): Promise<ApiResponse<ArticleImportResult[]>> {
+    const formData = new FormData()
+    files.forEach((file) => formData.append('files', file))
+    images.forEach((image) => formData.append('images', image))
+
+    const response = await this.client.post('/allblogposts/import/articles', formData, {
+      headers: {
+        'Content-Type': 'multipart/form-data',
+      },
+      // Per-file errors come back as 400 when no article was imported
+      validateStatus: (status) => status < 500,
+    })
+    return response.data
+  }
+
+  // Comment moderation
+  async getComments(params?: { status?: CommentStatus; blog_post_id?: number; page?: number; limit?: number }): Promise<ApiResponse<PaginatedResponse<Comment>>> {
+    const response = await this.client.get('/comments', { params })
+    return response.data
+  }
+
+  async moderateComment(id: string, action: 'approve' | 'reject' | 'spam'): Promise<ApiResponse<Comment>> {
+    const response = await this.client.patch(`/comments/${id}/${action}`)
+    return response.data
+  }
+
+  async deleteComment(id: string): Promise<ApiResponse<null>> {
+    const response = await this.client.delete(`/comments/${id}`)
+    return response.data
+  }
+
+  // Review moderation
+  async getReviews(params?: { status?: ReviewStatus; entity_type?: 'course' | 'mentor'; entity_id?: string; page?: number; limit?: number }): Promise<ApiResponse<PaginatedResponse<Review>>> {
+    const response = await this.client.get('/reviews', { params })
+    return response.data
+  }
+
+  async moderateReview(id: string, action: 'approve' | 'reject'): Promise<ApiResponse<Review>> {
+    const response = await this.client.patch(`/reviews/${id}/${action}`)
+    return response.data
+  }
+
+  async deleteReview(id: string): Promise<ApiResponse<null>> {
+    const response = await this.client.delete(`/reviews/${id}`)
+    return response.data
+  }
+
+  // Notifications
+  async getNotifications(params?: { unread?: boolean; page?: number; limit?: number }): Promise<ApiResponse<PaginatedResponse<Notification>>> {
+    const response = await this.client.get('/notifications', { params })
+    return response.data
+  }
+
+  async markNotificationRead(id: string): Promise<ApiResponse<null>> {
+    const response = await this.client.patch(`/notifications/${id}/read`)
+    return response.data
+  }
+
+  async markAllNotificationsRead(): Promise<ApiResponse<null>> {
+    const response = await this.client.patch('/notifications/read-all')
+    return response.data
+  }
+
+  // Translations: empty values remove a field's translation
+  async getTranslations(type: TranslatableContentType, id: string): Promise<ApiResponse<ItemTranslations>> {
+    const response = await this.client.get(`/translations/${type}/${id}`)
+    return response.data
+  }
+
+  async setTranslations(type: TranslatableContentType, id: string, locale: TranslationLocale, fields: Record<string, string>): Promise<ApiResponse<null>> {
+    const response = await this.client.put(`/translations/${type}/${id}/${locale}`, { fields })
+    return response.data
+  }
+
+  async deleteTranslations(type: TranslatableContentType, id: string, locale: TranslationLocale): Promise<ApiResponse<null>> {
+    const response = await this.client.delete(`/translations/${type}/${id}/${locale}`)
+    return response.data
+  }
+
+  async getMissingTranslations(params?: { locale?: TranslationLocale; type?: TranslatableContentType }): Promise<ApiResponse<TranslationReport[]>> {
+    const response = await this.client.get('/translations/missing', { params })
+    return response.data
+  }
+
+  // Catalog import/export: items are matched by slug (email for mentors)
+  async importCatalog(kind: CatalogKind, file: File, dryRun = false): Promise<ApiResponse<CatalogImportReport>> {
+    const formData = new FormData()
+    formData.append('file', file)
+
+    const response = await this.client.post(`/catalog/${kind}/import`, formData, {
+      params: { dry_run: dryRun },
+      headers: {
+        'Content-Type': 'multipart/form-data',
+      },
+      // Validation errors come back as 400 with the report
+      validateStatus: (status) => status < 500,
+    })
+    return response.data
+  }
+
+  async exportCatalog(kind: CatalogKind, format: 'csv' | 'json' = 'csv'): Promise<Blob> {
+    const response = await this.client.get(`/catalog/${kind}/export`, {
+      params: { format },
+      responseType: 'blob',
+    })
+    return response.data
+  }
+
   // File Upload
   async uploadFile(file: File, type: 'image' | 'video' | 'document' = 'image'): Promise<ApiResponse<{ url: string }>> {
     const formData = new FormData()
@@ -25,6 +25,65 @@ export interface AuthResponse {
   user: User
 }
 
+// Workflow Types
+export type ContentStatus = 'draft' | 'pending_review' | 'scheduled' | 'published' | 'archived'
+
+export interface ScheduleEntry {
+  entity_type: 'post' | 'course'
+  entity_id: string
+  title: string
+  slug: string
+  status: ContentStatus
+  action: 'publish' | 'unpublish'
+  at: string
+}
+
+// Taxonomy Types
+export interface Category {
+  id: string
+  name: string
+  slug: string
+  description?: string
+  parent_id?: string
+  position: number
+  children?: Category[]
+  created_at: string
+  updated_at: string
+}
+
+export interface Tag {
+  id: string
+  name: string
+  slug: string
+  created_at: string
+}
+
+export interface Facet {
+  slug: string
+  name: string
+  count: number
+}
+
+export interface Facets {
+  categories: Facet[]
+  tags: Facet[]
+}
+
+export interface Taxonomy {
+  categories: Category[]
+  tags: Tag[]
+}
+
+export type TaxonomyContentType = 'courses' | 'posts' | 'programs' | 'projects' | 'mentors' | 'allblogposts'
+
+export interface CategoryRequest {
+  name: string
+  slug?: string
+  description?: string
+  parent_id?: string | null
+  position?: number
+}
+
 // Course Types
 export interface Course {
   id: string
@@ -33,11 +92,18 @@ export interface Course {
   description?: string
   thumbnail_url?: string
   category?: string
-  status: 'draft' | 'pending_review' | 'published' | 'archived'
+  status: ContentStatus
+  publish_at?: string
+  unpublish_at?: string
   author_id: string
   author?: User
   lessons?: Lesson[]
   enrollments_count?: number
+  categories?: Category[]
+  tags?: Tag[]
+  mentors?: AssignedMentor[]
+  rating_average: number
+  rating_count: number
   created_at: string
   updated_at: string
 }
@@ -55,11 +121,13 @@ export interface Lesson {
 
 export interface CreateCourseRequest {
   title: string
-  slug: string
+  slug?: string // derived from the title when empty
   description?: string
   thumbnail_url?: string
   category?: string
   status?: 'draft' | 'pending_review'
+  publish_at?: string
+  unpublish_at?: string
 }
 
 // Post Types
@@ -71,27 +139,34 @@ export interface Post {
   excerpt?: string
   thumbnail_url?: string
   category?: string
-  status: 'draft' | 'pending_review' | 'published' | 'archived'
+  status: ContentStatus
+  publish_at?: string
+  unpublish_at?: string
   author_id: string
   author?: User
+  categories?: Category[]
+  tags?: Tag[]
   created_at: string
   updated_at: string
 }
 
 export interface CreatePostRequest {
   title: string
-  slug: string
+  slug?: string // derived from the title when empty
   content?: any
   excerpt?: string
   thumbnail_url?: string
   category?: string
   status?: 'draft' | 'pending_review'
+  publish_at?: string
+  unpublish_at?: string
 }
 
 // Mentor Types
 export interface Mentor {
   id: string
   name: string
+  slug: string
   title?: string
   bio?: string
   avatar_url?: string
@@ -100,12 +175,52 @@ export interface Mentor {
   linkedin_url?: string
   specialties?: string[]
   status: 'active' | 'inactive'
+  user_id?: string // account of the mentor, who may manage their own availability
+  rating_average: number
+  rating_count: number
+  profile?: MentorProfile
+  courses?: TaughtItem[]
+  programs?: TaughtItem[]
   created_at: string
   updated_at: string
 }
 
+// Mentors assigned to courses and programs, listed by position
+export type AssignmentRole = 'lead_instructor' | 'assistant'
+
+export interface AssignedMentor {
+  id: string
+  name: string
+  slug: string
+  title?: string
+  avatar_url?: string
+  role: AssignmentRole
+}
+
+export interface TaughtItem {
+  id: string
+  title: string
+  slug: string
+  role: AssignmentRole
+}
+
+export interface MentorAssignment {
+  mentor_id: string
+  role?: AssignmentRole // assistant when left out
+}
+
+// Structured mentor profile; entries are listed by position
+export interface MentorProfile {
+  education: { id?: string; position?: number; degree: string; school?: string; year?: string; thesis?: string }[]
+  work_history: { id?: string; position?: number; period?: string; role: string; organization?: string }[]
+  publications: { id?: string; position?: number; title: string; venue?: string; year?: string; url?: string }[]
+  awards: { id?: string; position?: number; title: string; issuer?: string; year?: string }[]
+  research_areas: { id?: string; position?: number; name: string }[]
+}
+
 export interface CreateMentorRequest {
   name: string
+  slug?: string
   title?: string
   bio?: string
   avatar_url?: string
@@ -114,6 +229,197 @@ export interface CreateMentorRequest {
   linkedin_url?: string
   specialties?: string[]
   status?: 'active'
+  user_id?: string
+}
+
+// MSCer Types
+// Alumni profile; shown on the public site once published, which requires consent
+export interface MSCer {
+  id: string
+  slug: string
+  name: string
+  company?: string
+  position?: string
+  avatar_url?: string
+  achievement?: string
+  testimonial?: string
+  graduation_year?: number
+  promotion?: string
+  social_impact?: string
+  course?: string
+  skills: string[]
+  achievements: string[]
+  mentoring?: string
+  background: { education?: string; previous_role?: string; experience?: string }
+  status: 'draft' | 'published'
+  consent: boolean
+  consent_at?: string
+  user_id?: string
+  program_id?: string
+  program?: { id: string; slug: string; title: string }
+  created_at: string
+  updated_at: string
+}
+
+export interface CreateMSCerRequest {
+  name: string
+  slug?: string
+  company?: string
+  position?: string
+  avatar_url?: string
+  achievement?: string
+  testimonial?: string
+  graduation_year?: number
+  promotion?: string
+  social_impact?: string
+  course?: string
+  skills?: string[]
+  achievements?: string[]
+  mentoring?: string
+  background?: { education?: string; previous_role?: string; experience?: string }
+  status?: 'draft' | 'published'
+  consent: boolean
+  user_id?: string
+  program_id?: string
+}
+
+// Booking Types
+// Weekly window a mentor offers sessions in; times are HH:MM in timezone, weekday 0 is Sunday
+export interface MentorAvailability {
+  id?: string
+  mentor_id?: string
+  weekday: number
+  start_time: string
+  end_time: string
+  timezone: string
+  slot_minutes?: number
+}
+
+export interface BookingSlot {
+  starts_at: string
+  ends_at: string
+  timezone: string
+}
+
+export type BookingStatus = 'confirmed' | 'cancelled'
+
+export interface Booking {
+  id: string
+  mentor_id: string
+  user_id: string
+  starts_at: string
+  ends_at: string
+  timezone: string
+  status: BookingStatus
+  note?: string
+  cancel_reason?: string
+  cancelled_by?: string
+  cancelled_at?: string
+  sequence: number
+  mentor?: Mentor
+  user?: { id: string; name: string }
+  created_at: string
+  updated_at: string
+}
+
+// Review Types
+// Learner ratings of courses and mentors; only approved reviews count in the ratings
+export type ReviewStatus = 'pending' | 'approved' | 'rejected'
+
+export interface Review {
+  id: string
+  entity_type: 'course' | 'mentor'
+  entity_id: string
+  user_id: string
+  rating: number // 1 to 5
+  content?: string
+  status: ReviewStatus
+  moderated_by?: string
+  moderated_at?: string
+  user?: { id: string; name: string }
+  created_at: string
+  updated_at: string
+}
+
+// Comment Types
+export type CommentStatus = 'pending' | 'approved' | 'rejected' | 'spam'
+
+export interface Comment {
+  id: string
+  blog_post_id: number
+  parent_id?: string
+  user_id: string
+  content: string
+  status: CommentStatus
+  moderated_by?: string
+  moderated_at?: string
+  user?: { id: string; name: string }
+  blog_post?: { id: number; slug: string; title: string }
+  created_at: string
+  updated_at: string
+}
+
+export interface Notification {
+  id: string
+  user_id: string
+  type: 'comment'
+  title: string
+  message?: string
+  link?: string
+  read_at?: string
+  created_at: string
+}
+
+// Translation Types
+export type TranslatableContentType = 'programs' | 'projects' | 'allblogposts' | 'mentors' | 'courses'
+
+export type TranslationLocale = 'en'
+
+export interface ItemTranslations {
+  entity_type: string
+  entity_id: string
+  fields: string[]
+  source: Record<string, string>
+  locales: Record<TranslationLocale, Record<string, string>>
+}
+
+export interface TranslationReport {
+  type: TranslatableContentType
+  locale: TranslationLocale
+  total: number
+  complete: number
+  missing: {
+    entity_type: string
+    entity_id: string
+    label: string
+    fields: string[]
+  }[]
+}
+
+// Catalog Import/Export Types
+export type CatalogKind = 'programs' | 'mentors' | 'projects' | 'allblogposts'
+
+export interface CatalogImportReport {
+  kind: CatalogKind
+  format: 'csv' | 'json'
+  dry_run: boolean
+  rows: number
+  created: number
+  updated: number
+  saved: boolean
+  warnings?: string[]
+  errors: { row: number; key?: string; message: string }[]
+}
+
+// Markdown article import: one result per file, error set when it was not imported
+export interface ArticleImportResult {
+  file: string
+  slug?: string
+  post_id?: string
+  blog_id?: number
+  created: boolean
+  warnings?: string[]
+  error?: string
 }
 
 // Enrollment Types
@@ -140,6 +446,7 @@ export interface PaginatedResponse<T> {
   page: number
   limit: number
   total_pages: number
+  facets?: Facets
 }
 
 // Form Types
@@ -166,12 +473,13 @@ export interface DashboardStats {
 export interface FilterOptions {
   status?: string
   category?: string
+  tag?: string
   author_id?: string
   date_from?: string
   date_to?: string
   search?: string
   page?: number
   limit?: number
-  sort?: string
+  sort?: string // 'rating' or 'reviews' for courses and mentors
   order?: 'asc' | 'desc'
 }
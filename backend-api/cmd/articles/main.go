@@ -0,0 +1,140 @@
+// Command articles imports Markdown and MDX articles with YAML front matter into the
+// blog, the same way POST /api/v1/allblogposts/import/articles does.
+//
+//	go run ./cmd/articles -author admin@msc.edu.vn -assets ../frontend/public ../frontend/data/chia-se
+//
+// Arguments are article files or directories of them. Local images are uploaded:
+// site-relative paths such as /images/a.jpg are read from -assets, other paths
+// relative to the article. The database and upload directory are read from the
+// environment, as for the API server.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"msc-backend-api/internal/blog"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/uploads"
+	"msc-backend-api/pkg/config"
+	"msc-backend-api/pkg/database"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+func main() {
+	log.SetFlags(0)
+	author := flag.String("author", "", "email of the user who owns imported posts")
+	assets := flag.String("assets", "", "directory site-relative image paths are read from, e.g. the frontend's public directory")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: articles -author <email> [-assets dir] <file or directory>...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *author == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	files, err := articleFiles(flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_ = godotenv.Load()
+	cfg := config.Load()
+	db, err := database.Initialize(cfg.SupabaseURL)
+	if err != nil {
+		log.Fatal("Database connection failed: ", err)
+	}
+	var owner models.User
+	if err := db.Where("email = ?", *author).First(&owner).Error; err != nil {
+		log.Fatalf("Author %s not found: %v", *author, err)
+	}
+	uploader := blog.NewImageUploader(uploads.NewStore(cfg))
+
+	failed := 0
+	for _, file := range files {
+		result, err := importFile(db, uploader, file, *assets, owner)
+		if err != nil {
+			result = &blog.ArticleResult{File: file, Error: err.Error()}
+			failed++
+		}
+		encoded, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(encoded))
+	}
+	if failed > 0 {
+		log.Fatalf("%d of %d articles were not imported", failed, len(files))
+	}
+}
+
+func importFile(db *gorm.DB, uploader *blog.ImageUploader, file, assets string, owner models.User) (*blog.ArticleResult, error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	article, err := blog.ParseArticle(file, src)
+	if err != nil {
+		return nil, err
+	}
+
+	open := func(ref string) (string, io.ReadCloser, error) {
+		name := filepath.Join(filepath.Dir(file), filepath.FromSlash(blog.ImagePath(ref)))
+		if strings.HasPrefix(ref, "/") {
+			if assets == "" {
+				return "", nil, os.ErrNotExist
+			}
+			name = filepath.Join(assets, filepath.FromSlash(blog.ImagePath(ref)))
+		}
+		if abs, err := filepath.Abs(name); err == nil {
+			name = abs
+		}
+		f, err := os.Open(name)
+		return name, f, err
+	}
+	warnings, undo := uploader.Upload(article, open)
+
+	result, err := blog.ImportArticle(db, article, owner.ID)
+	if err != nil {
+		undo()
+		return nil, err
+	}
+	result.Warnings = warnings
+	return result, nil
+}
+
+// articleFiles expands directories into the .md and .mdx files they contain
+func articleFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			if !d.IsDir() && (ext == ".md" || ext == ".mdx" || ext == ".markdown") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,109 @@
+// Command catalog imports and exports programs, mentors, projects and blog posts as CSV
+// or JSON, the same way the /api/v1/catalog endpoints do.
+//
+//	go run ./cmd/catalog import -kind programs -file programs.csv [-dry-run]
+//	go run ./cmd/catalog export -kind mentors -format json [-file mentors.json]
+//
+// The database is read from SUPABASE_URL, as for the API server.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"msc-backend-api/internal/catalog"
+	"msc-backend-api/pkg/config"
+	"msc-backend-api/pkg/database"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 || (os.Args[1] != "import" && os.Args[1] != "export") {
+		usage()
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	kindName := flags.String("kind", "", "programs, mentors, projects or allblogposts")
+	file := flags.String("file", "", "file to read or write; stdin or stdout when empty")
+	formatName := flags.String("format", "", "csv or json; defaults to the file extension, then csv")
+	dryRun := flags.Bool("dry-run", false, "validate the import without saving")
+	flags.Parse(os.Args[2:])
+
+	kind, err := catalog.Lookup(*kindName)
+	if err != nil {
+		log.Fatalf("-kind must be one of: %s", strings.Join(kindNames(), ", "))
+	}
+	format, err := parseFormat(*formatName, *file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_ = godotenv.Load()
+	db, err := database.Initialize(config.Load().SupabaseURL)
+	if err != nil {
+		log.Fatal("Database connection failed: ", err)
+	}
+
+	if command == "export" {
+		out := io.Writer(os.Stdout)
+		if *file != "" {
+			f, err := os.Create(*file)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := catalog.Export(db, kind, format, out); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+		return
+	}
+
+	in := io.Reader(os.Stdin)
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	report, err := catalog.Import(db, kind, format, in, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	encoded, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(encoded))
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+func parseFormat(name, file string) (catalog.Format, error) {
+	switch {
+	case name != "":
+		return catalog.ParseFormat(name)
+	case file != "":
+		return catalog.ParseFormat(file)
+	}
+	return catalog.FormatCSV, nil
+}
+
+func kindNames() []string {
+	return []string{catalog.Programs.Name, catalog.Mentors.Name, catalog.Projects.Name, catalog.AllBlogPosts.Name}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: catalog import|export -kind <kind> [-file path] [-format csv|json] [-dry-run]")
+	os.Exit(2)
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
+	"msc-backend-api/internal/engagement"
 	"msc-backend-api/internal/handlers"
 	"msc-backend-api/internal/middleware"
+	"msc-backend-api/internal/scheduler"
+	"msc-backend-api/internal/uploads"
 	"msc-backend-api/pkg/config"
 	"msc-backend-api/pkg/database"
+	"msc-backend-api/pkg/mailer"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -17,6 +27,9 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to finish on shutdown
+const shutdownTimeout = 15 * time.Second
+
 // @title MSC.EDU.VN Admin API
 // @version 1.0
 // @description Backend API cho hệ thống quản trị MSC.EDU.VN
@@ -43,6 +56,33 @@ func main() {
 		log.Fatal("Database connection failed: ", err)
 	}
 
+	// SIGINT/SIGTERM start a graceful shutdown
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	// Background jobs are stopped only after the HTTP server has drained, so the final
+	// counter flush includes the views and likes of the last requests
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	runJob := func(run func(context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			run(jobsCtx)
+		}()
+	}
+
+	// Background jobs run in every replica; they coordinate through row locks
+	runJob(scheduler.New(db, cfg.SchedulerInterval).Run)
+	runJob(scheduler.NewTrashPurger(db, cfg.TrashRetention).Run)
+
+	// View and like counts are buffered per replica and flushed periodically
+	tracker := engagement.NewTracker(db, cfg.ViewWindow, cfg.CounterFlush)
+	runJob(tracker.Run)
+
+	mail := mailer.New(cfg)
+
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -88,11 +128,28 @@ func main() {
 	courseHandler := handlers.NewCourseHandler(db)
 	postHandler := handlers.NewPostHandler(db)
 	mentorHandler := handlers.NewMentorHandler(db)
-	uploadHandler := handlers.NewUploadHandler(cfg)
+	uploadStore := uploads.NewStore(cfg)
+	uploadHandler := handlers.NewUploadHandler(cfg, uploadStore)
 	dashboardHandler := handlers.NewDashboardHandler(db)
 	programHandler := handlers.NewProgramHandler(db)
 	projectHandler := handlers.NewProjectHandler(db)
-	allBlogPostHandler := handlers.NewAllBlogPostHandler(db)
+	allBlogPostHandler := handlers.NewAllBlogPostHandler(db, tracker)
+	scheduleHandler := handlers.NewScheduleHandler(db)
+	trashHandler := handlers.NewTrashHandler(db)
+	searchHandler := handlers.NewSearchHandler(db)
+	taxonomyHandler := handlers.NewTaxonomyHandler(db)
+	engagementHandler := handlers.NewEngagementHandler(db, tracker)
+	commentHandler := handlers.NewCommentHandler(db, cfg)
+	notificationHandler := handlers.NewNotificationHandler(db)
+	feedHandler := handlers.NewFeedHandler(db, cfg)
+	sitemapHandler := handlers.NewSitemapHandler(db, cfg)
+	redirectHandler := handlers.NewRedirectHandler(db, cfg)
+	translationHandler := handlers.NewTranslationHandler(db)
+	catalogHandler := handlers.NewCatalogHandler(db)
+	articleHandler := handlers.NewArticleHandler(db, uploadStore)
+	bookingHandler := handlers.NewBookingHandler(db, cfg, mail)
+	reviewHandler := handlers.NewReviewHandler(db)
+	mscerHandler := handlers.NewMSCerHandler(db)
 
 	// -------- API v1 (cần auth) --------
 	v1 := r.Group("/api/v1")
@@ -108,11 +165,25 @@ func main() {
 		{
 			courses.GET("", courseHandler.GetCourses)
 			courses.POST("", middleware.RequireRole("admin", "editor", "partner"), courseHandler.CreateCourse)
+			courses.GET("/enrolled", courseHandler.GetEnrolledCourses)
 			courses.GET("/:id", courseHandler.GetCourse)
 			courses.PUT("/:id", middleware.RequireRole("admin", "editor", "partner"), courseHandler.UpdateCourse)
 			courses.DELETE("/:id", middleware.RequireRole("admin", "editor"), courseHandler.DeleteCourse)
 			courses.PATCH("/:id/approve", middleware.RequireRole("admin", "editor"), courseHandler.ApproveCourse)
 			courses.PATCH("/:id/reject", middleware.RequireRole("admin", "editor"), courseHandler.RejectCourse)
+			courses.PATCH("/:id/archive", middleware.RequireRole("admin", "editor"), courseHandler.ArchiveCourse)
+			courses.POST("/:id/transitions", middleware.RequireRole("admin", "editor", "partner"), courseHandler.TransitionCourse)
+			courses.GET("/:id/history", courseHandler.GetCourseHistory)
+			courses.GET("/:id/revisions", courseHandler.GetCourseRevisions)
+			courses.GET("/:id/revisions/diff", courseHandler.DiffCourseRevisions)
+			courses.GET("/:id/revisions/:version", courseHandler.GetCourseRevision)
+			courses.POST("/:id/revisions/:version/restore", middleware.RequireRole("admin", "editor", "partner"), courseHandler.RestoreCourseRevision)
+			courses.GET("/:id/mentors", courseHandler.GetCourseMentors)
+			courses.PUT("/:id/mentors", middleware.RequireRole("admin", "editor", "partner"), courseHandler.SetCourseMentors)
+			courses.GET("/:id/reviews", reviewHandler.GetCourseReviews)
+			courses.GET("/:id/reviews/mine", reviewHandler.GetMyCourseReview)
+			courses.PUT("/:id/reviews/mine", reviewHandler.SubmitCourseReview)
+			courses.DELETE("/:id/reviews/mine", reviewHandler.DeleteMyCourseReview)
 		}
 
 		posts := v1.Group("/posts")
@@ -125,6 +196,13 @@ func main() {
 			posts.DELETE("/:id", middleware.RequireRole("admin", "editor"), postHandler.DeletePost)
 			posts.PATCH("/:id/approve", middleware.RequireRole("admin", "editor"), postHandler.ApprovePost)
 			posts.PATCH("/:id/reject", middleware.RequireRole("admin", "editor"), postHandler.RejectPost)
+			posts.PATCH("/:id/archive", middleware.RequireRole("admin", "editor"), postHandler.ArchivePost)
+			posts.POST("/:id/transitions", middleware.RequireRole("admin", "editor", "partner"), postHandler.TransitionPost)
+			posts.GET("/:id/history", postHandler.GetPostHistory)
+			posts.GET("/:id/revisions", postHandler.GetPostRevisions)
+			posts.GET("/:id/revisions/diff", postHandler.DiffPostRevisions)
+			posts.GET("/:id/revisions/:version", postHandler.GetPostRevision)
+			posts.POST("/:id/revisions/:version/restore", middleware.RequireRole("admin", "editor", "partner"), postHandler.RestorePostRevision)
 		}
 
 		mentors := v1.Group("/mentors")
@@ -134,7 +212,130 @@ func main() {
 			mentors.POST("", middleware.RequireRole("admin", "editor"), mentorHandler.CreateMentor)
 			mentors.GET("/:id", mentorHandler.GetMentor)
 			mentors.PUT("/:id", middleware.RequireRole("admin", "editor"), mentorHandler.UpdateMentor)
+			mentors.GET("/:id/profile", mentorHandler.GetMentorProfile)
+			mentors.PUT("/:id/profile", middleware.RequireRole("admin", "editor"), mentorHandler.SetMentorProfile)
 			mentors.DELETE("/:id", middleware.RequireRole("admin", "editor"), mentorHandler.DeleteMentor)
+			// The mentor's own account may manage availability; checked by the handler
+			mentors.GET("/:id/availability", bookingHandler.GetAvailability)
+			mentors.PUT("/:id/availability", bookingHandler.SetAvailability)
+			mentors.GET("/:id/slots", bookingHandler.GetSlots)
+			mentors.GET("/:id/calendar", bookingHandler.GetCalendarURL)
+			mentors.POST("/:id/calendar/regenerate", bookingHandler.RegenerateCalendarURL)
+
+			mentors.GET("/:id/reviews", reviewHandler.GetMentorReviews)
+			mentors.GET("/:id/reviews/mine", reviewHandler.GetMyMentorReview)
+			mentors.PUT("/:id/reviews/mine", reviewHandler.SubmitMentorReview)
+			mentors.DELETE("/:id/reviews/mine", reviewHandler.DeleteMyMentorReview)
+		}
+
+		mscers := v1.Group("/mscers")
+		mscers.Use(middleware.RequireAuth(), middleware.RequireRole("admin", "editor"))
+		{
+			mscers.GET("", mscerHandler.GetMSCers)
+			mscers.POST("", mscerHandler.CreateMSCer)
+			mscers.GET("/:id", mscerHandler.GetMSCer)
+			mscers.PUT("/:id", mscerHandler.UpdateMSCer)
+			mscers.DELETE("/:id", mscerHandler.DeleteMSCer)
+		}
+
+		bookings := v1.Group("/bookings")
+		bookings.Use(middleware.RequireAuth())
+		{
+			bookings.POST("", bookingHandler.CreateBooking)
+			bookings.GET("", bookingHandler.GetBookings)
+			bookings.GET("/:id", bookingHandler.GetBooking)
+			bookings.GET("/:id/invite.ics", bookingHandler.GetBookingInvite)
+			bookings.POST("/:id/reschedule", bookingHandler.RescheduleBooking)
+			bookings.POST("/:id/cancel", bookingHandler.CancelBooking)
+		}
+
+		schedule := v1.Group("/schedule")
+		schedule.Use(middleware.RequireAuth())
+		{
+			schedule.GET("", middleware.RequireRole("admin", "editor"), scheduleHandler.GetSchedule)
+		}
+
+		trash := v1.Group("/trash")
+		trash.Use(middleware.RequireAuth())
+		{
+			trash.GET("/:type", middleware.RequireRole("admin", "editor"), trashHandler.GetTrash)
+			trash.POST("/:type/:id/restore", middleware.RequireRole("admin", "editor"), trashHandler.RestoreFromTrash)
+			trash.DELETE("/:type/:id", middleware.RequireRole("admin"), trashHandler.PurgeFromTrash)
+		}
+
+		categories := v1.Group("/categories")
+		categories.Use(middleware.RequireAuth())
+		{
+			categories.GET("", taxonomyHandler.GetCategories)
+			categories.POST("", middleware.RequireRole("admin", "editor"), taxonomyHandler.CreateCategory)
+			categories.PUT("/:id", middleware.RequireRole("admin", "editor"), taxonomyHandler.UpdateCategory)
+			categories.DELETE("/:id", middleware.RequireRole("admin", "editor"), taxonomyHandler.DeleteCategory)
+		}
+
+		catalogs := v1.Group("/catalog")
+		catalogs.Use(middleware.RequireAuth())
+		{
+			catalogs.GET("/:kind/export", middleware.RequireRole("admin", "editor"), catalogHandler.ExportCatalog)
+			catalogs.POST("/:kind/import", middleware.RequireRole("admin"), catalogHandler.ImportCatalog)
+		}
+
+		translations := v1.Group("/translations")
+		translations.Use(middleware.RequireAuth())
+		{
+			translations.GET("/missing", middleware.RequireRole("admin", "editor"), translationHandler.GetMissingTranslations)
+			translations.GET("/:type/:id", middleware.RequireRole("admin", "editor"), translationHandler.GetTranslations)
+			translations.PUT("/:type/:id/:locale", middleware.RequireRole("admin", "editor"), translationHandler.SetTranslations)
+			translations.DELETE("/:type/:id/:locale", middleware.RequireRole("admin", "editor"), translationHandler.DeleteTranslations)
+		}
+
+		tags := v1.Group("/tags")
+		tags.Use(middleware.RequireAuth())
+		{
+			tags.GET("", taxonomyHandler.GetTags)
+			tags.POST("", middleware.RequireRole("admin", "editor"), taxonomyHandler.CreateTag)
+			tags.PUT("/:id", middleware.RequireRole("admin", "editor"), taxonomyHandler.UpdateTag)
+			tags.DELETE("/:id", middleware.RequireRole("admin", "editor"), taxonomyHandler.DeleteTag)
+		}
+
+		taxonomy := v1.Group("/taxonomy")
+		taxonomy.Use(middleware.RequireAuth())
+		{
+			taxonomy.GET("/:type/:id", taxonomyHandler.GetContentTaxonomy)
+			taxonomy.PUT("/:type/:id", middleware.RequireRole("admin", "editor"), taxonomyHandler.SetContentTaxonomy)
+		}
+
+		blogposts := v1.Group("/allblogposts")
+		blogposts.Use(middleware.RequireAuth())
+		{
+			blogposts.POST("/import", middleware.RequireRole("admin"), allBlogPostHandler.ImportLegacyPosts)
+			blogposts.POST("/import/articles", middleware.RequireRole("admin"), articleHandler.ImportArticles)
+		}
+
+		comments := v1.Group("/comments")
+		comments.Use(middleware.RequireAuth(), middleware.RequireRole("admin", "editor"))
+		{
+			comments.GET("", commentHandler.GetComments)
+			comments.PATCH("/:id/approve", commentHandler.ApproveComment)
+			comments.PATCH("/:id/reject", commentHandler.RejectComment)
+			comments.PATCH("/:id/spam", commentHandler.MarkCommentSpam)
+			comments.DELETE("/:id", commentHandler.DeleteComment)
+		}
+
+		reviews := v1.Group("/reviews")
+		reviews.Use(middleware.RequireAuth(), middleware.RequireRole("admin", "editor"))
+		{
+			reviews.GET("", reviewHandler.GetReviews)
+			reviews.PATCH("/:id/approve", reviewHandler.ApproveReview)
+			reviews.PATCH("/:id/reject", reviewHandler.RejectReview)
+			reviews.DELETE("/:id", reviewHandler.DeleteReview)
+		}
+
+		notifications := v1.Group("/notifications")
+		notifications.Use(middleware.RequireAuth())
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.PATCH("/read-all", notificationHandler.MarkAllNotificationsRead)
+			notifications.PATCH("/:id/read", notificationHandler.MarkNotificationRead)
 		}
 
 		users := v1.Group("/users")
@@ -161,6 +362,13 @@ func main() {
 			projects.PUT("/:id", middleware.RequireRole("admin", "editor"), projectHandler.UpdateProject)
 			projects.DELETE("/:id", middleware.RequireRole("admin", "editor"), projectHandler.DeleteProject)
 		}
+
+		programs := v1.Group("/programs")
+		programs.Use(middleware.RequireAuth())
+		{
+			programs.GET("/:id/mentors", programHandler.GetProgramMentors)
+			programs.PUT("/:id/mentors", middleware.RequireRole("admin", "editor"), programHandler.SetProgramMentors)
+		}
 	}
 
 	// -------- API (public + auth) --------
@@ -171,6 +379,8 @@ func main() {
 			allblogposts.GET("", allBlogPostHandler.GetAllBlogPosts)
 			allblogposts.GET("/:id", allBlogPostHandler.GetBlogPostByID)
 			allblogposts.GET("/slug/:slug", allBlogPostHandler.GetBlogPostBySlug)
+			allblogposts.GET("/:id/comments", commentHandler.GetBlogPostComments)
+			allblogposts.POST("/:id/comments", middleware.RequireAuth(), commentHandler.CreateComment)
 		}
 
 		projects := api.Group("/projects")
@@ -180,12 +390,53 @@ func main() {
 			projects.GET("/slug/:slug", projectHandler.GetProjectBySlug)
 		}
 
+		publicMentors := api.Group("/mentors")
+		{
+			publicMentors.GET("", mentorHandler.GetPublicMentors)
+			publicMentors.GET("/slug/:slug", mentorHandler.GetMentorBySlug)
+			publicMentors.GET("/:id/calendar.ics", bookingHandler.GetMentorCalendar)
+			publicMentors.GET("/:id/reviews", reviewHandler.GetPublicMentorReviews)
+		}
+
+		publicMSCers := api.Group("/mscers")
+		{
+			publicMSCers.GET("", mscerHandler.GetPublicMSCers)
+			publicMSCers.GET("/slug/:slug", mscerHandler.GetMSCerBySlug)
+		}
+
 		programs := api.Group("/programs")
 		{
 			programs.GET("", programHandler.GetPrograms)
 			programs.GET("/:id", programHandler.GetProgramByID)
 		}
 
+		engagements := api.Group("/engagement")
+		engagements.Use(middleware.OptionalAuth())
+		{
+			engagements.GET("/:type/:id", engagementHandler.GetEngagement)
+			engagements.POST("/:type/:id/view", engagementHandler.RecordView)
+			engagements.POST("/:type/:id/like", engagementHandler.Like)
+			engagements.DELETE("/:type/:id/like", engagementHandler.Unlike)
+		}
+
+		feeds := api.Group("/feeds")
+		{
+			feeds.GET("/blog.rss", feedHandler.BlogRSS)
+			feeds.GET("/blog.atom", feedHandler.BlogAtom)
+			feeds.GET("/blog.json", feedHandler.BlogJSON)
+		}
+
+		api.GET("/redirects/:type/:slug", redirectHandler.ResolveSlug)
+
+		api.GET("/categories", taxonomyHandler.GetCategories)
+		api.GET("/tags", taxonomyHandler.GetTags)
+
+		search := api.Group("/search")
+		{
+			search.GET("", searchHandler.Search)
+			search.GET("/suggest", searchHandler.Suggest)
+		}
+
 		auth := api.Group("/auth")
 		{
 			auth.POST("/register", userHandler.RegisterUser)
@@ -220,13 +471,43 @@ func main() {
 	})
 	r.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Uploaded files
+	r.Static("/uploads", uploadStore.Dir())
+
+	// SEO; the frontend proxies these paths so crawlers find them on the public site
+	r.GET("/sitemap.xml", sitemapHandler.SitemapIndex)
+	r.GET("/sitemaps/:file", sitemapHandler.Sitemap)
+	r.GET("/robots.txt", sitemapHandler.Robots)
+
 	// Start
 	port := cfg.Port
 	if port == "" {
 		port = "8080"
 	}
-	log.Printf("Server running at http://localhost:%s", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatal("Server failed to start: ", err)
+	server := &http.Server{Addr: ":" + port, Handler: r}
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server running at http://localhost:%s", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server failed to start: ", err)
+		}
+	case <-signalCtx.Done():
+		log.Println("Shutting down...")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("HTTP shutdown incomplete: %v", err)
+		}
 	}
+
+	// Stop the background jobs; the tracker flushes its buffered counters on the way out
+	stopJobs()
+	jobs.Wait()
+	mail.Wait()
+	log.Println("Server stopped")
 }
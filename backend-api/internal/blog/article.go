@@ -0,0 +1,388 @@
+package blog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/revisions"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/uploads"
+	"msc-backend-api/pkg/markdown"
+	"msc-backend-api/pkg/slug"
+	"msc-backend-api/pkg/tiptap"
+	"msc-backend-api/pkg/workflow"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidArticle is returned for an article file that cannot be imported
+var ErrInvalidArticle = errors.New("invalid article")
+
+// dateLayouts are the publish date formats accepted in front matter
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02", "02/01/2006"}
+
+// Article is a Markdown or MDX article with YAML front matter, as kept in the frontend's
+// data/chia-se directory
+type Article struct {
+	File        string       `json:"file"`
+	Title       string       `json:"title"`
+	Slug        string       `json:"slug"`
+	Excerpt     string       `json:"excerpt,omitempty"`
+	Author      string       `json:"author,omitempty"`
+	Category    string       `json:"category,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Image       string       `json:"image,omitempty"`
+	PublishDate *time.Time   `json:"publish_date,omitempty"`
+	Doc         *tiptap.Node `json:"-"`
+}
+
+type frontMatter struct {
+	ID          string   `yaml:"id"`
+	Slug        string   `yaml:"slug"`
+	Title       string   `yaml:"title"`
+	Excerpt     string   `yaml:"excerpt"`
+	Description string   `yaml:"description"`
+	Author      string   `yaml:"author"`
+	Category    string   `yaml:"category"`
+	Tags        []string `yaml:"tags"`
+	Image       string   `yaml:"image"`
+	Cover       string   `yaml:"cover"`
+	PublishDate string   `yaml:"publishDate"`
+	Date        string   `yaml:"date"`
+}
+
+// ParseArticle reads an article file. The slug comes from the slug or id field, then
+// the title, then the file name; the body becomes Tiptap content. Image references are
+// kept as written until RewriteImages replaces them.
+func ParseArticle(name string, src []byte) (*Article, error) {
+	front, body := markdown.SplitFrontMatter(src)
+	var meta frontMatter
+	if front != nil {
+		if err := yaml.Unmarshal(front, &meta); err != nil {
+			return nil, fmt.Errorf("%w: front matter: %v", ErrInvalidArticle, err)
+		}
+	}
+
+	article := &Article{
+		File:     name,
+		Title:    strings.TrimSpace(meta.Title),
+		Excerpt:  strings.TrimSpace(firstNonEmpty(meta.Excerpt, meta.Description)),
+		Author:   strings.TrimSpace(meta.Author),
+		Category: strings.TrimSpace(meta.Category),
+		Tags:     meta.Tags,
+		Image:    strings.TrimSpace(firstNonEmpty(meta.Image, meta.Cover)),
+		Doc:      markdown.Parse(string(body)),
+	}
+	if article.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidArticle)
+	}
+	// The Markdown parser nests as deep as the file does; reject what the renderer would
+	// refuse once the post is synced onto the blog
+	content, err := json.Marshal(article.Doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := tiptap.Validate(string(content)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArticle, err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	for _, candidate := range []string{meta.Slug, meta.ID, article.Title, base} {
+		if article.Slug = slug.Make(candidate); article.Slug != "" {
+			break
+		}
+	}
+	if article.Slug == "" {
+		return nil, fmt.Errorf("%w: title or slug must contain letters or digits", ErrInvalidArticle)
+	}
+
+	if date := strings.TrimSpace(firstNonEmpty(meta.PublishDate, meta.Date)); date != "" {
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, date); err == nil {
+				article.PublishDate = &t
+				break
+			}
+		}
+		if article.PublishDate == nil {
+			return nil, fmt.Errorf("%w: publish date %q is not a date", ErrInvalidArticle, date)
+		}
+	}
+	return article, nil
+}
+
+// LocalImages returns the image references of the article that point at files rather
+// than at URLs, cover image first, without duplicates
+func (a *Article) LocalImages() []string {
+	var refs []string
+	seen := map[string]bool{}
+	add := func(ref string) string {
+		if IsLocalImage(ref) && !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+		return ref
+	}
+	add(a.Image)
+	markdown.Images(a.Doc, add)
+	return refs
+}
+
+// RewriteImages replaces image references by the URLs they were uploaded to.
+// References missing from uploaded are kept.
+func (a *Article) RewriteImages(uploaded map[string]string) {
+	replace := func(ref string) string {
+		if url, ok := uploaded[ref]; ok {
+			return url
+		}
+		return ref
+	}
+	a.Image = replace(a.Image)
+	markdown.Images(a.Doc, replace)
+}
+
+// IsLocalImage reports whether an image reference is a path rather than a URL.
+// Site-relative paths such as /images/a.jpg count as local; they name files in the
+// frontend's public directory.
+func IsLocalImage(ref string) bool {
+	lower := strings.ToLower(ref)
+	if ref == "" || strings.HasPrefix(lower, "//") {
+		return false
+	}
+	for _, scheme := range []string{"http:", "https:", "data:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return false
+		}
+	}
+	return true
+}
+
+// ImagePath cleans a local image reference into a slash-separated path without a
+// leading ./ or /, dropping any query or fragment
+func ImagePath(ref string) string {
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	return strings.TrimPrefix(path.Clean("/"+ref), "/")
+}
+
+// ImageOpener opens the file behind a local image reference of an article. The name it
+// returns identifies the file, so an image referenced by several articles, or by one
+// article under different paths, is stored once.
+type ImageOpener func(ref string) (name string, f io.ReadCloser, err error)
+
+// ImageUploader stores the local images of the articles of one import
+type ImageUploader struct {
+	store  *uploads.Store
+	stored map[string]storedImage // by the name the opener returned
+}
+
+type storedImage struct {
+	url  string
+	name string // stored file name, used to remove the file again
+}
+
+func NewImageUploader(store *uploads.Store) *ImageUploader {
+	return &ImageUploader{store: store, stored: map[string]storedImage{}}
+}
+
+// Upload stores the local images of an article that are not stored yet and points the
+// article at their URLs. Images that cannot be opened or stored keep their reference and
+// are returned as warnings. undo removes the files this call stored; call it when the
+// article is not imported so a failed import leaves no orphaned uploads.
+func (u *ImageUploader) Upload(article *Article, open ImageOpener) (warnings []string, undo func()) {
+	var added []string
+	uploaded := map[string]string{}
+	for _, ref := range article.LocalImages() {
+		name, f, err := open(ref)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("image %s was not found and is kept as written", ref))
+			continue
+		}
+		if image, ok := u.stored[name]; ok {
+			f.Close()
+			uploaded[ref] = image.url
+			continue
+		}
+		url, storedName, err := u.store.Save(f, "image", ImagePath(ref))
+		f.Close()
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("image %s was not uploaded: %v", ref, err))
+			continue
+		}
+		u.stored[name] = storedImage{url: url, name: storedName}
+		added = append(added, name)
+		uploaded[ref] = url
+	}
+	article.RewriteImages(uploaded)
+
+	return warnings, func() {
+		for _, name := range added {
+			if err := u.store.Remove("image", u.stored[name].name); err != nil {
+				log.Printf("Failed to remove image %s of a failed import: %v", u.stored[name].name, err)
+			}
+			delete(u.stored, name)
+		}
+	}
+}
+
+// ArticleResult describes one article of an import. Error is set when the article was
+// not imported.
+type ArticleResult struct {
+	File     string   `json:"file"`
+	Slug     string   `json:"slug,omitempty"`
+	PostID   string   `json:"post_id,omitempty"`
+	BlogID   uint     `json:"blog_id,omitempty"`
+	Created  bool     `json:"created"`
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// ImportArticle stores an article as a published post, inserting or updating by slug,
+// and syncs it onto the public blog. Only a published post is updated: a post with the
+// slug in any other state is left to the workflow and the article is refused. An
+// existing blog row with the slug keeps its id, views and likes; a legacy row is linked
+// to the new post. The post's category and tags
+// are replaced by those of the article, and the byline and publish date are set from the
+// front matter when it has them. authorID owns posts the import creates.
+func ImportArticle(db *gorm.DB, article *Article, authorID uuid.UUID) (*ArticleResult, error) {
+	content, err := json.Marshal(article.Doc)
+	if err != nil {
+		return nil, err
+	}
+	result := &ArticleResult{File: article.File, Slug: article.Slug}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := slug.Lock(tx, "posts", article.Slug); err != nil {
+			return err
+		}
+
+		var entry models.AllBlogPost
+		err := tx.Unscoped().Where("slug = ?", article.Slug).First(&entry).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		legacy := err == nil && entry.PostID == nil
+
+		var post models.Post
+		query := tx.Unscoped().Where("slug = ?", article.Slug)
+		if err == nil && entry.PostID != nil {
+			query = tx.Unscoped().Where("id = ?", *entry.PostID)
+		}
+		err = query.First(&post).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		result.Created = errors.Is(err, gorm.ErrRecordNotFound)
+		if post.DeletedAt.Valid {
+			return fmt.Errorf("%w: slug %q belongs to a post in the trash", ErrInvalidArticle, article.Slug)
+		}
+		if !result.Created && post.Status != workflow.StatusPublished {
+			return fmt.Errorf("%w: slug %q belongs to a post that is %s", ErrInvalidArticle, article.Slug, post.Status)
+		}
+
+		post.Title = article.Title
+		post.Slug = article.Slug
+		post.Content = string(content)
+		post.Excerpt = article.Excerpt
+		post.ThumbnailURL = article.Image
+		post.Status = workflow.StatusPublished
+		if result.Created {
+			post.ID = uuid.New()
+			post.AuthorID = authorID
+			if article.PublishDate != nil {
+				post.CreatedAt = *article.PublishDate
+			}
+			if err := tx.Create(&post).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Save(&post).Error; err != nil {
+			return err
+		}
+		result.PostID = post.ID.String()
+
+		if _, err := revisions.Save(tx, models.EntityTypePost, post.ID, authorID, post.Snapshot(), nil); err != nil {
+			return err
+		}
+		if result.Created {
+			if err := tx.Create(&models.StatusTransition{
+				EntityType: models.EntityTypePost,
+				EntityID:   post.ID,
+				Action:     workflow.ActionPublish,
+				FromStatus: workflow.StatusDraft,
+				ToStatus:   workflow.StatusPublished,
+				ActorID:    authorID,
+				Reason:     "Imported from " + filepath.Base(article.File),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := setArticleTaxonomy(tx, post.ID, article); err != nil {
+			return err
+		}
+
+		if legacy {
+			if err := tx.Model(&entry).Update("post_id", post.ID).Error; err != nil {
+				return err
+			}
+		}
+		if err := SyncPost(tx, post.ID); err != nil {
+			return err
+		}
+
+		if err := tx.Where("post_id = ?", post.ID).First(&entry).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{}
+		if article.Author != "" {
+			updates["author"] = article.Author
+		}
+		if article.PublishDate != nil {
+			updates["publish_date"] = *article.PublishDate
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&entry).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		result.BlogID = entry.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// setArticleTaxonomy links the post to the article's category, created when missing,
+// and tags
+func setArticleTaxonomy(tx *gorm.DB, postID uuid.UUID, article *Article) error {
+	req := models.SetTaxonomyRequest{Tags: article.Tags}
+	if s := slug.Make(article.Category); s != "" {
+		category := models.Category{Name: article.Category, Slug: s}
+		if err := tx.Where("slug = ?", s).FirstOrCreate(&category).Error; err != nil {
+			return err
+		}
+		req.CategoryIDs = []uuid.UUID{category.ID}
+	}
+	return taxonomy.Set(tx, taxonomy.Posts, postID.String(), req)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
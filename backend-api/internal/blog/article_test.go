@@ -0,0 +1,53 @@
+package blog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseArticle(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		src      string
+		wantSlug string
+		wantErr  bool
+	}{
+		{"slug from front matter", "a.md", "---\ntitle: Tiêu đề\nslug: Bai Viet\n---\nbody", "bai-viet", false},
+		{"slug from id", "a.md", "---\ntitle: Tiêu đề\nid: bai-1\n---\nbody", "bai-1", false},
+		{"slug from title", "a.md", "---\ntitle: Khóa học Đầu tư\n---\nbody", "khoa-hoc-dau-tu", false},
+		{"slug from file name", "bai-viet.mdx", "---\ntitle: \"!!!\"\n---\nbody", "bai-viet", false},
+		{"title required", "a.md", "body", "", true},
+		{"bad date", "a.md", "---\ntitle: A\ndate: tomorrow\n---\n", "", true},
+		{"bad front matter", "a.md", "---\ntitle: [\n---\n", "", true},
+		{"nested too deep", "a.md", "---\ntitle: A\n---\n" + strings.Repeat(">", 100) + " deep", "", true},
+		{"deep list", "a.md", "---\ntitle: A\n---\n" + deepList(40), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article, err := ParseArticle(tt.file, []byte(tt.src))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidArticle) {
+					t.Fatalf("ParseArticle() error = %v, want ErrInvalidArticle", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseArticle() error = %v", err)
+			}
+			if article.Slug != tt.wantSlug {
+				t.Errorf("slug = %q, want %q", article.Slug, tt.wantSlug)
+			}
+		})
+	}
+}
+
+// deepList returns bullet lists nested depth levels deep
+func deepList(depth int) string {
+	var b strings.Builder
+	for i := 0; i < depth; i++ {
+		b.WriteString(strings.Repeat("  ", i) + "- item\n")
+	}
+	return b.String()
+}
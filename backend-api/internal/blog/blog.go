@@ -0,0 +1,272 @@
+// Package blog keeps the public blog table (allblogposts) in step with the posts that
+// editors manage through the post workflow.
+//
+// posts is the source of truth. Every published post has exactly one allblogposts row,
+// linked by post_id, that serves the unchanged /api/allblogposts response shape
+// (numeric id, views and likes included). Rows without a post_id are legacy content;
+// ImportLegacy turns them into posts so the two models converge.
+package blog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/comments"
+	"msc-backend-api/internal/engagement"
+	"msc-backend-api/internal/i18n"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/redirects"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/pkg/tiptap"
+	"msc-backend-api/pkg/workflow"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// wordsPerMinute is the reading speed behind read_time
+const wordsPerMinute = 200
+
+// emptyDoc is the Tiptap content of imported posts
+const emptyDoc = `{"type":"doc","content":[]}`
+
+// SyncPost projects a post onto its allblogposts row. Published posts are inserted or
+// updated; anything else (draft, archived, trashed) hides the row with a soft delete so
+// its id, views and likes survive a later re-publish. A purged post removes the row.
+//
+// The byline is set from the post author when the row is created and kept afterwards,
+// so imported legacy posts keep their original author. details_blog and read_time are
+// only rewritten when the post has Tiptap content.
+func SyncPost(tx *gorm.DB, postID uuid.UUID) error {
+	var entry models.AllBlogPost
+	err := tx.Unscoped().Where("post_id = ?", postID).First(&entry).Error
+	found := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var post models.Post
+	if err := tx.Unscoped().Preload("Author").Where("id = ?", postID).First(&post).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if !found {
+			return nil
+		}
+		if err := taxonomy.Unlink(tx, models.EntityTypeBlogPost, []string{entryID(entry)}); err != nil {
+			return err
+		}
+		if err := engagement.Unlink(tx, models.EntityTypeBlogPost, []string{entryID(entry)}); err != nil {
+			return err
+		}
+		if err := comments.Unlink(tx, []string{entryID(entry)}); err != nil {
+			return err
+		}
+		if err := redirects.Unlink(tx, models.EntityTypeBlogPost, []string{entryID(entry)}); err != nil {
+			return err
+		}
+		if err := i18n.Unlink(tx, models.EntityTypeBlogPost, []string{entryID(entry)}); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&entry).Error
+	}
+
+	if post.Status != workflow.StatusPublished || post.DeletedAt.Valid {
+		if !found || entry.DeletedAt.Valid {
+			return nil
+		}
+		return tx.Delete(&entry).Error
+	}
+
+	if !found {
+		entry.Author = post.Author.Name
+		entry.PublishDate = time.Now()
+		if post.PublishAt != nil {
+			entry.PublishDate = *post.PublishAt
+		}
+	}
+	oldSlug := entry.Slug
+	entry.PostID = &post.ID
+	entry.Slug = post.Slug
+	entry.Title = post.Title
+	entry.Excerpt = post.Excerpt
+	entry.Image = post.ThumbnailURL
+	entry.DeletedAt = gorm.DeletedAt{}
+
+	if doc, err := tiptap.Parse(post.Content); err == nil && len(doc.Content) > 0 {
+		entry.DetailsBlog = tiptap.HTML(doc)
+		entry.ReadTime = ReadTime(tiptap.Text(doc))
+	}
+
+	// The post's first category is the one the public blog shows
+	taxonomies, err := taxonomy.Load(tx, taxonomy.Posts, []string{post.ID.String()})
+	if err != nil {
+		return err
+	}
+	if categories := taxonomies[post.ID.String()].Categories; len(categories) > 0 {
+		entry.Category = categories[0].Name
+	}
+
+	if err := tx.Unscoped().Save(&entry).Error; err != nil {
+		return err
+	}
+	// Links to the entry's old slug keep working
+	if err := redirects.Record(tx, models.EntityTypeBlogPost, entryID(entry), oldSlug, entry.Slug); err != nil {
+		return err
+	}
+	return mirrorTaxonomy(tx, post.ID, entryID(entry))
+}
+
+// mirrorTaxonomy copies the category and tag links of a post onto its blog row so the
+// /api/allblogposts filters and facets match what editors set on the post
+func mirrorTaxonomy(tx *gorm.DB, postID uuid.UUID, blogID string) error {
+	if err := taxonomy.Unlink(tx, models.EntityTypeBlogPost, []string{blogID}); err != nil {
+		return err
+	}
+	if err := tx.Exec(`INSERT INTO content_categories (entity_type, entity_id, category_id)
+		SELECT ?, ?, category_id FROM content_categories WHERE entity_type = ? AND entity_id = ?`,
+		models.EntityTypeBlogPost, blogID, models.EntityTypePost, postID.String()).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`INSERT INTO content_tags (entity_type, entity_id, tag_id)
+		SELECT ?, ?, tag_id FROM content_tags WHERE entity_type = ? AND entity_id = ?`,
+		models.EntityTypeBlogPost, blogID, models.EntityTypePost, postID.String()).Error
+}
+
+// errSlugTaken marks a legacy row whose slug already belongs to a post
+var errSlugTaken = errors.New("slug is already used by a post")
+
+// LegacyImport is the outcome of ImportLegacy
+type LegacyImport struct {
+	Imported int            `json:"imported"`
+	Skipped  []SkippedEntry `json:"skipped"`
+}
+
+// SkippedEntry is a legacy row ImportLegacy left alone, with the reason
+type SkippedEntry struct {
+	ID      uint   `json:"id"`
+	Slug    string `json:"slug"`
+	Message string `json:"message"`
+}
+
+// ImportLegacy creates a published post for every allblogposts row that has no post yet
+// and links the two. authorID owns the new posts; the public byline is unchanged.
+// Post content starts empty because legacy details_blog is not Tiptap JSON, and the
+// public body is kept until an editor writes new content.
+//
+// Each row is imported in its own transaction. Rows whose slug is already used by a
+// post are skipped and reported rather than stopping the import, so fixing the slug
+// and running it again picks up exactly the rows that are left.
+func ImportLegacy(db *gorm.DB, authorID uuid.UUID) (LegacyImport, error) {
+	result := LegacyImport{Skipped: []SkippedEntry{}}
+
+	var legacy []models.AllBlogPost
+	if err := db.Where("post_id IS NULL").Order("id").Find(&legacy).Error; err != nil {
+		return result, err
+	}
+
+	for _, entry := range legacy {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var taken int64
+			if err := tx.Unscoped().Model(&models.Post{}).Where("slug = ?", entry.Slug).Count(&taken).Error; err != nil {
+				return err
+			}
+			if taken > 0 {
+				return errSlugTaken
+			}
+
+			publishedAt := entry.PublishDate
+			if publishedAt.IsZero() {
+				publishedAt = time.Now()
+			}
+			post := models.Post{
+				Title:        entry.Title,
+				Slug:         entry.Slug,
+				Content:      emptyDoc,
+				Excerpt:      entry.Excerpt,
+				ThumbnailURL: entry.Image,
+				Status:       workflow.StatusPublished,
+				AuthorID:     authorID,
+			}
+			post.ID = uuid.New()
+			post.CreatedAt = publishedAt
+			if err := tx.Create(&post).Error; err != nil {
+				return err
+			}
+			snapshot, err := json.Marshal(post.Snapshot())
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(&models.Revision{
+				EntityType: models.EntityTypePost,
+				EntityID:   post.ID,
+				Version:    1,
+				AuthorID:   authorID,
+				Snapshot:   string(snapshot),
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.StatusTransition{
+				EntityType: models.EntityTypePost,
+				EntityID:   post.ID,
+				Action:     workflow.ActionPublish,
+				FromStatus: workflow.StatusDraft,
+				ToStatus:   workflow.StatusPublished,
+				ActorID:    authorID,
+				Reason:     "Imported from allblogposts #" + entryID(entry),
+			}).Error; err != nil {
+				return err
+			}
+
+			// The blog row's categories and tags become the post's
+			if err := tx.Exec(`INSERT INTO content_categories (entity_type, entity_id, category_id)
+				SELECT ?, ?, category_id FROM content_categories WHERE entity_type = ? AND entity_id = ?`,
+				models.EntityTypePost, post.ID.String(), models.EntityTypeBlogPost, entryID(entry)).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`INSERT INTO content_tags (entity_type, entity_id, tag_id)
+				SELECT ?, ?, tag_id FROM content_tags WHERE entity_type = ? AND entity_id = ?`,
+				models.EntityTypePost, post.ID.String(), models.EntityTypeBlogPost, entryID(entry)).Error; err != nil {
+				return err
+			}
+
+			return tx.Model(&entry).Update("post_id", post.ID).Error
+		})
+		if errors.Is(err, errSlugTaken) {
+			result.Skipped = append(result.Skipped, SkippedEntry{ID: entry.ID, Slug: entry.Slug, Message: err.Error()})
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("import allblogposts #%d: %w", entry.ID, err)
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// SlugTaken reports whether a public blog row other than the post's own uses slug.
+// Pass uuid.Nil for a post that does not exist yet.
+func SlugTaken(db *gorm.DB, slug string, postID uuid.UUID) (bool, error) {
+	var count int64
+	err := db.Unscoped().Model(&models.AllBlogPost{}).
+		Where("slug = ? AND (post_id IS NULL OR post_id <> ?)", slug, postID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ReadTime estimates the reading time of plain text in the format the blog uses
+func ReadTime(text string) string {
+	minutes := (len(strings.Fields(text)) + wordsPerMinute - 1) / wordsPerMinute
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("%d phút đọc", minutes)
+}
+
+func entryID(entry models.AllBlogPost) string {
+	return strconv.FormatUint(uint64(entry.ID), 10)
+}
@@ -0,0 +1,193 @@
+// Package booking lets users book sessions with mentors. Mentors publish weekly
+// availability in their own time zone; the open slots of a period are derived from it
+// and from the sessions already booked. Booking, rescheduling and cancelling happen in
+// transactions that lock the mentor, so two users can never take the same slot.
+package booking
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // time zones also resolve on hosts without a zoneinfo database
+
+	"msc-backend-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidAvailability is returned for an availability window that cannot be used
+var ErrInvalidAvailability = errors.New("invalid availability")
+
+// Limits of the length of one session
+const (
+	MinSlotMinutes     = 15
+	MaxSlotMinutes     = 8 * 60
+	DefaultSlotMinutes = 60
+)
+
+// LoadAvailability returns a mentor's weekly availability ordered by day and time
+func LoadAvailability(db *gorm.DB, mentorID uuid.UUID) ([]models.MentorAvailability, error) {
+	availability := []models.MentorAvailability{}
+	err := db.Where("mentor_id = ?", mentorID).Order("weekday, start_time").Find(&availability).Error
+	return availability, err
+}
+
+// SetAvailability replaces a mentor's weekly availability. Sessions already booked are
+// kept. It returns gorm.ErrRecordNotFound when the mentor does not exist and
+// ErrInvalidAvailability when a window is malformed.
+func SetAvailability(db *gorm.DB, mentorID uuid.UUID, availability []models.MentorAvailability) error {
+	for i := range availability {
+		if err := normalize(&availability[i], mentorID, i); err != nil {
+			return err
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Model(&models.Mentor{}).Where("id = ?", mentorID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("mentor_id = ?", mentorID).Delete(&models.MentorAvailability{}).Error; err != nil {
+			return err
+		}
+		if len(availability) == 0 {
+			return nil
+		}
+		return tx.Create(&availability).Error
+	})
+}
+
+// normalize checks one availability window and rewrites its times as "HH:MM"
+func normalize(a *models.MentorAvailability, mentorID uuid.UUID, index int) error {
+	a.ID = uuid.New()
+	a.MentorID = mentorID
+	a.Timezone = strings.TrimSpace(a.Timezone)
+
+	if a.Weekday < 0 || a.Weekday > 6 {
+		return invalid(index, "weekday must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if _, err := time.LoadLocation(a.Timezone); err != nil || a.Timezone == "" {
+		return invalid(index, fmt.Sprintf("unknown timezone %q", a.Timezone))
+	}
+	if a.SlotMinutes == 0 {
+		a.SlotMinutes = DefaultSlotMinutes
+	}
+	if a.SlotMinutes < MinSlotMinutes || a.SlotMinutes > MaxSlotMinutes {
+		return invalid(index, fmt.Sprintf("slot_minutes must be between %d and %d", MinSlotMinutes, MaxSlotMinutes))
+	}
+
+	start, ok := parseClock(a.StartTime)
+	if !ok {
+		return invalid(index, "start_time must be HH:MM")
+	}
+	end, ok := parseClock(a.EndTime)
+	if !ok {
+		return invalid(index, "end_time must be HH:MM")
+	}
+	if end-start < a.SlotMinutes {
+		return invalid(index, "the window must be at least one slot long")
+	}
+	a.StartTime, a.EndTime = formatClock(start), formatClock(end)
+	return nil
+}
+
+func invalid(index int, reason string) error {
+	return fmt.Errorf("%w: entry %d: %s", ErrInvalidAvailability, index+1, reason)
+}
+
+// parseClock returns the minutes since midnight of an "HH:MM" time. "24:00" is accepted
+// as the end of the day.
+func parseClock(s string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * 60, true
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Slots returns the open slots of a mentor starting in [from, to): slots of the weekly
+// availability that are in the future and do not overlap a confirmed booking
+func Slots(db *gorm.DB, mentorID uuid.UUID, from, to time.Time) ([]models.Slot, error) {
+	availability, err := LoadAvailability(db, mentorID)
+	if err != nil {
+		return nil, err
+	}
+	var booked []models.Booking
+	if err := db.Where("mentor_id = ? AND status = ? AND starts_at < ? AND ends_at > ?",
+		mentorID, models.BookingStatusConfirmed, to.Add(MaxSlotMinutes*time.Minute), from).
+		Find(&booked).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	open := []models.Slot{}
+	for _, slot := range expand(availability, from, to) {
+		if !slot.StartsAt.After(now) {
+			continue
+		}
+		free := true
+		for _, b := range booked {
+			if b.StartsAt.Before(slot.EndsAt) && b.EndsAt.After(slot.StartsAt) {
+				free = false
+				break
+			}
+		}
+		if free {
+			open = append(open, slot)
+		}
+	}
+	return open, nil
+}
+
+// expand lists the slots of the weekly availability starting in [from, to), in time
+// order. A start offered by several windows is listed once.
+func expand(availability []models.MentorAvailability, from, to time.Time) []models.Slot {
+	seen := map[int64]bool{}
+	var slots []models.Slot
+	for _, a := range availability {
+		loc, err := time.LoadLocation(a.Timezone)
+		if err != nil {
+			continue
+		}
+		start, ok1 := parseClock(a.StartTime)
+		end, ok2 := parseClock(a.EndTime)
+		if !ok1 || !ok2 || a.SlotMinutes <= 0 {
+			continue
+		}
+		length := time.Duration(a.SlotMinutes) * time.Minute
+
+		// Walk the local days of the period, one extra on each side for zone offsets
+		first := from.In(loc)
+		day := time.Date(first.Year(), first.Month(), first.Day()-1, 0, 0, 0, 0, loc)
+		for ; day.Before(to.In(loc).AddDate(0, 0, 1)); day = day.AddDate(0, 0, 1) {
+			if int(day.Weekday()) != a.Weekday {
+				continue
+			}
+			for m := start; m+a.SlotMinutes <= end; m += a.SlotMinutes {
+				s := time.Date(day.Year(), day.Month(), day.Day(), m/60, m%60, 0, 0, loc)
+				if s.Before(from) || !s.Before(to) || seen[s.Unix()] {
+					continue
+				}
+				seen[s.Unix()] = true
+				slots = append(slots, models.Slot{StartsAt: s.UTC(), EndsAt: s.Add(length).UTC(), Timezone: a.Timezone})
+			}
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].StartsAt.Before(slots[j].StartsAt) })
+	return slots
+}
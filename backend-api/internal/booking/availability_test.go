@@ -0,0 +1,138 @@
+package booking
+
+import (
+	"testing"
+	"time"
+
+	"msc-backend-api/internal/models"
+)
+
+func TestExpand(t *testing.T) {
+	window := func(weekday int, start, end, timezone string, minutes int) models.MentorAvailability {
+		return models.MentorAvailability{Weekday: weekday, StartTime: start, EndTime: end, Timezone: timezone, SlotMinutes: minutes}
+	}
+	utc := func(s string) time.Time {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	tests := []struct {
+		name         string
+		availability []models.MentorAvailability
+		from, to     string
+		want         []string // slot starts in UTC
+	}{
+		{
+			name:         "slots of one window",
+			availability: []models.MentorAvailability{window(2, "09:00", "12:00", "UTC", 60)},
+			from:         "2026-10-19T00:00:00Z", to: "2026-10-26T00:00:00Z",
+			want: []string{"2026-10-20T09:00:00Z", "2026-10-20T10:00:00Z", "2026-10-20T11:00:00Z"},
+		},
+		{
+			name:         "a slot must fit in the window",
+			availability: []models.MentorAvailability{window(2, "09:00", "10:30", "UTC", 60)},
+			from:         "2026-10-19T00:00:00Z", to: "2026-10-26T00:00:00Z",
+			want: []string{"2026-10-20T09:00:00Z"},
+		},
+		{
+			name:         "from is inclusive and to exclusive",
+			availability: []models.MentorAvailability{window(2, "09:00", "12:00", "UTC", 60)},
+			from:         "2026-10-20T10:00:00Z", to: "2026-10-20T11:00:00Z",
+			want: []string{"2026-10-20T10:00:00Z"},
+		},
+		{
+			name:         "every week of the period",
+			availability: []models.MentorAvailability{window(2, "09:00", "10:00", "UTC", 60)},
+			from:         "2026-10-19T00:00:00Z", to: "2026-11-02T00:00:00Z",
+			want: []string{"2026-10-20T09:00:00Z", "2026-10-27T09:00:00Z"},
+		},
+		{
+			name:         "local Monday morning is Sunday in UTC",
+			availability: []models.MentorAvailability{window(1, "00:00", "01:00", "Asia/Ho_Chi_Minh", 30)},
+			from:         "2026-10-18T00:00:00Z", to: "2026-10-19T00:00:00Z",
+			want: []string{"2026-10-18T17:00:00Z", "2026-10-18T17:30:00Z"},
+		},
+		{
+			name:         "local Friday night is Saturday in UTC",
+			availability: []models.MentorAvailability{window(5, "22:00", "24:00", "America/Los_Angeles", 60)},
+			from:         "2026-10-24T00:00:00Z", to: "2026-10-25T00:00:00Z",
+			want: []string{"2026-10-24T05:00:00Z", "2026-10-24T06:00:00Z"},
+		},
+		{
+			name:         "wall-clock time kept across the autumn change",
+			availability: []models.MentorAvailability{window(2, "09:00", "10:00", "Europe/Berlin", 60)},
+			from:         "2026-10-19T00:00:00Z", to: "2026-11-02T00:00:00Z",
+			want: []string{"2026-10-20T07:00:00Z", "2026-10-27T08:00:00Z"},
+		},
+		{
+			name:         "skipped spring hour gives no extra slot",
+			availability: []models.MentorAvailability{window(0, "01:00", "04:00", "Europe/Berlin", 60)},
+			from:         "2026-03-29T00:00:00Z", to: "2026-03-30T00:00:00Z",
+			want: []string{"2026-03-29T00:00:00Z", "2026-03-29T01:00:00Z"},
+		},
+		{
+			name: "overlapping windows list a start once, in time order",
+			availability: []models.MentorAvailability{
+				window(2, "10:00", "12:00", "UTC", 60),
+				window(2, "09:00", "11:00", "UTC", 60),
+			},
+			from: "2026-10-19T00:00:00Z", to: "2026-10-26T00:00:00Z",
+			want: []string{"2026-10-20T09:00:00Z", "2026-10-20T10:00:00Z", "2026-10-20T11:00:00Z"},
+		},
+		{
+			name: "unusable windows are skipped",
+			availability: []models.MentorAvailability{
+				window(2, "09:00", "10:00", "Mars/Olympus", 60),
+				window(2, "9am", "10:00", "UTC", 60),
+				window(2, "09:00", "10:00", "UTC", 0),
+			},
+			from: "2026-10-19T00:00:00Z", to: "2026-10-26T00:00:00Z",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := expand(tt.availability, utc(tt.from), utc(tt.to))
+			if len(slots) != len(tt.want) {
+				t.Fatalf("got %d slots %v, want %v", len(slots), slots, tt.want)
+			}
+			for i, slot := range slots {
+				if got := slot.StartsAt.Format(time.RFC3339); got != tt.want[i] {
+					t.Errorf("slot %d starts at %s, want %s", i, got, tt.want[i])
+				}
+				if length := slot.EndsAt.Sub(slot.StartsAt); length != time.Duration(tt.availability[0].SlotMinutes)*time.Minute {
+					t.Errorf("slot %d lasts %s", i, length)
+				}
+				if slot.StartsAt.Location() != time.UTC {
+					t.Errorf("slot %d is not in UTC", i)
+				}
+			}
+		})
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"09:00", 540, true},
+		{" 23:59 ", 1439, true},
+		{"00:00", 0, true},
+		{"24:00", 1440, true},
+		{"24:01", 0, false},
+		{"9:00", 540, true},
+		{"09:60", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseClock(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseClock(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
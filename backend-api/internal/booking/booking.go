@@ -0,0 +1,188 @@
+package booking
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrPast is returned for a session that would start in the past
+	ErrPast = errors.New("the session must start in the future")
+	// ErrSlotUnavailable is returned when the time is not an open slot of the mentor
+	ErrSlotUnavailable = errors.New("the slot is not available")
+	// ErrConflict is returned when the user already has a session at that time
+	ErrConflict = errors.New("you already have a session at that time")
+	// ErrClosed is returned when changing a cancelled or finished booking
+	ErrClosed = errors.New("the booking can no longer be changed")
+	// ErrInvalidTimezone is returned for an unknown time zone
+	ErrInvalidTimezone = errors.New("unknown timezone")
+)
+
+// Book reserves the slot starting at start for userID. timezone is the user's time zone;
+// when empty, the time zone of the mentor's availability is used. It returns
+// gorm.ErrRecordNotFound when the mentor does not exist or is inactive.
+func Book(db *gorm.DB, mentorID, userID uuid.UUID, start time.Time, timezone, note string) (*models.Booking, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+	}
+	if !start.After(time.Now()) {
+		return nil, ErrPast
+	}
+
+	var booking models.Booking
+	err := db.Transaction(func(tx *gorm.DB) error {
+		slot, err := reserve(tx, mentorID, userID, start, uuid.Nil)
+		if err != nil {
+			return err
+		}
+		if timezone == "" {
+			timezone = slot.Timezone
+		}
+		booking = models.Booking{
+			ID:       uuid.New(),
+			MentorID: mentorID,
+			UserID:   userID,
+			StartsAt: slot.StartsAt,
+			EndsAt:   slot.EndsAt,
+			Timezone: timezone,
+			Status:   models.BookingStatusConfirmed,
+			Note:     strings.TrimSpace(note),
+		}
+		return tx.Create(&booking).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// Reschedule moves a confirmed booking to the slot starting at start
+func Reschedule(db *gorm.DB, bookingID uuid.UUID, start time.Time) (*models.Booking, error) {
+	if !start.After(time.Now()) {
+		return nil, ErrPast
+	}
+
+	var booking models.Booking
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOpen(tx, bookingID, &booking); err != nil {
+			return err
+		}
+		slot, err := reserve(tx, booking.MentorID, booking.UserID, start, booking.ID)
+		if err != nil {
+			return err
+		}
+		booking.StartsAt, booking.EndsAt = slot.StartsAt, slot.EndsAt
+		booking.Sequence++
+		return tx.Model(&booking).Updates(map[string]interface{}{
+			"starts_at": booking.StartsAt,
+			"ends_at":   booking.EndsAt,
+			"sequence":  booking.Sequence,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// Cancel cancels a confirmed booking that has not ended yet, freeing its slot
+func Cancel(db *gorm.DB, bookingID, by uuid.UUID, reason string) (*models.Booking, error) {
+	var booking models.Booking
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOpen(tx, bookingID, &booking); err != nil {
+			return err
+		}
+		now := time.Now()
+		booking.Status = models.BookingStatusCancelled
+		booking.CancelReason = strings.TrimSpace(reason)
+		booking.CancelledBy = &by
+		booking.CancelledAt = &now
+		booking.Sequence++
+		return tx.Model(&booking).Updates(map[string]interface{}{
+			"status":        booking.Status,
+			"cancel_reason": booking.CancelReason,
+			"cancelled_by":  by,
+			"cancelled_at":  now,
+			"sequence":      booking.Sequence,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// lockOpen loads a booking for update and checks that it may still be changed
+func lockOpen(tx *gorm.DB, bookingID uuid.UUID, booking *models.Booking) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", bookingID).First(booking).Error; err != nil {
+		return err
+	}
+	if booking.Status != models.BookingStatusConfirmed || !booking.EndsAt.After(time.Now()) {
+		return ErrClosed
+	}
+	return nil
+}
+
+// reserve checks that start is an open slot of the mentor and that the user is free at
+// that time, ignoring the booking being rescheduled. The mentor and user rows stay
+// locked until the transaction ends, so concurrent bookings are checked one at a time;
+// they are always locked in that order to avoid deadlocks.
+func reserve(tx *gorm.DB, mentorID, userID uuid.UUID, start time.Time, ignore uuid.UUID) (*models.Slot, error) {
+	var mentor models.Mentor
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND status = ?", mentorID, "active").First(&mentor).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		Where("id = ?", userID).First(&models.User{}).Error; err != nil {
+		return nil, err
+	}
+
+	availability, err := LoadAvailability(tx, mentorID)
+	if err != nil {
+		return nil, err
+	}
+	slots := expand(availability, start, start.Add(time.Second))
+	if len(slots) == 0 || !slots[0].StartsAt.Equal(start) {
+		return nil, ErrSlotUnavailable
+	}
+	slot := slots[0]
+
+	overlapping := func(column string, id uuid.UUID) (bool, error) {
+		var count int64
+		err := tx.Model(&models.Booking{}).
+			Where(column+" = ? AND status = ? AND id <> ? AND starts_at < ? AND ends_at > ?",
+				id, models.BookingStatusConfirmed, ignore, slot.EndsAt, slot.StartsAt).
+			Count(&count).Error
+		return count > 0, err
+	}
+	if taken, err := overlapping("mentor_id", mentorID); err != nil {
+		return nil, err
+	} else if taken {
+		return nil, ErrSlotUnavailable
+	}
+	if busy, err := overlapping("user_id", userID); err != nil {
+		return nil, err
+	} else if busy {
+		return nil, ErrConflict
+	}
+	return &slot, nil
+}
+
+// Unlink removes the availability and bookings of permanently deleted mentors
+func Unlink(tx *gorm.DB, mentorIDs []string) error {
+	if err := tx.Where("mentor_id IN ?", mentorIDs).Delete(&models.MentorAvailability{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("mentor_id IN ?", mentorIDs).Delete(&models.Booking{}).Error
+}
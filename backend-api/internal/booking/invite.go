@@ -0,0 +1,152 @@
+package booking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/ics"
+	"msc-backend-api/pkg/mailer"
+
+	"github.com/google/uuid"
+)
+
+// Changes a booking notification is sent for
+const (
+	ChangeBooked      = "booked"
+	ChangeRescheduled = "rescheduled"
+	ChangeCancelled   = "cancelled"
+)
+
+// Event returns the calendar event of a booking. Its UID stays the same across
+// reschedules so calendar apps update the event they already have.
+func Event(b models.Booking, mentor models.Mentor, student models.User) ics.Event {
+	event := ics.Event{
+		UID:         b.ID.String() + "@msc.edu.vn",
+		Sequence:    b.Sequence,
+		Start:       b.StartsAt,
+		End:         b.EndsAt,
+		Summary:     fmt.Sprintf("Mentoring: %s & %s", mentor.Name, student.Name),
+		Description: b.Note,
+		Status:      ics.StatusConfirmed,
+		Attendees:   []ics.Person{{Name: student.Name, Email: student.Email}},
+		Updated:     b.UpdatedAt,
+	}
+	if b.Status == models.BookingStatusCancelled {
+		event.Status = ics.StatusCancelled
+	}
+	if mentor.Email != "" {
+		event.Organizer = &ics.Person{Name: mentor.Name, Email: mentor.Email}
+	}
+	return event
+}
+
+// Invite returns the .ics invitation of a booking: a request while it is confirmed and a
+// cancellation once it is cancelled. Invitations must name an organizer (RFC 5546), so
+// for a mentor without an email address the sender address from is used.
+func Invite(b models.Booking, mentor models.Mentor, student models.User, from string) []byte {
+	method := ics.MethodRequest
+	if b.Status == models.BookingStatusCancelled {
+		method = ics.MethodCancel
+	}
+	event := Event(b, mentor, student)
+	if event.Organizer == nil {
+		organizer := ics.Person{Name: "MSC.EDU.VN", Email: from}
+		if addr, err := mail.ParseAddress(from); err == nil {
+			organizer.Email = addr.Address
+			if addr.Name != "" {
+				organizer.Name = addr.Name
+			}
+		}
+		event.Organizer = &organizer
+	}
+	return ics.Calendar{Method: method, Events: []ics.Event{event}}.Bytes()
+}
+
+// Feed returns the calendar of a mentor's confirmed bookings, subscribed to by URL.
+// Anyone holding the URL can read the feed, so students' email addresses are left out;
+// their names are in the event summaries.
+func Feed(mentor models.Mentor, bookings []models.Booking, students map[uuid.UUID]models.User) []byte {
+	cal := ics.Calendar{Name: "MSC mentoring – " + mentor.Name}
+	for _, b := range bookings {
+		event := Event(b, mentor, students[b.UserID])
+		event.Attendees = nil
+		cal.Events = append(cal.Events, event)
+	}
+	return cal.Bytes()
+}
+
+// Messages returns the emails sent to the user and the mentor when a booking changes,
+// each carrying the updated invitation. from is the sender address, see Invite.
+func Messages(change string, b models.Booking, mentor models.Mentor, student models.User, from string) []mailer.Message {
+	invite := mailer.Attachment{
+		Filename:    "invite.ics",
+		ContentType: "text/calendar; charset=utf-8; method=REQUEST",
+		Data:        Invite(b, mentor, student, from),
+	}
+	if b.Status == models.BookingStatusCancelled {
+		invite.ContentType = "text/calendar; charset=utf-8; method=CANCEL"
+	}
+
+	var subject, verb string
+	switch change {
+	case ChangeRescheduled:
+		subject, verb = "Mentoring session rescheduled", "has been moved to"
+	case ChangeCancelled:
+		subject, verb = "Mentoring session cancelled", "has been cancelled. It was scheduled for"
+	default:
+		subject, verb = "Mentoring session confirmed", "is confirmed for"
+	}
+
+	body := func(greeting, other, timezone string) string {
+		var text strings.Builder
+		fmt.Fprintf(&text, "Hi %s,\n\nYour mentoring session with %s %s %s.\n", greeting, other, verb, when(b, timezone))
+		if b.Note != "" && change != ChangeCancelled {
+			fmt.Fprintf(&text, "\nNote: %s\n", b.Note)
+		}
+		if change == ChangeCancelled && b.CancelReason != "" {
+			fmt.Fprintf(&text, "\nReason: %s\n", b.CancelReason)
+		}
+		text.WriteString("\nThe attached invitation updates the event in your calendar.\n\nMSC.EDU.VN\n")
+		return text.String()
+	}
+
+	messages := []mailer.Message{{
+		To:          []string{student.Email},
+		Subject:     subject + " – " + mentor.Name,
+		Text:        body(student.Name, mentor.Name, b.Timezone),
+		Attachments: []mailer.Attachment{invite},
+	}}
+	if mentor.Email != "" {
+		messages = append(messages, mailer.Message{
+			To:          []string{mentor.Email},
+			Subject:     subject + " – " + student.Name,
+			Text:        body(mentor.Name, student.Name, b.Timezone),
+			Attachments: []mailer.Attachment{invite},
+		})
+	}
+	return messages
+}
+
+// when formats the time of a booking in the given time zone
+func when(b models.Booking, timezone string) string {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, end := b.StartsAt.In(loc), b.EndsAt.In(loc)
+	return fmt.Sprintf("%s, %s–%s (%s)", start.Format("Monday 02/01/2006"), start.Format("15:04"), end.Format("15:04"), loc.String())
+}
+
+// NewFeedToken returns a random token for a mentor's calendar feed URL
+func NewFeedToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
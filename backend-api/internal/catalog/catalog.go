@@ -0,0 +1,329 @@
+// Package catalog imports and exports the public catalog (programs, mentors, projects
+// and blog posts) as CSV or JSON files.
+//
+// A file holds one item per CSV row or JSON array element, with the columns of its
+// kind. Items are matched on the kind's key (the slug; the email for mentors): known
+// items get the columns present in the file, unknown ones are created. A file is
+// imported in one transaction, so it is applied completely or not at all, and a dry
+// run validates it without saving. Exports write the same columns, so an exported file
+// can be edited and imported again.
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/mentors"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/slug"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Format is a file format
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+var (
+	// ErrUnknownKind is returned for content types that cannot be imported
+	ErrUnknownKind = errors.New("unknown catalog type")
+	// ErrUnknownFormat is returned for files that are neither CSV nor JSON
+	ErrUnknownFormat = errors.New("format must be csv or json")
+)
+
+// ParseFormat reads a format from a name ("csv"), file name ("mentors.json") or MIME
+// type ("text/csv")
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if ext := path.Ext(name); ext != "" && !strings.Contains(name, "/") {
+		name = ext
+	}
+	name, _, _ = strings.Cut(name, ";")
+	switch strings.TrimSpace(name) {
+	case "csv", ".csv", "text/csv":
+		return FormatCSV, nil
+	case "json", ".json", "application/json":
+		return FormatJSON, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+type columnType int
+
+const (
+	text      columnType = iota
+	list                 // string array; "a|b" in CSV
+	object               // any JSON value; raw JSON in CSV
+	timestamp            // RFC 3339, or a YYYY-MM-DD date in CSV
+)
+
+// Column is a field of an item in import and export files. Names are the JSON names of
+// the model fields.
+type Column struct {
+	Name string
+	typ  columnType
+}
+
+// empty is the JSON value of a column that a model leaves out of its JSON
+func (c Column) empty() json.RawMessage {
+	switch c.typ {
+	case list:
+		return json.RawMessage("[]")
+	case object, timestamp:
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(`""`)
+}
+
+// Kind is a content type that can be imported and exported
+type Kind struct {
+	Name    string
+	Table   string
+	Key     string // column that identifies an item
+	Columns []Column
+
+	newItem  func() interface{}
+	newSlice func() interface{}
+	// prepare fills defaults and returns the problems of an item about to be saved
+	prepare func(item interface{}) []string
+	// saved, when set, updates the rows that depend on an item once it is saved
+	saved func(tx *gorm.DB, item interface{}) error
+	// slugOf, when set, returns the slug of an item whose key is another column, the
+	// name a missing slug is generated from and the item's id ("" when new). The import
+	// keeps the stored slug when a row leaves it empty, makes generated slugs unique and
+	// records changed slugs in the slug history under entityType.
+	slugOf     func(item interface{}) (s *string, name, id string)
+	entityType string
+}
+
+func (k Kind) column(name string) (Column, bool) {
+	for _, column := range k.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+// ColumnNames returns the columns of the kind in file order
+func (k Kind) ColumnNames() []string {
+	names := make([]string, len(k.Columns))
+	for i, column := range k.Columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+var (
+	Programs = Kind{
+		Name:  "programs",
+		Table: "programs",
+		Key:   "slug",
+		Columns: []Column{
+			{"slug", text}, {"title", text}, {"description", text}, {"detailed_content", text},
+			{"duration", text}, {"students", text}, {"level", text}, {"price", text},
+			{"image", text}, {"highlights", list}, {"category", text},
+		},
+		newItem:  func() interface{} { return &models.Program{} },
+		newSlice: func() interface{} { return &[]models.Program{} },
+		prepare: func(item interface{}) []string {
+			program := item.(*models.Program)
+			return required(map[string]string{"title": program.Title})
+		},
+	}
+	Mentors = Kind{
+		Name:  "mentors",
+		Table: "mentors",
+		Key:   "email",
+		Columns: []Column{
+			{"email", text}, {"slug", text}, {"name", text}, {"title", text}, {"bio", text}, {"avatar_url", text},
+			{"phone", text}, {"linkedin_url", text}, {"specialties", list}, {"status", text},
+		},
+		newItem:  func() interface{} { return &models.Mentor{} },
+		newSlice: func() interface{} { return &[]models.Mentor{} },
+		prepare: func(item interface{}) []string {
+			mentor := item.(*models.Mentor)
+			if mentor.Status == "" {
+				mentor.Status = "active"
+			}
+			problems := required(map[string]string{"name": mentor.Name})
+			if mentor.Status != "active" && mentor.Status != "inactive" {
+				problems = append(problems, "status must be active or inactive")
+			}
+			return problems
+		},
+		slugOf: func(item interface{}) (*string, string, string) {
+			mentor := item.(*models.Mentor)
+			id := ""
+			if mentor.ID != uuid.Nil {
+				id = mentor.ID.String()
+			}
+			return &mentor.Slug, mentor.Name, id
+		},
+		entityType: models.EntityTypeMentor,
+	}
+	Projects = Kind{
+		Name:  "projects",
+		Table: "projects",
+		Key:   "slug",
+		Columns: []Column{
+			{"slug", text}, {"title", text}, {"description", text}, {"image", text},
+			{"category", text}, {"status", text}, {"mentors", object},
+		},
+		newItem:  func() interface{} { return &models.Project{} },
+		newSlice: func() interface{} { return &[]models.Project{} },
+		prepare: func(item interface{}) []string {
+			project := item.(*models.Project)
+			if project.Status == "" {
+				project.Status = "active"
+			}
+			// BeforeSave only encodes a non-empty list, so clearing the mentors is done here
+			if len(project.MentorsJSON) == 0 {
+				project.Mentors = "[]"
+			}
+			return required(map[string]string{"title": project.Title})
+		},
+		// Mentors are linked to mentor records by id or name
+		saved: func(tx *gorm.DB, item interface{}) error {
+			project := item.(*models.Project)
+			return mentors.SetProjectMentors(tx, project, project.MentorsJSON)
+		},
+	}
+	AllBlogPosts = Kind{
+		Name:  "allblogposts",
+		Table: "allblogposts",
+		Key:   "slug",
+		Columns: []Column{
+			{"slug", text}, {"title", text}, {"excerpt", text}, {"image", text}, {"author", text},
+			{"author_avatar", text}, {"publish_date", timestamp}, {"category", text},
+			{"details_blog", text}, {"read_time", text},
+		},
+		newItem:  func() interface{} { return &models.AllBlogPost{} },
+		newSlice: func() interface{} { return &[]models.AllBlogPost{} },
+		prepare: func(item interface{}) []string {
+			post := item.(*models.AllBlogPost)
+			// Posts from the workflow are rewritten from their post on every change
+			if post.PostID != nil {
+				return []string{"blog post is managed through /posts; edit the post instead"}
+			}
+			if post.PublishDate.IsZero() {
+				post.PublishDate = time.Now()
+			}
+			return required(map[string]string{"title": post.Title})
+		},
+	}
+)
+
+// Kinds are the importable content types by their URL name
+var Kinds = map[string]Kind{
+	Programs.Name:     Programs,
+	Mentors.Name:      Mentors,
+	Projects.Name:     Projects,
+	AllBlogPosts.Name: AllBlogPosts,
+}
+
+func Lookup(name string) (Kind, error) {
+	kind, ok := Kinds[name]
+	if !ok {
+		return Kind{}, ErrUnknownKind
+	}
+	return kind, nil
+}
+
+func required(fields map[string]string) []string {
+	var problems []string
+	for name, value := range fields {
+		if strings.TrimSpace(value) == "" {
+			problems = append(problems, name+" is required")
+		}
+	}
+	return problems
+}
+
+// normalizeKey returns the key of an item as stored: slugs are normalised and derived
+// from the title when missing, emails are lowercased
+func (k Kind) normalizeKey(fields map[string]json.RawMessage) string {
+	var key, title string
+	json.Unmarshal(fields[k.Key], &key)
+	if k.Key == "email" {
+		return strings.ToLower(strings.TrimSpace(key))
+	}
+	if normalized := slug.Make(key); normalized != "" {
+		return normalized
+	}
+	json.Unmarshal(fields["title"], &title)
+	return slug.Make(title)
+}
+
+// csvValue converts a CSV cell into the JSON value of its column
+func csvValue(column Column, cell string) (json.RawMessage, error) {
+	cell = strings.TrimSpace(cell)
+	switch column.typ {
+	case list:
+		if strings.HasPrefix(cell, "[") {
+			var values []string
+			if err := json.Unmarshal([]byte(cell), &values); err != nil {
+				return nil, fmt.Errorf("%s must be a list of texts", column.Name)
+			}
+			return json.RawMessage(cell), nil
+		}
+		values := []string{}
+		for _, value := range strings.Split(cell, "|") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		return json.Marshal(values)
+	case object:
+		if cell == "" {
+			return json.RawMessage("null"), nil
+		}
+		if !json.Valid([]byte(cell)) {
+			return nil, fmt.Errorf("%s must be JSON", column.Name)
+		}
+		return json.RawMessage(cell), nil
+	case timestamp:
+		if cell == "" {
+			return nil, nil
+		}
+		if day, err := time.Parse("2006-01-02", cell); err == nil {
+			return json.Marshal(day)
+		}
+		if _, err := time.Parse(time.RFC3339, cell); err != nil {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC 3339 time", column.Name)
+		}
+		return json.Marshal(cell)
+	}
+	return json.Marshal(cell)
+}
+
+// csvCell converts the JSON value of a column into a CSV cell
+func csvCell(column Column, value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return ""
+	}
+	switch column.typ {
+	case list:
+		var values []string
+		if err := json.Unmarshal(value, &values); err == nil {
+			return strings.Join(values, "|")
+		}
+		return string(value)
+	case object:
+		return string(value)
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		return s
+	}
+	return string(value)
+}
@@ -0,0 +1,118 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"gorm.io/gorm"
+)
+
+// exportBatch is the number of items read and written at a time
+const exportBatch = 500
+
+type flusher interface {
+	Flush()
+}
+
+// Export writes the live items of the kind to w, ordered by ID. Items are read in
+// batches and w is flushed after each one when it supports flushing, so large exports
+// stream instead of being built in memory.
+func Export(db *gorm.DB, kind Kind, format Format, w io.Writer) error {
+	var csvWriter *csv.Writer
+	switch format {
+	case FormatCSV:
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(kind.ColumnNames()); err != nil {
+			return err
+		}
+	case FormatJSON:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+	default:
+		return ErrUnknownFormat
+	}
+
+	written := 0
+	dest := kind.newSlice()
+	result := db.FindInBatches(dest, exportBatch, func(tx *gorm.DB, batch int) error {
+		objects, err := columnValues(dest)
+		if err != nil {
+			return err
+		}
+		for _, object := range objects {
+			if format == FormatCSV {
+				record := make([]string, len(kind.Columns))
+				for i, column := range kind.Columns {
+					record[i] = csvCell(column, object[column.Name])
+				}
+				if err := csvWriter.Write(record); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := writeJSONItem(w, kind, object, written == 0); err != nil {
+				return err
+			}
+			written++
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if format == FormatJSON {
+		_, err := io.WriteString(w, "\n]\n")
+		return err
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// columnValues turns a batch of models into their JSON fields
+func columnValues(batch interface{}) ([]map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]json.RawMessage
+	err = json.Unmarshal(encoded, &objects)
+	return objects, err
+}
+
+// writeJSONItem writes one array element with the kind's columns in file order
+func writeJSONItem(w io.Writer, kind Kind, object map[string]json.RawMessage, first bool) error {
+	var b bytes.Buffer
+	if !first {
+		b.WriteString(",")
+	}
+	b.WriteString("\n  {")
+	for i, column := range kind.Columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		name, _ := json.Marshal(column.Name)
+		b.Write(name)
+		b.WriteString(": ")
+		value := object[column.Name]
+		if len(value) == 0 {
+			value = column.empty()
+		}
+		b.Write(value)
+	}
+	b.WriteString("}")
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,336 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"msc-backend-api/internal/redirects"
+	"msc-backend-api/pkg/slug"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidFile is returned for files that cannot be read as their format
+var ErrInvalidFile = errors.New("invalid file")
+
+// errRollback ends the import transaction of dry runs and files with errors
+var errRollback = errors.New("rollback")
+
+// RowError is a problem with one item of an import file. Row is the CSV line or the
+// 1-based JSON array index.
+type RowError struct {
+	Row     int    `json:"row"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report is the outcome of an import. Nothing is saved when it has errors or is a
+// dry run; Created and Updated then tell what the import would do.
+type Report struct {
+	Kind     string     `json:"kind"`
+	Format   Format     `json:"format"`
+	DryRun   bool       `json:"dry_run"`
+	Rows     int        `json:"rows"`
+	Created  int        `json:"created"`
+	Updated  int        `json:"updated"`
+	Saved    bool       `json:"saved"`
+	Warnings []string   `json:"warnings,omitempty"`
+	Errors   []RowError `json:"errors"`
+}
+
+type row struct {
+	line    int
+	fields  map[string]json.RawMessage
+	problem string // set when the row could not be read
+}
+
+// Import reads a file of the kind and upserts its items by key. Problems with items are
+// listed in the report; the error is only set when the file cannot be read or the
+// database fails.
+func Import(db *gorm.DB, kind Kind, format Format, r io.Reader, dryRun bool) (Report, error) {
+	report := Report{Kind: kind.Name, Format: format, DryRun: dryRun, Errors: []RowError{}}
+
+	var rows []row
+	var unknown []string
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, unknown, err = readCSV(kind, r)
+	case FormatJSON:
+		rows, unknown, err = readJSON(kind, r)
+	default:
+		return report, ErrUnknownFormat
+	}
+	if err != nil {
+		return report, err
+	}
+	report.Rows = len(rows)
+	if len(unknown) > 0 {
+		report.Warnings = append(report.Warnings, "ignored unknown columns: "+strings.Join(unknown, ", "))
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		items := make([]interface{}, 0, len(rows))
+		previousSlugs := make([]string, 0, len(rows))
+		seen := map[string]int{}
+		claimed := map[string]int{} // slugs given to items of the file, by row
+		for _, row := range rows {
+			if row.problem != "" {
+				report.Errors = append(report.Errors, RowError{Row: row.line, Message: row.problem})
+				continue
+			}
+			key := kind.normalizeKey(row.fields)
+			if key == "" {
+				report.Errors = append(report.Errors, RowError{Row: row.line, Message: kind.Key + " is required"})
+				continue
+			}
+			if first, ok := seen[key]; ok {
+				report.Errors = append(report.Errors, RowError{Row: row.line, Key: key, Message: fmt.Sprintf("duplicate of row %d", first)})
+				continue
+			}
+			seen[key] = row.line
+
+			item, found, err := kind.find(tx, key)
+			if err != nil {
+				return err
+			}
+			if item == nil {
+				report.Errors = append(report.Errors, RowError{Row: row.line, Key: key, Message: "belongs to an item in the trash; restore or purge it first"})
+				continue
+			}
+
+			var previousSlug string
+			if kind.slugOf != nil {
+				s, _, _ := kind.slugOf(item)
+				previousSlug = *s
+			}
+
+			encodedKey, _ := json.Marshal(key)
+			row.fields[kind.Key] = encodedKey
+			object, _ := json.Marshal(row.fields)
+			if err := json.Unmarshal(object, item); err != nil {
+				report.Errors = append(report.Errors, RowError{Row: row.line, Key: key, Message: "invalid value: " + err.Error()})
+				continue
+			}
+			if problems := kind.prepare(item); len(problems) > 0 {
+				sort.Strings(problems)
+				report.Errors = append(report.Errors, RowError{Row: row.line, Key: key, Message: strings.Join(problems, "; ")})
+				continue
+			}
+			if kind.slugOf != nil {
+				problem, err := kind.resolveSlug(tx, item, previousSlug, claimed)
+				if err != nil {
+					return err
+				}
+				if problem != "" {
+					report.Errors = append(report.Errors, RowError{Row: row.line, Key: key, Message: problem})
+					continue
+				}
+				s, _, _ := kind.slugOf(item)
+				claimed[*s] = row.line
+			}
+
+			if found {
+				report.Updated++
+			} else {
+				report.Created++
+			}
+			items = append(items, item)
+			previousSlugs = append(previousSlugs, previousSlug)
+		}
+
+		if len(report.Errors) > 0 || dryRun {
+			return errRollback
+		}
+		for i, item := range items {
+			if err := tx.Save(item).Error; err != nil {
+				return fmt.Errorf("saving item %d: %w", i+1, err)
+			}
+			if kind.saved != nil {
+				if err := kind.saved(tx, item); err != nil {
+					return fmt.Errorf("saving item %d: %w", i+1, err)
+				}
+			}
+			// Links to the item's old slug keep working
+			if kind.slugOf != nil {
+				s, _, id := kind.slugOf(item)
+				if err := redirects.Record(tx, kind.entityType, id, previousSlugs[i], *s); err != nil {
+					return fmt.Errorf("saving item %d: %w", i+1, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRollback) {
+		return report, err
+	}
+	report.Saved = err == nil
+	return report, nil
+}
+
+// resolveSlug settles the slug of an item of a kind with slugOf. previous is the stored
+// slug, empty for new items, and claimed holds the slugs of the file's earlier items.
+// A row without a slug keeps the stored one or, for a new item, gets a free slug made
+// from its name. A requested slug that another item uses is returned as a problem.
+func (k Kind) resolveSlug(tx *gorm.DB, item interface{}, previous string, claimed map[string]int) (string, error) {
+	s, name, id := k.slugOf(item)
+	inFile := func(candidate string) (bool, error) {
+		_, ok := claimed[candidate]
+		return ok, nil
+	}
+
+	requested := slug.Make(*s)
+	if requested == "" && previous != "" {
+		*s = previous
+		return "", nil
+	}
+	if requested == previous {
+		*s = requested
+		return "", nil
+	}
+
+	base := requested
+	if base == "" {
+		if base = slug.Make(name); base == "" {
+			return "slug is required", nil
+		}
+	}
+	if err := slug.Lock(tx, k.Table, base); err != nil {
+		return "", err
+	}
+	free, err := slug.Unique(base, slug.InTable(tx, k.Table, id), inFile)
+	if err != nil {
+		return "", err
+	}
+	if requested != "" && free != requested {
+		if row, ok := claimed[requested]; ok {
+			return fmt.Sprintf("slug %q is already used by row %d; %q is free", requested, row, free), nil
+		}
+		return fmt.Sprintf("slug %q is already used by another item; %q is free", requested, free), nil
+	}
+	*s = free
+	return "", nil
+}
+
+// find returns the live item with key, or a new item when there is none. It returns a
+// nil item when only a trashed item has the key, since restoring is up to an editor.
+func (k Kind) find(tx *gorm.DB, key string) (interface{}, bool, error) {
+	item := k.newItem()
+	err := tx.Where(k.Key+" = ?", key).First(item).Error
+	if err == nil {
+		return item, true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	var trashed int64
+	if err := tx.Table(k.Table).Where(k.Key+" = ? AND deleted_at IS NOT NULL", key).Count(&trashed).Error; err != nil {
+		return nil, false, err
+	}
+	if trashed > 0 {
+		return nil, false, nil
+	}
+	return k.newItem(), false, nil
+}
+
+func readCSV(kind Kind, r io.Reader) ([]row, []string, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: cannot read the header: %v", ErrInvalidFile, err)
+	}
+	columns := make([]*Column, len(header))
+	var unknown []string
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if column, ok := kind.column(name); ok {
+			columns[i] = &column
+		} else if name != "" {
+			unknown = append(unknown, name)
+		}
+	}
+
+	var rows []row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if isBlank(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		r := row{line: line, fields: map[string]json.RawMessage{}}
+		var problems []string
+		for i, cell := range record {
+			if i >= len(columns) || columns[i] == nil {
+				continue
+			}
+			value, err := csvValue(*columns[i], cell)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			if value != nil {
+				r.fields[columns[i].Name] = value
+			}
+		}
+		r.problem = strings.Join(problems, "; ")
+		rows = append(rows, r)
+	}
+	return rows, unknown, nil
+}
+
+func readJSON(kind Kind, r io.Reader) ([]row, []string, error) {
+	var objects []map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&objects); err != nil {
+		return nil, nil, fmt.Errorf("%w: expected an array of objects: %v", ErrInvalidFile, err)
+	}
+
+	unknownSet := map[string]bool{}
+	rows := make([]row, 0, len(objects))
+	for i, object := range objects {
+		r := row{line: i + 1, fields: map[string]json.RawMessage{}}
+		for name, value := range object {
+			if _, ok := kind.column(name); ok {
+				r.fields[name] = value
+			} else {
+				unknownSet[name] = true
+			}
+		}
+		rows = append(rows, r)
+	}
+
+	unknown := make([]string, 0, len(unknownSet))
+	for name := range unknownSet {
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	return rows, unknown, nil
+}
+
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
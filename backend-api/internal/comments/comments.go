@@ -0,0 +1,142 @@
+// Package comments holds the moderation rules of blog comments: the banned-word
+// filter, threading, approved-comment counts and the notification sent to a post's
+// author when a comment on it goes live.
+package comments
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/slug"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Filter flags comments that contain a banned word or phrase. Matching ignores case
+// and Vietnamese accents and only hits whole words, so "ngu" does not match "người".
+type Filter struct {
+	words []string
+}
+
+func NewFilter(words []string) *Filter {
+	f := &Filter{}
+	for _, word := range words {
+		if normalized := slug.Make(word); normalized != "" {
+			f.words = append(f.words, normalized)
+		}
+	}
+	return f
+}
+
+// Match returns the first banned word found in text
+func (f *Filter) Match(text string) (string, bool) {
+	normalized := "-" + slug.Make(text) + "-"
+	for _, word := range f.words {
+		if strings.Contains(normalized, "-"+word+"-") {
+			return word, true
+		}
+	}
+	return "", false
+}
+
+// Thread nests replies under their parents. comments must be in display order; replies
+// whose parent is not in the list are dropped.
+func Thread(comments []models.Comment) []models.Comment {
+	children := map[uuid.UUID][]models.Comment{}
+	var roots []models.Comment
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+	}
+
+	var attach func(list []models.Comment) []models.Comment
+	attach = func(list []models.Comment) []models.Comment {
+		for i := range list {
+			list[i].Replies = attach(children[list[i].ID])
+		}
+		return list
+	}
+	if roots == nil {
+		return []models.Comment{}
+	}
+	return attach(roots)
+}
+
+// Counts returns the number of approved comments of each blog post
+func Counts(db *gorm.DB, blogPostIDs []uint) (map[uint]int64, error) {
+	counts := map[uint]int64{}
+	if len(blogPostIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		BlogPostID uint
+		Count      int64
+	}
+	if err := db.Model(&models.Comment{}).
+		Select("blog_post_id, COUNT(*) AS count").
+		Where("blog_post_id IN ? AND status = ?", blogPostIDs, models.CommentStatusApproved).
+		Group("blog_post_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.BlogPostID] = row.Count
+	}
+	return counts, nil
+}
+
+// NotifyAuthor tells the author of the post behind a blog entry that a comment on it
+// was approved. Legacy entries without a post have no account to notify, and authors
+// are not told about their own comments.
+func NotifyAuthor(tx *gorm.DB, comment models.Comment) error {
+	var entry models.AllBlogPost
+	if err := tx.Unscoped().Where("id = ?", comment.BlogPostID).First(&entry).Error; err != nil {
+		return err
+	}
+	if entry.PostID == nil {
+		return nil
+	}
+
+	var post models.Post
+	if err := tx.Unscoped().Select("id", "author_id").Where("id = ?", *entry.PostID).First(&post).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if post.AuthorID == comment.UserID {
+		return nil
+	}
+
+	commenter := "Someone"
+	if comment.User != nil && comment.User.Name != "" {
+		commenter = comment.User.Name
+	}
+	return tx.Create(&models.Notification{
+		UserID:  post.AuthorID,
+		Type:    models.NotificationTypeComment,
+		Title:   fmt.Sprintf("%s commented on \"%s\"", commenter, entry.Title),
+		Message: excerpt(comment.Content, 200),
+		Link:    "/chia-se/" + entry.Slug + "#comment-" + comment.ID.String(),
+	}).Error
+}
+
+// Unlink removes the comments of blog entries that are deleted for good
+func Unlink(tx *gorm.DB, blogPostIDs []string) error {
+	return tx.Where("blog_post_id IN ?", blogPostIDs).Delete(&models.Comment{}).Error
+}
+
+func excerpt(text string, limit int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
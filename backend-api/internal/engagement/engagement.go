@@ -0,0 +1,266 @@
+// Package engagement counts views and likes of public content.
+//
+// Views are de-duplicated per visitor within a time window and likes once per visitor.
+// Counter changes are collected in memory and flushed to the content rows in batches,
+// so popular items do not turn every page view into a write on the same row. The view
+// window is tracked per replica; a visitor whose requests land on several replicas
+// may be counted once on each.
+package engagement
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"msc-backend-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnknownType is returned for content types without counters
+var ErrUnknownType = errors.New("unknown content type")
+
+// Target is a content table with views and likes columns
+type Target struct {
+	Name       string // URL name of the type
+	Table      string
+	EntityType string
+	UUIDKey    bool // false for the serial keys of allblogposts
+}
+
+var (
+	AllBlogPosts = Target{Name: "allblogposts", Table: "allblogposts", EntityType: models.EntityTypeBlogPost}
+	Programs     = Target{Name: "programs", Table: "programs", EntityType: models.EntityTypeProgram, UUIDKey: true}
+	Projects     = Target{Name: "projects", Table: "projects", EntityType: models.EntityTypeProject, UUIDKey: true}
+)
+
+// Targets are the content types with counters, by URL name
+var Targets = map[string]Target{
+	AllBlogPosts.Name: AllBlogPosts,
+	Programs.Name:     Programs,
+	Projects.Name:     Projects,
+}
+
+// Lookup returns the target for a URL name
+func Lookup(name string) (Target, error) {
+	t, ok := Targets[name]
+	if !ok {
+		return Target{}, ErrUnknownType
+	}
+	return t, nil
+}
+
+// ValidID reports whether id has the key format of the target
+func (t Target) ValidID(id string) bool {
+	if t.UUIDKey {
+		_, err := uuid.Parse(id)
+		return err == nil
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return id != ""
+}
+
+// Visitor identifies who is viewing or liking: the user for signed-in requests and a
+// hash of the client address and user agent for anonymous ones
+func Visitor(userID, clientIP, userAgent string) string {
+	if userID != "" {
+		return "user:" + userID
+	}
+	sum := sha256.Sum256([]byte(clientIP + "\n" + userAgent))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
+
+type counterKey struct {
+	table string
+	id    string
+}
+
+type counterDelta struct {
+	views int
+	likes int
+}
+
+// Tracker de-duplicates views and buffers counter changes until the next flush
+type Tracker struct {
+	db       *gorm.DB
+	window   time.Duration
+	interval time.Duration
+
+	mu      sync.Mutex
+	seen    map[string]time.Time // last counted view per item and visitor
+	pending map[counterKey]counterDelta
+}
+
+func NewTracker(db *gorm.DB, window, interval time.Duration) *Tracker {
+	return &Tracker{
+		db:       db,
+		window:   window,
+		interval: interval,
+		seen:     map[string]time.Time{},
+		pending:  map[counterKey]counterDelta{},
+	}
+}
+
+// Exists reports whether the item is a live (not trashed) row of the target
+func Exists(db *gorm.DB, t Target, id string) (bool, error) {
+	var count int64
+	err := db.Table(t.Table).Where("id = ? AND deleted_at IS NULL", id).Count(&count).Error
+	return count > 0, err
+}
+
+// RecordView counts a view unless the visitor's last counted view of the item is
+// within the window. It reports whether the view was counted.
+func (tr *Tracker) RecordView(t Target, id, visitor string) bool {
+	now := time.Now()
+	key := t.EntityType + "/" + id + "/" + visitor
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if at, ok := tr.seen[key]; ok && now.Sub(at) < tr.window {
+		return false
+	}
+	tr.seen[key] = now
+	tr.addLocked(counterKey{t.Table, id}, counterDelta{views: 1})
+	return true
+}
+
+// Like records that the visitor likes the item. It reports false when they already did.
+func (tr *Tracker) Like(db *gorm.DB, t Target, id, visitor string) (bool, error) {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ContentLike{
+		EntityType: t.EntityType,
+		EntityID:   id,
+		Visitor:    visitor,
+	})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	tr.add(counterKey{t.Table, id}, counterDelta{likes: 1})
+	return true, nil
+}
+
+// Unlike withdraws the visitor's like. It reports false when there was none.
+func (tr *Tracker) Unlike(db *gorm.DB, t Target, id, visitor string) (bool, error) {
+	result := db.Where("entity_type = ? AND entity_id = ? AND visitor = ?", t.EntityType, id, visitor).
+		Delete(&models.ContentLike{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	tr.add(counterKey{t.Table, id}, counterDelta{likes: -1})
+	return true, nil
+}
+
+// Get returns the counters of an item, including changes not flushed yet, and whether
+// the visitor likes it. It returns gorm.ErrRecordNotFound for missing or trashed items.
+func (tr *Tracker) Get(db *gorm.DB, t Target, id, visitor string) (models.Engagement, error) {
+	var engagement models.Engagement
+	if err := db.Table(t.Table).
+		Select("COALESCE(views, 0) AS views, COALESCE(likes, 0) AS likes").
+		Where("id = ? AND deleted_at IS NULL", id).
+		Take(&engagement).Error; err != nil {
+		return models.Engagement{}, err
+	}
+
+	var liked int64
+	if err := db.Model(&models.ContentLike{}).
+		Where("entity_type = ? AND entity_id = ? AND visitor = ?", t.EntityType, id, visitor).
+		Count(&liked).Error; err != nil {
+		return models.Engagement{}, err
+	}
+	engagement.Liked = liked > 0
+
+	pending := tr.Pending(t, id)
+	engagement.Views += pending.Views
+	engagement.Likes += pending.Likes
+	return engagement, nil
+}
+
+// Pending returns the counter changes of an item that have not been flushed yet
+func (tr *Tracker) Pending(t Target, id string) models.Engagement {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	d := tr.pending[counterKey{t.Table, id}]
+	return models.Engagement{Views: d.views, Likes: d.likes}
+}
+
+// Flush writes the buffered counter changes, one update per item. Changes that could
+// not be written are kept for the next flush.
+func (tr *Tracker) Flush(ctx context.Context) error {
+	tr.mu.Lock()
+	pending := tr.pending
+	tr.pending = map[counterKey]counterDelta{}
+	now := time.Now()
+	for key, at := range tr.seen {
+		if now.Sub(at) >= tr.window {
+			delete(tr.seen, key)
+		}
+	}
+	tr.mu.Unlock()
+
+	for key, d := range pending {
+		err := tr.db.WithContext(ctx).Table(key.table).Where("id = ?", key.id).UpdateColumns(map[string]interface{}{
+			"views": gorm.Expr("COALESCE(views, 0) + ?", d.views),
+			"likes": gorm.Expr("GREATEST(COALESCE(likes, 0) + ?, 0)", d.likes),
+		}).Error
+		if err != nil {
+			tr.mu.Lock()
+			for unwritten, rest := range pending {
+				tr.addLocked(unwritten, rest)
+			}
+			tr.mu.Unlock()
+			return err
+		}
+		delete(pending, key)
+	}
+	return nil
+}
+
+// Run flushes every interval until ctx is cancelled, then flushes once more
+func (tr *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(tr.interval)
+	defer ticker.Stop()
+
+	log.Printf("Engagement counters started (flush every %s, view window %s)", tr.interval, tr.window)
+	for {
+		select {
+		case <-ctx.Done():
+			if err := tr.Flush(context.Background()); err != nil {
+				log.Printf("Engagement flush failed: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := tr.Flush(ctx); err != nil {
+				log.Printf("Engagement flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// Unlink removes the likes of content rows that are deleted for good
+func Unlink(tx *gorm.DB, entityType string, ids []string) error {
+	return tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Delete(&models.ContentLike{}).Error
+}
+
+func (tr *Tracker) add(key counterKey, d counterDelta) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.addLocked(key, d)
+}
+
+func (tr *Tracker) addLocked(key counterKey, d counterDelta) {
+	sum := tr.pending[key]
+	sum.views += d.views
+	sum.likes += d.likes
+	tr.pending[key] = sum
+}
@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"msc-backend-api/internal/blog"
+	"msc-backend-api/internal/engagement"
+	"msc-backend-api/internal/i18n"
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/redirects"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/textsearch"
 	"net/http"
 	"strconv"
 
@@ -10,11 +16,12 @@ import (
 )
 
 type AllBlogPostHandler struct {
-	db *gorm.DB
+	db      *gorm.DB
+	tracker *engagement.Tracker
 }
 
-func NewAllBlogPostHandler(db *gorm.DB) *AllBlogPostHandler {
-	return &AllBlogPostHandler{db: db}
+func NewAllBlogPostHandler(db *gorm.DB, tracker *engagement.Tracker) *AllBlogPostHandler {
+	return &AllBlogPostHandler{db: db, tracker: tracker}
 }
 
 // GetAllBlogPosts godoc
@@ -25,13 +32,16 @@ func NewAllBlogPostHandler(db *gorm.DB) *AllBlogPostHandler {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param category query string false "Filter by category"
+// @Param category query string false "Filter by category slug or name, including subcategories"
+// @Param tag query string false "Filter by tag slug"
+// @Param search query string false "Full-text search (accent-insensitive), results ranked by relevance"
 // @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
 // @Router /api/allblogposts [get]
 func (h *AllBlogPostHandler) GetAllBlogPosts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	category := c.Query("category")
+	search := c.Query("search")
 
 	if page < 1 {
 		page = 1
@@ -47,12 +57,14 @@ func (h *AllBlogPostHandler) GetAllBlogPosts(c *gin.Context) {
 
 	query := h.db.Model(&models.AllBlogPost{})
 
-	if category != "" {
-		query = query.Where("category = ?", category)
+	query = taxonomy.Filter(query, taxonomy.AllBlogPosts, category, c.Query("tag"))
+	if search != "" {
+		query = query.Where(textsearch.AllBlogPosts.Match(search)).Order(textsearch.AllBlogPosts.Rank(search))
 	}
 
 	// Count total
 	query.Count(&total)
+	facets := listFacets(h.db, query, taxonomy.AllBlogPosts)
 
 	// Get posts
 	if err := query.Order("publish_date DESC").
@@ -66,6 +78,40 @@ func (h *AllBlogPostHandler) GetAllBlogPosts(c *gin.Context) {
 		return
 	}
 
+	if search != "" && len(posts) > 0 {
+		ids := make([]uint, len(posts))
+		for i := range posts {
+			ids[i] = posts[i].ID
+		}
+		snippets := searchSnippets(h.db, textsearch.AllBlogPosts, search, ids)
+		for i := range posts {
+			posts[i].Snippet = snippets[strconv.FormatUint(uint64(posts[i].ID), 10)]
+		}
+	}
+
+	ids := make([]string, len(posts))
+	for i := range posts {
+		ids[i] = strconv.FormatUint(uint64(posts[i].ID), 10)
+	}
+	translations := loadTranslations(c, h.db, i18n.AllBlogPosts, ids)
+	for i := range posts {
+		i18n.Apply(&posts[i], translations[ids[i]])
+	}
+	taxonomies := loadTaxonomy(h.db, taxonomy.AllBlogPosts, ids)
+	for i := range posts {
+		tax := taxonomies[ids[i]]
+		posts[i].Categories, posts[i].Tags = tax.Categories, tax.Tags
+	}
+
+	blogIDs := make([]uint, len(posts))
+	for i := range posts {
+		blogIDs[i] = posts[i].ID
+	}
+	commentCounts := loadCommentCounts(h.db, blogIDs)
+	for i := range posts {
+		posts[i].CommentsCount = commentCounts[posts[i].ID]
+	}
+
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
 
 	response := models.PaginatedResponse{
@@ -74,6 +120,7 @@ func (h *AllBlogPostHandler) GetAllBlogPosts(c *gin.Context) {
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		Facets:     facets,
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
@@ -111,9 +158,15 @@ func (h *AllBlogPostHandler) GetBlogPostByID(c *gin.Context) {
 		return
 	}
 
-	// Increment views
-	h.db.Model(&post).Update("views", post.Views+1)
-	post.Views++
+	// Count the view, once per visitor within the view window
+	h.tracker.RecordView(engagement.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10), visitorOf(c))
+	pending := h.tracker.Pending(engagement.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10))
+	post.Views += pending.Views
+	post.Likes += pending.Likes
+
+	i18n.Apply(&post, itemTranslations(c, h.db, i18n.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10)))
+	post.Categories, post.Tags = itemTaxonomy(h.db, taxonomy.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10))
+	post.CommentsCount = loadCommentCounts(h.db, []uint{post.ID})[post.ID]
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
@@ -129,6 +182,7 @@ func (h *AllBlogPostHandler) GetBlogPostByID(c *gin.Context) {
 // @Produce json
 // @Param slug path string true "Blog Post Slug"
 // @Success 200 {object} models.APIResponse{data=models.AllBlogPost}
+// @Success 301 {object} models.APIResponse{data=models.Redirect} "Old slug; Location has the current one"
 // @Failure 404 {object} models.APIResponse
 // @Router /api/allblogposts/slug/{slug} [get]
 func (h *AllBlogPostHandler) GetBlogPostBySlug(c *gin.Context) {
@@ -137,6 +191,9 @@ func (h *AllBlogPostHandler) GetBlogPostBySlug(c *gin.Context) {
 	var post models.AllBlogPost
 	if err := h.db.Where("slug = ?", slug).First(&post).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
+			if respondMovedSlug(c, h.db, redirects.AllBlogPosts) {
+				return
+			}
 			c.JSON(http.StatusNotFound, models.APIResponse{
 				Success: false,
 				Message: "Blog post not found",
@@ -150,12 +207,47 @@ func (h *AllBlogPostHandler) GetBlogPostBySlug(c *gin.Context) {
 		return
 	}
 
-	// Increment views
-	h.db.Model(&post).Update("views", post.Views+1)
-	post.Views++
+	// Count the view, once per visitor within the view window
+	h.tracker.RecordView(engagement.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10), visitorOf(c))
+	pending := h.tracker.Pending(engagement.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10))
+	post.Views += pending.Views
+	post.Likes += pending.Likes
+
+	i18n.Apply(&post, itemTranslations(c, h.db, i18n.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10)))
+	post.Categories, post.Tags = itemTaxonomy(h.db, taxonomy.AllBlogPosts, strconv.FormatUint(uint64(post.ID), 10))
+	post.CommentsCount = loadCommentCounts(h.db, []uint{post.ID})[post.ID]
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    post,
 	})
 }
+
+// ImportLegacyPosts godoc
+// @Summary Import legacy blog posts
+// @Description Create a published post for every blog post that is not managed through the post workflow yet. The public blog is unchanged; later edits go through /posts. Blog posts whose slug is already used by a post are skipped and listed.
+// @Tags allblogposts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 500 {object} models.APIResponse
+// @Router /allblogposts/import [post]
+func (h *AllBlogPostHandler) ImportLegacyPosts(c *gin.Context) {
+	result, err := blog.ImportLegacy(h.db, currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to import blog posts: " + err.Error(),
+			Data:    result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Blog posts imported successfully",
+		Data:    result,
+	})
+}
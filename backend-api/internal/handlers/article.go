@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"msc-backend-api/internal/blog"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/uploads"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxArticleImportSize is the largest request accepted by the article import, images included
+const maxArticleImportSize = 50 << 20
+
+// maxArticleSize is the largest single Markdown file accepted by the article import
+const maxArticleSize = 2 << 20
+
+type ArticleHandler struct {
+	db    *gorm.DB
+	store *uploads.Store
+}
+
+func NewArticleHandler(db *gorm.DB, store *uploads.Store) *ArticleHandler {
+	return &ArticleHandler{db: db, store: store}
+}
+
+// @Summary Import Markdown articles
+// @Description Import .md or .mdx articles with YAML front matter (title, slug or id, excerpt, author, category, tags, image, publishDate) as published blog posts, inserting or updating by slug. A post with the slug that is not published is left alone and the article is reported as failed. Local images referenced by the articles are uploaded when sent as images with the same file name; others are kept as written and reported as warnings.
+// @Tags allblogposts
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param files formData file true "Markdown or MDX files"
+// @Param images formData file false "Images referenced by the articles"
+// @Success 200 {object} models.APIResponse{data=[]blog.ArticleResult}
+// @Failure 400 {object} models.APIResponse{data=[]blog.ArticleResult}
+// @Router /allblogposts/import/articles [post]
+func (h *ArticleHandler) ImportArticles(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxArticleImportSize)
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["files"]) == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "No article files provided",
+		})
+		return
+	}
+
+	// Images are matched to references by file name, the only part of the path browsers send
+	images := map[string]*multipart.FileHeader{}
+	for _, image := range form.File["images"] {
+		images[strings.ToLower(filepath.Base(image.Filename))] = image
+	}
+	open := func(ref string) (string, io.ReadCloser, error) {
+		name := strings.ToLower(path.Base(blog.ImagePath(ref)))
+		image, ok := images[name]
+		if !ok {
+			return "", nil, os.ErrNotExist
+		}
+		f, err := image.Open()
+		return name, f, err
+	}
+	uploader := blog.NewImageUploader(h.store)
+
+	results := make([]*blog.ArticleResult, 0, len(form.File["files"]))
+	imported := 0
+	for _, file := range form.File["files"] {
+		result, err := h.importArticle(c, file, uploader, open)
+		if err != nil {
+			result = &blog.ArticleResult{File: file.Filename, Error: err.Error()}
+		} else {
+			imported++
+		}
+		results = append(results, result)
+	}
+
+	if imported == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "No articles were imported",
+			Data:    results,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Imported %d of %d articles", imported, len(results)),
+		Data:    results,
+	})
+}
+
+func (h *ArticleHandler) importArticle(c *gin.Context, file *multipart.FileHeader, uploader *blog.ImageUploader, open blog.ImageOpener) (*blog.ArticleResult, error) {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext != ".md" && ext != ".mdx" && ext != ".markdown" {
+		return nil, fmt.Errorf("%w: only .md and .mdx files can be imported", blog.ErrInvalidArticle)
+	}
+	if file.Size > maxArticleSize {
+		return nil, fmt.Errorf("%w: file is larger than %dMB", blog.ErrInvalidArticle, maxArticleSize>>20)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	src, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := blog.ParseArticle(file.Filename, src)
+	if err != nil {
+		return nil, err
+	}
+	warnings, undo := uploader.Upload(article, open)
+	result, err := blog.ImportArticle(h.db, article, currentUserID(c))
+	if err != nil {
+		undo()
+		return nil, err
+	}
+	result.Warnings = warnings
+	return result, nil
+}
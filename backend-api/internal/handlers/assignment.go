@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"msc-backend-api/internal/mentors"
+	"msc-backend-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// respondAssignedMentors writes the mentors assigned to a course or program
+func respondAssignedMentors(c *gin.Context, db *gorm.DB, entityType string, entityID uuid.UUID) {
+	assigned, err := mentors.LoadAssigned(db, entityType, entityID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentors",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    assigned,
+	})
+}
+
+// setAssignedMentors replaces the mentors of a course or program from the request body
+// and writes the new list
+func setAssignedMentors(c *gin.Context, db *gorm.DB, entityType string, entityID uuid.UUID, notFound string) {
+	var req models.SetAssignmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	assignments := make([]mentors.Assignment, len(req.Mentors))
+	for i, m := range req.Mentors {
+		assignments[i] = mentors.Assignment{MentorID: m.MentorID, Role: m.Role}
+	}
+	if err := mentors.SetAssignments(db, entityType, entityID, assignments); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Message: notFound,
+			})
+		case errors.Is(err, mentors.ErrInvalidAssignment):
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Failed to update mentors",
+			})
+		}
+		return
+	}
+
+	assigned, _ := mentors.LoadAssigned(db, entityType, entityID, false)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Mentors updated successfully",
+		Data:    assigned,
+	})
+}
@@ -0,0 +1,760 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"msc-backend-api/internal/booking"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/auth"
+	"msc-backend-api/pkg/config"
+	"msc-backend-api/pkg/mailer"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSlotRange is the longest period open slots are listed for at once
+const maxSlotRange = 62 * 24 * time.Hour
+
+// calendarMaxAge is how long calendar apps may cache a mentor's feed
+const calendarMaxAge = 5 * time.Minute
+
+// calendarHistory is how far back a mentor's feed lists past sessions
+const calendarHistory = 30 * 24 * time.Hour
+
+type BookingHandler struct {
+	db       *gorm.DB
+	mailer   *mailer.Mailer
+	apiURL   string
+	mailFrom string
+}
+
+func NewBookingHandler(db *gorm.DB, cfg *config.Config, m *mailer.Mailer) *BookingHandler {
+	return &BookingHandler{db: db, mailer: m, apiURL: cfg.APIURL, mailFrom: cfg.MailFrom}
+}
+
+// @Summary Get mentor availability
+// @Description Get the weekly windows in which a mentor offers sessions
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Success 200 {object} models.APIResponse{data=[]models.MentorAvailability}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/availability [get]
+func (h *BookingHandler) GetAvailability(c *gin.Context) {
+	mentor, ok := h.findMentor(c)
+	if !ok {
+		return
+	}
+
+	availability, err := booking.LoadAvailability(h.db, mentor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch availability",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    availability,
+	})
+}
+
+// @Summary Set mentor availability
+// @Description Replace a mentor's weekly availability. Times are HH:MM in the window's IANA time zone; weekday 0 is Sunday. Booked sessions are kept. Allowed for the mentor's own account, admins and editors.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Param availability body models.SetAvailabilityRequest true "Weekly availability"
+// @Success 200 {object} models.APIResponse{data=[]models.MentorAvailability}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/availability [put]
+func (h *BookingHandler) SetAvailability(c *gin.Context) {
+	mentor, ok := h.findMentor(c)
+	if !ok {
+		return
+	}
+	if !canManageMentor(c, mentor) {
+		respondForbidden(c)
+		return
+	}
+
+	var req models.SetAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	if err := booking.SetAvailability(h.db, mentor.ID, req.Availability); err != nil {
+		if errors.Is(err, booking.ErrInvalidAvailability) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to update availability",
+		})
+		return
+	}
+
+	availability, _ := booking.LoadAvailability(h.db, mentor.ID)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Availability updated successfully",
+		Data:    availability,
+	})
+}
+
+// @Summary Get open slots
+// @Description List the open slots of a mentor in a period of at most 62 days
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Param from query string false "Start of the period, RFC 3339 or YYYY-MM-DD (UTC)" default(now)
+// @Param to query string false "End of the period, RFC 3339 or YYYY-MM-DD (UTC)" default(from + 14 days)
+// @Success 200 {object} models.APIResponse{data=[]models.Slot}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/slots [get]
+func (h *BookingHandler) GetSlots(c *gin.Context) {
+	mentor, ok := h.findMentor(c)
+	if !ok {
+		return
+	}
+
+	from := time.Now()
+	if value := c.Query("from"); value != "" {
+		t, ok := parseTimeParam(value)
+		if !ok {
+			respondInvalidPeriod(c)
+			return
+		}
+		from = t
+	}
+	to := from.Add(14 * 24 * time.Hour)
+	if value := c.Query("to"); value != "" {
+		t, ok := parseTimeParam(value)
+		if !ok {
+			respondInvalidPeriod(c)
+			return
+		}
+		to = t
+	}
+	if !to.After(from) || to.Sub(from) > maxSlotRange {
+		respondInvalidPeriod(c)
+		return
+	}
+
+	slots, err := booking.Slots(h.db, mentor.ID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch slots",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    slots,
+	})
+}
+
+// @Summary Get mentor calendar feed URL
+// @Description Get the secret URL of a mentor's .ics calendar feed of booked sessions, for subscribing in a calendar app. The URL is created on first use. Allowed for the mentor's own account, admins and editors.
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/calendar [get]
+func (h *BookingHandler) GetCalendarURL(c *gin.Context) {
+	mentor, ok := h.findMentor(c)
+	if !ok {
+		return
+	}
+	if !canManageMentor(c, mentor) {
+		respondForbidden(c)
+		return
+	}
+
+	var feed models.MentorCalendarFeed
+	err := h.db.Where("mentor_id = ?", mentor.ID).First(&feed).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		feed, err = h.newCalendarFeed(mentor.ID, false)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch calendar URL",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    gin.H{"url": h.calendarURL(feed)},
+	})
+}
+
+// @Summary Regenerate mentor calendar feed URL
+// @Description Replace the secret URL of a mentor's calendar feed. Calendar apps subscribed to the old URL stop receiving updates. Allowed for the mentor's own account, admins and editors.
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/calendar/regenerate [post]
+func (h *BookingHandler) RegenerateCalendarURL(c *gin.Context) {
+	mentor, ok := h.findMentor(c)
+	if !ok {
+		return
+	}
+	if !canManageMentor(c, mentor) {
+		respondForbidden(c)
+		return
+	}
+
+	feed, err := h.newCalendarFeed(mentor.ID, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to regenerate calendar URL",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Calendar URL regenerated successfully",
+		Data:    gin.H{"url": h.calendarURL(feed)},
+	})
+}
+
+// newCalendarFeed gives a mentor a new random feed token. Without replace, a token
+// created concurrently is kept and returned instead.
+func (h *BookingHandler) newCalendarFeed(mentorID uuid.UUID, replace bool) (models.MentorCalendarFeed, error) {
+	token, err := booking.NewFeedToken()
+	if err != nil {
+		return models.MentorCalendarFeed{}, err
+	}
+	feed := models.MentorCalendarFeed{MentorID: mentorID, Token: token}
+	onConflict := clause.OnConflict{Columns: []clause.Column{{Name: "mentor_id"}}, DoNothing: true}
+	if replace {
+		onConflict = clause.OnConflict{
+			Columns:   []clause.Column{{Name: "mentor_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"token", "created_at"}),
+		}
+	}
+	if err := h.db.Clauses(onConflict).Create(&feed).Error; err != nil {
+		return models.MentorCalendarFeed{}, err
+	}
+	if !replace {
+		err = h.db.Where("mentor_id = ?", mentorID).First(&feed).Error
+	}
+	return feed, err
+}
+
+func (h *BookingHandler) calendarURL(feed models.MentorCalendarFeed) string {
+	return h.apiURL + "/api/mentors/" + feed.MentorID.String() + "/calendar.ics?token=" + feed.Token
+}
+
+// @Summary Mentor calendar feed
+// @Description iCalendar feed of a mentor's confirmed sessions from the last 30 days on, without attendee email addresses. The token comes from GET /mentors/{id}/calendar.
+// @Tags bookings
+// @Produce text/calendar
+// @Param id path string true "Mentor ID"
+// @Param token query string true "Feed token"
+// @Success 200 {string} string "iCalendar document"
+// @Failure 404 {object} models.APIResponse
+// @Router /api/mentors/{id}/calendar.ics [get]
+func (h *BookingHandler) GetMentorCalendar(c *gin.Context) {
+	mentorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondMentorNotFound(c)
+		return
+	}
+	var feed models.MentorCalendarFeed
+	if err := h.db.Where("mentor_id = ?", mentorID).First(&feed).Error; err != nil ||
+		subtle.ConstantTimeCompare([]byte(feed.Token), []byte(c.Query("token"))) != 1 {
+		respondMentorNotFound(c)
+		return
+	}
+	var mentor models.Mentor
+	if err := h.db.Where("id = ?", mentorID).First(&mentor).Error; err != nil {
+		respondMentorNotFound(c)
+		return
+	}
+
+	var bookings []models.Booking
+	if err := h.db.Where("mentor_id = ? AND status = ? AND ends_at > ?",
+		mentor.ID, models.BookingStatusConfirmed, time.Now().Add(-calendarHistory)).
+		Order("starts_at").Find(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch bookings",
+		})
+		return
+	}
+
+	students := map[uuid.UUID]models.User{}
+	if len(bookings) > 0 {
+		ids := make([]uuid.UUID, len(bookings))
+		for i, b := range bookings {
+			ids[i] = b.UserID
+		}
+		var users []models.User
+		h.db.Unscoped().Select("id", "name", "email").Where("id IN ?", ids).Find(&users)
+		for _, u := range users {
+			students[u.ID] = u
+		}
+	}
+
+	// Cancellations change the feed without a newer row, so only the ETag is offered
+	serveCacheable(c, "text/calendar; charset=utf-8", booking.Feed(mentor, bookings, students), time.Time{}, calendarMaxAge)
+}
+
+// @Summary Book a session
+// @Description Book an open slot of a mentor. A confirmation email with an .ics invitation is sent to the user and the mentor.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param booking body models.CreateBookingRequest true "Booking"
+// @Success 201 {object} models.APIResponse{data=models.Booking}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /bookings [post]
+func (h *BookingHandler) CreateBooking(c *gin.Context) {
+	var req models.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	created, err := booking.Book(h.db, req.MentorID, currentUserID(c), req.StartsAt, req.Timezone, req.Note)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondMentorNotFound(c)
+			return
+		}
+		respondBookingError(c, err, "Failed to book session")
+		return
+	}
+
+	b, err := h.load(created.ID)
+	if err != nil {
+		b = created
+	}
+	h.notify(booking.ChangeBooked, *b)
+	h.hideMentorContact(c, b)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Session booked successfully",
+		Data:    b,
+	})
+}
+
+// @Summary Get bookings
+// @Description Get the sessions the current user booked and, for a mentor's account, the sessions booked with them. Admins and editors see all bookings.
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param mentor_id query string false "Filter by mentor"
+// @Param status query string false "Filter by status (confirmed, cancelled)"
+// @Param upcoming query bool false "Only sessions that have not ended"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Failure 401 {object} models.APIResponse
+// @Router /bookings [get]
+func (h *BookingHandler) GetBookings(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := h.db.Model(&models.Booking{})
+	if !isStaff(c) {
+		userID := currentUserID(c)
+		query = query.Where("user_id = ? OR mentor_id IN (?)", userID,
+			h.db.Model(&models.Mentor{}).Select("id").Where("user_id = ?", userID))
+	}
+	if mentorID := c.Query("mentor_id"); mentorID != "" {
+		query = query.Where("mentor_id = ?", mentorID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	upcoming := c.Query("upcoming") == "true"
+	if upcoming {
+		query = query.Where("ends_at > ?", time.Now())
+	}
+
+	var total int64
+	query.Count(&total)
+
+	order := "starts_at DESC"
+	if upcoming {
+		order = "starts_at"
+	}
+	bookings := []models.Booking{}
+	if err := preloadParties(query).Order(order).
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&bookings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch bookings",
+		})
+		return
+	}
+	for i := range bookings {
+		h.hideMentorContact(c, &bookings[i])
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.PaginatedResponse{
+			Data:       bookings,
+			Total:      total,
+			Page:       page,
+			Limit:      limit,
+			TotalPages: (int(total) + limit - 1) / limit,
+		},
+	})
+}
+
+// @Summary Get booking
+// @Description Get a booking of the current user, of a mentor's account, or any booking for admins and editors
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} models.APIResponse{data=models.Booking}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /bookings/{id} [get]
+func (h *BookingHandler) GetBooking(c *gin.Context) {
+	b, ok := h.findBooking(c)
+	if !ok {
+		return
+	}
+	h.hideMentorContact(c, b)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    b,
+	})
+}
+
+// @Summary Get booking invitation
+// @Description Download the .ics invitation of a booking
+// @Tags bookings
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {string} string "iCalendar document"
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /bookings/{id}/invite.ics [get]
+func (h *BookingHandler) GetBookingInvite(c *gin.Context) {
+	b, ok := h.findBooking(c)
+	if !ok {
+		return
+	}
+	mentor, student := h.parties(*b)
+
+	c.Header("Content-Disposition", `attachment; filename="invite.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", booking.Invite(*b, mentor, student, h.mailFrom))
+}
+
+// @Summary Reschedule booking
+// @Description Move a booking to another open slot of the same mentor. The user and the mentor receive an updated invitation.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param booking body models.RescheduleBookingRequest true "New start"
+// @Success 200 {object} models.APIResponse{data=models.Booking}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /bookings/{id}/reschedule [post]
+func (h *BookingHandler) RescheduleBooking(c *gin.Context) {
+	current, ok := h.findBooking(c)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	if _, err := booking.Reschedule(h.db, current.ID, req.StartsAt); err != nil {
+		respondBookingError(c, err, "Failed to reschedule booking")
+		return
+	}
+
+	b, err := h.load(current.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch booking",
+		})
+		return
+	}
+	h.notify(booking.ChangeRescheduled, *b)
+	h.hideMentorContact(c, b)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Booking rescheduled successfully",
+		Data:    b,
+	})
+}
+
+// @Summary Cancel booking
+// @Description Cancel a booking that has not ended, freeing its slot. The user and the mentor receive a cancellation.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param booking body models.CancelBookingRequest false "Reason"
+// @Success 200 {object} models.APIResponse{data=models.Booking}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /bookings/{id}/cancel [post]
+func (h *BookingHandler) CancelBooking(c *gin.Context) {
+	current, ok := h.findBooking(c)
+	if !ok {
+		return
+	}
+
+	var req models.CancelBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid request data",
+			})
+			return
+		}
+	}
+
+	if _, err := booking.Cancel(h.db, current.ID, currentUserID(c), req.Reason); err != nil {
+		respondBookingError(c, err, "Failed to cancel booking")
+		return
+	}
+
+	b, err := h.load(current.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch booking",
+		})
+		return
+	}
+	h.notify(booking.ChangeCancelled, *b)
+	h.hideMentorContact(c, b)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Booking cancelled successfully",
+		Data:    b,
+	})
+}
+
+// findMentor loads the mentor named by the id parameter, answering 404 when it is missing
+func (h *BookingHandler) findMentor(c *gin.Context) (*models.Mentor, bool) {
+	var mentor models.Mentor
+	if err := h.db.Where("id = ?", c.Param("id")).First(&mentor).Error; err != nil {
+		respondMentorNotFound(c)
+		return nil, false
+	}
+	return &mentor, true
+}
+
+// findBooking loads the booking named by the id parameter. Bookings the current user
+// takes no part in are reported as missing.
+func (h *BookingHandler) findBooking(c *gin.Context) (*models.Booking, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	var b *models.Booking
+	if err == nil {
+		b, err = h.load(id)
+	}
+	if err != nil || !canAccessBooking(c, b) {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Booking not found",
+		})
+		return nil, false
+	}
+	return b, true
+}
+
+func (h *BookingHandler) load(id uuid.UUID) (*models.Booking, error) {
+	var b models.Booking
+	if err := preloadParties(h.db).Where("id = ?", id).First(&b).Error; err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// preloadParties loads the mentor and user of bookings, including deleted ones
+func preloadParties(query *gorm.DB) *gorm.DB {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	return query.Preload("Mentor", unscoped).Preload("User", unscoped)
+}
+
+// parties returns the mentor and user of a booking with the contact details emails and
+// invitations need
+func (h *BookingHandler) parties(b models.Booking) (models.Mentor, models.User) {
+	var mentor models.Mentor
+	if b.Mentor != nil {
+		mentor = *b.Mentor
+	}
+	var student models.User
+	h.db.Unscoped().Select("id", "name", "email").Where("id = ?", b.UserID).First(&student)
+	return mentor, student
+}
+
+// notify emails the user and the mentor about a change to a booking
+func (h *BookingHandler) notify(change string, b models.Booking) {
+	mentor, student := h.parties(b)
+	for _, msg := range booking.Messages(change, b, mentor, student, h.mailFrom) {
+		h.mailer.SendAsync(msg)
+	}
+}
+
+// hideMentorContact keeps the mentor's contact details for staff only
+func (h *BookingHandler) hideMentorContact(c *gin.Context, b *models.Booking) {
+	if b.Mentor != nil && !isStaff(c) {
+		b.Mentor.HidePrivate()
+	}
+}
+
+// isStaff reports whether the current user is an admin or editor
+func isStaff(c *gin.Context) bool {
+	return auth.HasAnyRole(currentUserRoles(c), []string{"admin", "editor"})
+}
+
+// canManageMentor reports whether the current user may manage a mentor's availability
+// and calendar: the mentor's own account, admins and editors
+func canManageMentor(c *gin.Context, mentor *models.Mentor) bool {
+	return isStaff(c) || (mentor.UserID != nil && *mentor.UserID == currentUserID(c))
+}
+
+// canAccessBooking reports whether the current user takes part in a booking, as the
+// user who booked it or as the mentor, or is staff
+func canAccessBooking(c *gin.Context, b *models.Booking) bool {
+	if isStaff(c) || b.UserID == currentUserID(c) {
+		return true
+	}
+	return b.Mentor != nil && b.Mentor.UserID != nil && *b.Mentor.UserID == currentUserID(c)
+}
+
+// parseTimeParam accepts an RFC 3339 time or a date, taken as midnight UTC
+func parseTimeParam(value string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func respondBookingError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Booking not found",
+		})
+	case errors.Is(err, booking.ErrPast), errors.Is(err, booking.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	case errors.Is(err, booking.ErrSlotUnavailable), errors.Is(err, booking.ErrConflict), errors.Is(err, booking.ErrClosed):
+		c.JSON(http.StatusConflict, models.APIResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: message,
+		})
+	}
+}
+
+func respondInvalidPeriod(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, models.APIResponse{
+		Success: false,
+		Message: "from and to must be RFC 3339 times or dates, with to after from and at most 62 days apart",
+	})
+}
+
+func respondMentorNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: "Mentor not found",
+	})
+}
+
+func respondForbidden(c *gin.Context) {
+	c.JSON(http.StatusForbidden, models.APIResponse{
+		Success: false,
+		Message: "Insufficient permissions",
+	})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"msc-backend-api/internal/catalog"
+	"msc-backend-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxImportSize is the largest catalog file accepted by the import endpoint
+const maxImportSize = 20 << 20
+
+type CatalogHandler struct {
+	db *gorm.DB
+}
+
+func NewCatalogHandler(db *gorm.DB) *CatalogHandler {
+	return &CatalogHandler{db: db}
+}
+
+// @Summary Import catalog data
+// @Description Upsert programs, mentors, projects or allblogposts from a CSV or JSON file, matched by slug (email for mentors). The file is applied in one transaction; with dry_run=true it is only validated.
+// @Tags catalog
+// @Accept mpfd
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param kind path string true "Content type (programs, mentors, projects, allblogposts)"
+// @Param file formData file false "CSV or JSON file; the request body is read when omitted"
+// @Param format query string false "csv or json; defaults to the file extension or Content-Type"
+// @Param dry_run query bool false "Validate without saving"
+// @Success 200 {object} models.APIResponse{data=catalog.Report}
+// @Failure 400 {object} models.APIResponse{data=catalog.Report}
+// @Router /catalog/{kind}/import [post]
+func (h *CatalogHandler) ImportCatalog(c *gin.Context) {
+	kind, err := catalog.Lookup(c.Param("kind"))
+	if err != nil {
+		respondUnknownCatalogKind(c)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	body, formatHint, err := importFile(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Cannot read the uploaded file",
+		})
+		return
+	}
+	defer body.Close()
+
+	if c.Query("format") != "" {
+		formatHint = c.Query("format")
+	}
+	format, err := catalog.ParseFormat(formatHint)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Format must be csv or json",
+		})
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	report, err := catalog.Import(h.db, kind, format, body, dryRun)
+	if err != nil {
+		if errors.Is(err, catalog.ErrInvalidFile) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to import " + kind.Name,
+		})
+		return
+	}
+
+	if len(report.Errors) > 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: fmt.Sprintf("%d rows have errors; nothing was saved", len(report.Errors)),
+			Data:    report,
+		})
+		return
+	}
+
+	message := fmt.Sprintf("Imported %d %s: %d created, %d updated", report.Rows, kind.Name, report.Created, report.Updated)
+	if dryRun {
+		message = fmt.Sprintf("Dry run: %d would be created, %d updated; nothing was saved", report.Created, report.Updated)
+	}
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// @Summary Export catalog data
+// @Description Stream all live programs, mentors, projects or allblogposts as CSV or JSON, in the format the import accepts
+// @Tags catalog
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param kind path string true "Content type (programs, mentors, projects, allblogposts)"
+// @Param format query string false "csv or json" default(csv)
+// @Success 200 {string} string "Export file"
+// @Failure 400 {object} models.APIResponse
+// @Router /catalog/{kind}/export [get]
+func (h *CatalogHandler) ExportCatalog(c *gin.Context) {
+	kind, err := catalog.Lookup(c.Param("kind"))
+	if err != nil {
+		respondUnknownCatalogKind(c)
+		return
+	}
+	format, err := catalog.ParseFormat(c.DefaultQuery("format", string(catalog.FormatCSV)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Format must be csv or json",
+		})
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == catalog.FormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, kind.Name, format))
+	c.Status(http.StatusOK)
+
+	// The status is sent with the first batch, so a failure can only cut the file short
+	if err := catalog.Export(h.db, kind, format, c.Writer); err != nil {
+		log.Printf("Export of %s failed: %v", kind.Name, err)
+		c.Abort()
+	}
+}
+
+// importFile returns the uploaded file, or the request body when no multipart file was
+// sent, with a hint of its format
+func importFile(c *gin.Context) (io.ReadCloser, string, error) {
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		return f, file.Filename, err
+	}
+	return c.Request.Body, c.ContentType(), nil
+}
+
+func respondUnknownCatalogKind(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: "Unknown catalog type",
+	})
+}
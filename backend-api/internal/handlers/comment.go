@@ -0,0 +1,456 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/comments"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/auth"
+	"msc-backend-api/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommentHandler struct {
+	db         *gorm.DB
+	filter     *comments.Filter
+	rateLimit  int
+	rateWindow time.Duration
+}
+
+func NewCommentHandler(db *gorm.DB, cfg *config.Config) *CommentHandler {
+	return &CommentHandler{
+		db:         db,
+		filter:     comments.NewFilter(cfg.CommentBannedWords),
+		rateLimit:  cfg.CommentRateLimit,
+		rateWindow: cfg.CommentRateWindow,
+	}
+}
+
+// @Summary Get blog post comments
+// @Description Get the approved comments of a blog post as threads, oldest first
+// @Tags comments
+// @Produce json
+// @Param id path string true "Blog Post ID"
+// @Success 200 {object} models.APIResponse{data=[]models.Comment}
+// @Failure 404 {object} models.APIResponse
+// @Router /api/allblogposts/{id}/comments [get]
+func (h *CommentHandler) GetBlogPostComments(c *gin.Context) {
+	blogPost, ok := h.findBlogPost(c)
+	if !ok {
+		return
+	}
+
+	var list []models.Comment
+	if err := h.db.Preload("User").
+		Where("blog_post_id = ? AND status = ?", blogPost.ID, models.CommentStatusApproved).
+		Order("created_at ASC").
+		Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch comments",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    comments.Thread(list),
+	})
+}
+
+// @Summary Comment on a blog post
+// @Description Post a comment or a reply. Comments wait for moderation unless written by an editor; comments with banned words are marked as spam.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Blog Post ID"
+// @Param comment body models.CreateCommentRequest true "Comment"
+// @Success 201 {object} models.APIResponse{data=models.Comment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 429 {object} models.APIResponse
+// @Router /api/allblogposts/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	blogPost, ok := h.findBlogPost(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Comment cannot be empty",
+		})
+		return
+	}
+
+	// Replies go under an approved comment of the same post
+	if req.ParentID != nil {
+		var parent models.Comment
+		if err := h.db.Where("id = ? AND blog_post_id = ? AND status = ?", *req.ParentID, blogPost.ID, models.CommentStatusApproved).
+			First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Parent comment not found",
+			})
+			return
+		}
+	}
+
+	userID := currentUserID(c)
+	var recent int64
+	if err := h.db.Model(&models.Comment{}).
+		Where("user_id = ? AND created_at > ?", userID, time.Now().Add(-h.rateWindow)).
+		Count(&recent).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to post comment",
+		})
+		return
+	}
+	if recent >= int64(h.rateLimit) {
+		c.Header("Retry-After", strconv.Itoa(int(h.rateWindow.Seconds())))
+		c.JSON(http.StatusTooManyRequests, models.APIResponse{
+			Success: false,
+			Message: "You are commenting too fast, please try again later",
+		})
+		return
+	}
+
+	comment := models.Comment{
+		BlogPostID: blogPost.ID,
+		ParentID:   req.ParentID,
+		UserID:     userID,
+		Content:    req.Content,
+		Status:     models.CommentStatusPending,
+	}
+	message := "Comment submitted for moderation"
+	if _, banned := h.filter.Match(req.Content); banned {
+		comment.Status = models.CommentStatusSpam
+	} else if auth.HasRole(currentUserRoles(c), "admin") || auth.HasRole(currentUserRoles(c), "editor") {
+		comment.Status = models.CommentStatusApproved
+		message = "Comment posted successfully"
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		if comment.Status != models.CommentStatusApproved {
+			return nil
+		}
+		comment.User = &models.CommentAuthor{}
+		if err := tx.First(comment.User, "id = ?", userID).Error; err != nil {
+			return err
+		}
+		return comments.NotifyAuthor(tx, comment)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to post comment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    comment,
+	})
+}
+
+// @Summary Get comments for moderation
+// @Description Get comments by moderation status, newest first
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending (default), approved, rejected or spam"
+// @Param blog_post_id query int false "Only comments of this blog post"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /comments [get]
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	status := c.DefaultQuery("status", models.CommentStatusPending)
+	switch status {
+	case models.CommentStatusPending, models.CommentStatusApproved, models.CommentStatusRejected, models.CommentStatusSpam:
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid status",
+		})
+		return
+	}
+
+	query := h.db.Model(&models.Comment{}).Where("status = ?", status)
+	if blogPostID := c.Query("blog_post_id"); blogPostID != "" {
+		query = query.Where("blog_post_id = ?", blogPostID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var list []models.Comment
+	if err := query.
+		Preload("User").
+		Preload("BlogPost", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Select("id", "slug", "title")
+		}).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch comments",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.PaginatedResponse{
+			Data:       list,
+			Total:      total,
+			Page:       page,
+			Limit:      limit,
+			TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+		},
+	})
+}
+
+// @Summary Approve comment
+// @Description Publish a comment on the blog and notify the post's author
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} models.APIResponse{data=models.Comment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /comments/{id}/approve [patch]
+func (h *CommentHandler) ApproveComment(c *gin.Context) {
+	h.moderate(c, models.CommentStatusApproved, "Comment approved successfully")
+}
+
+// @Summary Reject comment
+// @Description Hide a comment from the blog
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} models.APIResponse{data=models.Comment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /comments/{id}/reject [patch]
+func (h *CommentHandler) RejectComment(c *gin.Context) {
+	h.moderate(c, models.CommentStatusRejected, "Comment rejected successfully")
+}
+
+// @Summary Mark comment as spam
+// @Description Hide a comment from the blog and file it as spam
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} models.APIResponse{data=models.Comment}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /comments/{id}/spam [patch]
+func (h *CommentHandler) MarkCommentSpam(c *gin.Context) {
+	h.moderate(c, models.CommentStatusSpam, "Comment marked as spam")
+}
+
+// @Summary Delete comment
+// @Description Permanently delete a comment and its replies
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Comment ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCommentNotFound(c)
+		return
+	}
+
+	result := h.db.Exec(`DELETE FROM comments WHERE id IN (
+		WITH RECURSIVE thread AS (
+			SELECT id FROM comments WHERE id = ?
+			UNION ALL
+			SELECT comments.id FROM comments JOIN thread ON comments.parent_id = thread.id
+		)
+		SELECT id FROM thread
+	)`, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to delete comment",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondCommentNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Comment deleted successfully",
+	})
+}
+
+// moderate moves a comment to a new moderation status, guarding on the old one so two
+// moderators can't act on the same comment at once
+func (h *CommentHandler) moderate(c *gin.Context, status, message string) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondCommentNotFound(c)
+		return
+	}
+
+	var comment models.Comment
+	if err := h.db.Preload("User").Where("id = ?", id).First(&comment).Error; err != nil {
+		respondCommentNotFound(c)
+		return
+	}
+	if comment.Status == status {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Comment is already " + status,
+		})
+		return
+	}
+
+	moderatorID := currentUserID(c)
+	now := time.Now()
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Comment{}).
+			Where("id = ? AND status = ?", comment.ID, comment.Status).
+			Updates(map[string]interface{}{
+				"status":       status,
+				"moderated_by": moderatorID,
+				"moderated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStatusConflict
+		}
+
+		// Notify on the first moderation only, so a comment that is approved, rejected
+		// and approved again is announced once
+		if status == models.CommentStatusApproved && comment.ModeratedAt == nil {
+			return comments.NotifyAuthor(tx, comment)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errStatusConflict) {
+			c.JSON(http.StatusConflict, models.APIResponse{
+				Success: false,
+				Message: "Comment was moderated by someone else, reload and try again",
+			})
+			return
+		}
+		log.Printf("Cannot moderate comment %s: %v", comment.ID, err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to moderate comment",
+		})
+		return
+	}
+
+	comment.Status = status
+	comment.ModeratedBy = &moderatorID
+	comment.ModeratedAt = &now
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    comment,
+	})
+}
+
+// findBlogPost loads the live blog post of the :id parameter or writes a 404
+func (h *CommentHandler) findBlogPost(c *gin.Context) (models.AllBlogPost, bool) {
+	var blogPost models.AllBlogPost
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		err = gorm.ErrRecordNotFound
+	} else {
+		err = h.db.Select("id", "slug", "title").Where("id = ?", id).First(&blogPost).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Message: "Blog post not found",
+			})
+			return blogPost, false
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch blog post",
+		})
+		return blogPost, false
+	}
+	return blogPost, true
+}
+
+// loadCommentCounts returns the approved comment counts of a page of blog posts
+func loadCommentCounts(db *gorm.DB, ids []uint) map[uint]int64 {
+	counts, err := comments.Counts(db, ids)
+	if err != nil {
+		log.Printf("Cannot count comments: %v", err)
+	}
+	return counts
+}
+
+func respondCommentNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: "Comment not found",
+	})
+}
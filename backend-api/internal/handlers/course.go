@@ -1,11 +1,19 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"msc-backend-api/internal/i18n"
+	"msc-backend-api/internal/mentors"
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/revisions"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/textsearch"
 	"msc-backend-api/pkg/auth"
+	"msc-backend-api/pkg/workflow"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,8 +36,10 @@ func NewCourseHandler(db *gorm.DB) *CourseHandler {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param status query string false "Filter by status"
-// @Param category query string false "Filter by category"
-// @Param search query string false "Search in title and description"
+// @Param category query string false "Filter by category slug or name, including subcategories"
+// @Param tag query string false "Filter by tag slug"
+// @Param search query string false "Full-text search (accent-insensitive), results ranked by relevance"
+// @Param sort query string false "rating for the best rated first, reviews for the most reviewed first"
 // @Success 200 {object} models.PaginatedResponse
 // @Failure 401 {object} models.APIResponse
 // @Router /courses [get]
@@ -37,7 +47,6 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	status := c.Query("status")
-	category := c.Query("category")
 	search := c.Query("search")
 
 	if page < 1 {
@@ -47,6 +56,11 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 		limit = 10
 	}
 
+	order, ok := ratingOrder(c)
+	if !ok {
+		return
+	}
+
 	offset := (page - 1) * limit
 
 	query := h.db.Model(&models.Course{}).Preload("Author")
@@ -55,11 +69,12 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
-	if category != "" {
-		query = query.Where("category = ?", category)
+	query = filterTaxonomy(c, query, taxonomy.Courses)
+	if order != "" {
+		query = query.Order(order)
 	}
 	if search != "" {
-		query = query.Where("title ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Where(textsearch.Courses.Match(search)).Order(textsearch.Courses.Rank(search))
 	}
 
 	// Check user role for filtering
@@ -75,6 +90,7 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 
 	var total int64
 	query.Count(&total)
+	facets := listFacets(h.db, query, taxonomy.Courses)
 
 	var courses []models.Course
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&courses).Error; err != nil {
@@ -85,6 +101,31 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 		return
 	}
 
+	if search != "" && len(courses) > 0 {
+		ids := make([]uuid.UUID, len(courses))
+		for i := range courses {
+			ids[i] = courses[i].ID
+		}
+		snippets := searchSnippets(h.db, textsearch.Courses, search, ids)
+		for i := range courses {
+			courses[i].Snippet = snippets[courses[i].ID.String()]
+		}
+	}
+
+	ids := make([]string, len(courses))
+	for i := range courses {
+		ids[i] = courses[i].ID.String()
+	}
+	translations := loadTranslations(c, h.db, i18n.Courses, ids)
+	for i := range courses {
+		i18n.Apply(&courses[i], translations[ids[i]])
+	}
+	taxonomies := loadTaxonomy(h.db, taxonomy.Courses, ids)
+	for i := range courses {
+		tax := taxonomies[ids[i]]
+		courses[i].Categories, courses[i].Tags = tax.Categories, tax.Tags
+	}
+
 	totalPages := (int(total) + limit - 1) / limit
 
 	c.JSON(http.StatusOK, models.PaginatedResponse{
@@ -93,21 +134,69 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		Facets:     facets,
+	})
+}
+
+// @Summary Get my courses
+// @Description Get the courses the signed-in user is enrolled in, most recently enrolled first, with their progress, categories and rating
+// @Tags courses
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=[]models.Enrollment}
+// @Failure 401 {object} models.APIResponse
+// @Router /courses/enrolled [get]
+func (h *CourseHandler) GetEnrolledCourses(c *gin.Context) {
+	var enrollments []models.Enrollment
+	if err := h.db.
+		Joins("JOIN courses ON courses.id = enrollments.course_id AND courses.deleted_at IS NULL").
+		Preload("Course").
+		Preload("Course.Author", func(db *gorm.DB) *gorm.DB { return db.Select("id", "name") }).
+		Where("enrollments.user_id = ?", currentUserID(c)).
+		Order("enrollments.enrolled_at DESC").
+		Find(&enrollments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch courses",
+		})
+		return
+	}
+
+	ids := make([]string, len(enrollments))
+	for i := range enrollments {
+		ids[i] = enrollments[i].CourseID.String()
+	}
+	translations := loadTranslations(c, h.db, i18n.Courses, ids)
+	taxonomies := loadTaxonomy(h.db, taxonomy.Courses, ids)
+	for i := range enrollments {
+		course := &enrollments[i].Course
+		i18n.Apply(course, translations[ids[i]])
+		course.Categories, course.Tags = taxonomies[ids[i]].Categories, taxonomies[ids[i]].Tags
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    enrollments,
 	})
 }
 
 // @Summary Get single course
-// @Description Get course details by ID
+// @Description Get course details by ID, including the assigned mentors
 // @Tags courses
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Course ID"
+// @Param render query string false "Include rendered lesson content (html or text)"
 // @Success 200 {object} models.APIResponse{data=models.Course}
 // @Failure 401 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
 // @Router /courses/{id} [get]
 func (h *CourseHandler) GetCourse(c *gin.Context) {
 	id := c.Param("id")
+	render, ok := renderMode(c)
+	if !ok {
+		return
+	}
 
 	var course models.Course
 	if err := h.db.Preload("Author").Preload("Lessons").Where("id = ?", id).First(&course).Error; err != nil {
@@ -118,6 +207,20 @@ func (h *CourseHandler) GetCourse(c *gin.Context) {
 		return
 	}
 
+	renderLessons(render, course.Lessons)
+
+	i18n.Apply(&course, itemTranslations(c, h.db, i18n.Courses, course.ID.String()))
+	course.Categories, course.Tags = itemTaxonomy(h.db, taxonomy.Courses, course.ID.String())
+	assigned, err := mentors.LoadAssigned(h.db, models.EntityTypeCourse, course.ID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch course mentors",
+		})
+		return
+	}
+	course.Mentors = assigned
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    course,
@@ -149,38 +252,43 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 	userIDStr := userID.(string)
 	authorID, _ := uuid.Parse(userIDStr)
 
-	// Check if slug is unique
-	var existingCourse models.Course
-	if err := h.db.Where("slug = ?", req.Slug).First(&existingCourse).Error; err == nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Slug already exists",
-		})
+	// The slug is optional; a taken one gets a numeric suffix
+	base := slugBase(req.Slug, req.Title)
+	if base == "" {
+		respondInvalidSlug(c)
 		return
 	}
 
-	// Set default status for partners
-	if req.Status == "" {
-		userRoles, _ := c.Get("user_roles")
-		if userRolesList, ok := userRoles.([]string); ok {
-			if auth.HasRole(userRolesList, "partner") {
-				req.Status = "pending_review"
-			} else {
-				req.Status = "draft"
-			}
-		}
+	// New content starts as a draft (or in review for partners); publishing needs a workflow action
+	status, ok := checkInitialStatus(c, req.Status)
+	if !ok {
+		return
+	}
+	if !checkScheduleWindow(c, status, req.PublishAt, req.UnpublishAt) {
+		return
 	}
 
 	course := models.Course{
 		Title:        req.Title,
-		Slug:         req.Slug,
 		Description:  req.Description,
 		ThumbnailURL: req.ThumbnailURL,
-		Status:       req.Status,
+		Status:       status,
+		PublishAt:    req.PublishAt,
+		UnpublishAt:  req.UnpublishAt,
 		AuthorID:     authorID,
 	}
 
-	if err := h.db.Create(&course).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if course.Slug, err = uniqueSlug(tx, "courses", base); err != nil {
+			return err
+		}
+		if err := tx.Create(&course).Error; err != nil {
+			return err
+		}
+		_, err = revisions.Save(tx, models.EntityTypeCourse, course.ID, authorID, course.Snapshot(), nil)
+		return err
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to create course",
@@ -199,7 +307,7 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 }
 
 // @Summary Update course
-// @Description Update course by ID
+// @Description Update course by ID. A partner's edit to a scheduled or published course sends it back to review.
 // @Tags courses
 // @Accept json
 // @Produce json
@@ -246,28 +354,34 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 		return
 	}
 
-	// Check if new slug is unique (if changed)
-	if req.Slug != course.Slug {
-		var existingCourse models.Course
-		if err := h.db.Where("slug = ? AND id != ?", req.Slug, id).First(&existingCourse).Error; err == nil {
-			c.JSON(http.StatusBadRequest, models.APIResponse{
-				Success: false,
-				Message: "Slug already exists",
-			})
-			return
-		}
+	if !checkUnchangedStatus(c, course.Status, req.Status) || !checkScheduleWindow(c, course.Status, req.PublishAt, req.UnpublishAt) {
+		return
+	}
+
+	// Keep the slug unless a new one is given
+	courseSlug, ok := checkSlugChange(c, h.db, "courses", course.ID.String(), course.Slug, req.Slug)
+	if !ok {
+		return
 	}
 
 	// Update course
 	course.Title = req.Title
-	course.Slug = req.Slug
+	course.Slug = courseSlug
 	course.Description = req.Description
 	course.ThumbnailURL = req.ThumbnailURL
-	if req.Status != "" {
-		course.Status = req.Status
-	}
+	course.PublishAt = req.PublishAt
+	course.UnpublishAt = req.UnpublishAt
 
-	if err := h.db.Save(&course).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := resubmitEdited(c, tx, models.EntityTypeCourse, course.ID, &course.Status); err != nil {
+			return err
+		}
+		if err := tx.Save(&course).Error; err != nil {
+			return err
+		}
+		_, err := revisions.Save(tx, models.EntityTypeCourse, course.ID, currentUserID(c), course.Snapshot(), nil)
+		return err
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to update course",
@@ -286,7 +400,7 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 }
 
 // @Summary Delete course
-// @Description Delete course by ID
+// @Description Move course to trash
 // @Tags courses
 // @Produce json
 // @Security BearerAuth
@@ -308,86 +422,350 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Course deleted successfully",
+		Message: "Course moved to trash",
 	})
 }
 
 // @Summary Approve course
-// @Description Approve a pending course
+// @Description Publish a course that is pending review
 // @Tags courses
+// @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Course ID"
+// @Param review body models.ReviewRequest false "Optional review note"
 // @Success 200 {object} models.APIResponse{data=models.Course}
+// @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
 // @Router /courses/{id}/approve [patch]
 func (h *CourseHandler) ApproveCourse(c *gin.Context) {
-	id := c.Param("id")
+	h.transitionCourse(c, workflow.ActionApprove, "Course approved successfully")
+}
 
-	var course models.Course
-	if err := h.db.Where("id = ?", id).First(&course).Error; err != nil {
-		c.JSON(http.StatusNotFound, models.APIResponse{
+// @Summary Reject course
+// @Description Send a pending course back to draft; a reason is required
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param review body models.ReviewRequest true "Rejection reason"
+// @Success 200 {object} models.APIResponse{data=models.Course}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /courses/{id}/reject [patch]
+func (h *CourseHandler) RejectCourse(c *gin.Context) {
+	h.transitionCourse(c, workflow.ActionReject, "Course rejected successfully")
+}
+
+// @Summary Archive course
+// @Description Archive a course from any active status
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param review body models.ReviewRequest false "Optional note"
+// @Success 200 {object} models.APIResponse{data=models.Course}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /courses/{id}/archive [patch]
+func (h *CourseHandler) ArchiveCourse(c *gin.Context) {
+	h.transitionCourse(c, workflow.ActionArchive, "Course archived successfully")
+}
+
+// @Summary Run course workflow action
+// @Description Apply a workflow action (submit, approve, reject, schedule, unschedule, publish, unpublish, archive, restore) to a course
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param transition body models.TransitionRequest true "Workflow action"
+// @Success 200 {object} models.APIResponse{data=models.Course}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /courses/{id}/transitions [post]
+func (h *CourseHandler) TransitionCourse(c *gin.Context) {
+	var req models.TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
-			Message: "Course not found",
+			Message: "Invalid request data",
 		})
 		return
 	}
 
-	course.Status = "published"
-	if err := h.db.Save(&course).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Message: "Failed to approve course",
-		})
+	course, ok := h.findAccessibleCourse(c)
+	if !ok || !checkSchedulable(c, req.Action, course.PublishAt) {
 		return
 	}
 
-	h.db.Preload("Author").First(&course, course.ID)
+	if _, ok := changeStatus(c, h.db, course, models.EntityTypeCourse, course.ID, course.Status, req.Action, req.Reason); !ok {
+		return
+	}
+
+	h.db.Preload("Author").First(course, course.ID)
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Course approved successfully",
+		Message: "Course status updated successfully",
 		Data:    course,
 	})
 }
 
-// @Summary Reject course
-// @Description Reject a pending course
+// @Summary Get course status history
+// @Description List the workflow transitions of a course and the actions available to the caller
+// @Tags courses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/history [get]
+func (h *CourseHandler) GetCourseHistory(c *gin.Context) {
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
+
+	respondStatusHistory(c, h.db, models.EntityTypeCourse, course.ID, course.Status)
+}
+
+// transitionCourse backs the fixed-action endpoints; the review note in the body is optional
+func (h *CourseHandler) transitionCourse(c *gin.Context, action, message string) {
+	var req models.ReviewRequest
+	_ = c.ShouldBindJSON(&req) // body is optional
+
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := changeStatus(c, h.db, course, models.EntityTypeCourse, course.ID, course.Status, action, req.Reason); !ok {
+		return
+	}
+
+	h.db.Preload("Author").First(course, course.ID)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    course,
+	})
+}
+
+// @Summary Get course revisions
+// @Description List every stored revision of a course, newest first
+// @Tags courses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.APIResponse{data=[]models.Revision}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/revisions [get]
+func (h *CourseHandler) GetCourseRevisions(c *gin.Context) {
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
+
+	respondRevisions(c, h.db, models.EntityTypeCourse, course.ID)
+}
+
+// @Summary Get course revision
+// @Description Get a single revision of a course with its full snapshot
 // @Tags courses
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Course ID"
+// @Param version path int true "Revision version"
+// @Success 200 {object} models.APIResponse{data=models.Revision}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/revisions/{version} [get]
+func (h *CourseHandler) GetCourseRevision(c *gin.Context) {
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
+
+	respondRevision(c, h.db, models.EntityTypeCourse, course.ID)
+}
+
+// @Summary Diff course revisions
+// @Description Compare two revisions of a course field by field
+// @Tags courses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param from query int false "Source version (defaults to the one before to)"
+// @Param to query int false "Target version (defaults to the latest)"
+// @Success 200 {object} models.APIResponse{data=RevisionDiff}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/revisions/diff [get]
+func (h *CourseHandler) DiffCourseRevisions(c *gin.Context) {
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
+
+	respondRevisionDiff(c, h.db, models.EntityTypeCourse, course.ID)
+}
+
+// @Summary Restore course revision
+// @Description Restore the content of an old revision; the result is stored as a new revision. Restored by a partner, a scheduled or published course goes back to review.
+// @Tags courses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param version path int true "Revision version"
 // @Success 200 {object} models.APIResponse{data=models.Course}
+// @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
-// @Router /courses/{id}/reject [patch]
-func (h *CourseHandler) RejectCourse(c *gin.Context) {
-	id := c.Param("id")
+// @Router /courses/{id}/revisions/{version}/restore [post]
+func (h *CourseHandler) RestoreCourseRevision(c *gin.Context) {
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
 
-	var course models.Course
-	if err := h.db.Where("id = ?", id).First(&course).Error; err != nil {
-		c.JSON(http.StatusNotFound, models.APIResponse{
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
-			Message: "Course not found",
+			Message: "Invalid revision version",
 		})
 		return
 	}
 
-	course.Status = "draft"
-	if err := h.db.Save(&course).Error; err != nil {
+	revision, err := findRevision(h.db, models.EntityTypeCourse, course.ID, version)
+	if err != nil {
+		respondRevisionLookupError(c, err)
+		return
+	}
+
+	var snapshot models.CourseSnapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
-			Message: "Failed to reject course",
+			Message: "Failed to read revision",
 		})
 		return
 	}
 
-	h.db.Preload("Author").First(&course, course.ID)
+	// The old slug may have been taken by another course since
+	if snapshot.Slug != course.Slug {
+		var existingCourse models.Course
+		if err := h.db.Unscoped().Where("slug = ? AND id != ?", snapshot.Slug, course.ID).First(&existingCourse).Error; err == nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Slug already exists",
+			})
+			return
+		}
+	}
+
+	course.ApplySnapshot(snapshot)
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := resubmitEdited(c, tx, models.EntityTypeCourse, course.ID, &course.Status); err != nil {
+			return err
+		}
+		if err := tx.Save(course).Error; err != nil {
+			return err
+		}
+		_, err := revisions.Save(tx, models.EntityTypeCourse, course.ID, currentUserID(c), course.Snapshot(), &revision.Version)
+		return err
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to restore revision",
+		})
+		return
+	}
+
+	h.db.Preload("Author").First(course, course.ID)
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Course rejected successfully",
+		Message: fmt.Sprintf("Course restored from revision %d", revision.Version),
 		Data:    course,
 	})
 }
+
+// @Summary Get course mentors
+// @Description Get the mentors assigned to a course, in display order
+// @Tags courses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.APIResponse{data=[]models.AssignedMentor}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/mentors [get]
+func (h *CourseHandler) GetCourseMentors(c *gin.Context) {
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
+	respondAssignedMentors(c, h.db, models.EntityTypeCourse, course.ID)
+}
+
+// @Summary Set course mentors
+// @Description Replace the mentors assigned to a course, in display order. Roles are lead_instructor or assistant (default).
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param mentors body models.SetAssignmentsRequest true "Mentors"
+// @Success 200 {object} models.APIResponse{data=[]models.AssignedMentor}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/mentors [put]
+func (h *CourseHandler) SetCourseMentors(c *gin.Context) {
+	course, ok := h.findAccessibleCourse(c)
+	if !ok {
+		return
+	}
+	setAssignedMentors(c, h.db, models.EntityTypeCourse, course.ID, "Course not found")
+}
+
+// findAccessibleCourse loads the course from the :id path param and checks that a partner
+// only reaches their own courses. It writes the error response itself when it fails.
+func (h *CourseHandler) findAccessibleCourse(c *gin.Context) (*models.Course, bool) {
+	var course models.Course
+	if err := h.db.Where("id = ?", c.Param("id")).First(&course).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Course not found",
+		})
+		return nil, false
+	}
+
+	if !canAccessOwned(c, course.AuthorID) {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Message: "You can only access your own courses",
+		})
+		return nil, false
+	}
+
+	return &course, true
+}
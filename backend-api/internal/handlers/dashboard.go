@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/workflow"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -43,8 +44,8 @@ func (h *DashboardHandler) GetStats(c *gin.Context) {
 
 	// Count pending reviews (courses and posts)
 	var pendingCourses, pendingPosts int64
-	h.db.Model(&models.Course{}).Where("status = ?", "pending_review").Count(&pendingCourses)
-	h.db.Model(&models.Post{}).Where("status = ?", "pending_review").Count(&pendingPosts)
+	h.db.Model(&models.Course{}).Where("status = ?", workflow.StatusPendingReview).Count(&pendingCourses)
+	h.db.Model(&models.Post{}).Where("status = ?", workflow.StatusPendingReview).Count(&pendingPosts)
 	stats.PendingReviews = pendingCourses + pendingPosts
 
 	// TODO: Calculate revenue from payment system
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"msc-backend-api/internal/engagement"
+	"msc-backend-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type EngagementHandler struct {
+	db      *gorm.DB
+	tracker *engagement.Tracker
+}
+
+func NewEngagementHandler(db *gorm.DB, tracker *engagement.Tracker) *EngagementHandler {
+	return &EngagementHandler{db: db, tracker: tracker}
+}
+
+// @Summary Get views and likes
+// @Description Get the view and like counts of a blog post, program or project and whether the current visitor likes it
+// @Tags engagement
+// @Produce json
+// @Param type path string true "Content type (allblogposts, programs, projects)"
+// @Param id path string true "Content ID"
+// @Success 200 {object} models.APIResponse{data=models.Engagement}
+// @Failure 404 {object} models.APIResponse
+// @Router /api/engagement/{type}/{id} [get]
+func (h *EngagementHandler) GetEngagement(c *gin.Context) {
+	t, id, ok := lookupEngagementTarget(c)
+	if !ok {
+		return
+	}
+	h.respondEngagement(c, t, id, "")
+}
+
+// @Summary Record a view
+// @Description Count a view of a blog post, program or project. Repeat views by the same visitor within the view window are not counted.
+// @Tags engagement
+// @Produce json
+// @Param type path string true "Content type (allblogposts, programs, projects)"
+// @Param id path string true "Content ID"
+// @Success 200 {object} models.APIResponse{data=models.Engagement}
+// @Failure 404 {object} models.APIResponse
+// @Router /api/engagement/{type}/{id}/view [post]
+func (h *EngagementHandler) RecordView(c *gin.Context) {
+	t, id, ok := lookupEngagementTarget(c)
+	if !ok {
+		return
+	}
+
+	exists, err := engagement.Exists(h.db, t, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to record view",
+		})
+		return
+	}
+	if !exists {
+		respondEngagementNotFound(c, "Item not found")
+		return
+	}
+
+	h.tracker.RecordView(t, id, visitorOf(c))
+	h.respondEngagement(c, t, id, "")
+}
+
+// @Summary Like
+// @Description Like a blog post, program or project. Each signed-in user or anonymous visitor likes an item once.
+// @Tags engagement
+// @Produce json
+// @Param type path string true "Content type (allblogposts, programs, projects)"
+// @Param id path string true "Content ID"
+// @Success 200 {object} models.APIResponse{data=models.Engagement}
+// @Failure 404 {object} models.APIResponse
+// @Router /api/engagement/{type}/{id}/like [post]
+func (h *EngagementHandler) Like(c *gin.Context) {
+	t, id, ok := lookupEngagementTarget(c)
+	if !ok {
+		return
+	}
+
+	exists, err := engagement.Exists(h.db, t, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to like item",
+		})
+		return
+	}
+	if !exists {
+		respondEngagementNotFound(c, "Item not found")
+		return
+	}
+
+	if _, err := h.tracker.Like(h.db, t, id, visitorOf(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to like item",
+		})
+		return
+	}
+	h.respondEngagement(c, t, id, "Liked")
+}
+
+// @Summary Unlike
+// @Description Withdraw the current visitor's like of a blog post, program or project
+// @Tags engagement
+// @Produce json
+// @Param type path string true "Content type (allblogposts, programs, projects)"
+// @Param id path string true "Content ID"
+// @Success 200 {object} models.APIResponse{data=models.Engagement}
+// @Failure 404 {object} models.APIResponse
+// @Router /api/engagement/{type}/{id}/like [delete]
+func (h *EngagementHandler) Unlike(c *gin.Context) {
+	t, id, ok := lookupEngagementTarget(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.tracker.Unlike(h.db, t, id, visitorOf(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to unlike item",
+		})
+		return
+	}
+	h.respondEngagement(c, t, id, "Unliked")
+}
+
+func (h *EngagementHandler) respondEngagement(c *gin.Context, t engagement.Target, id, message string) {
+	counts, err := h.tracker.Get(h.db, t, id, visitorOf(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondEngagementNotFound(c, "Item not found")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch views and likes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    counts,
+	})
+}
+
+func lookupEngagementTarget(c *gin.Context) (engagement.Target, string, bool) {
+	t, err := engagement.Lookup(c.Param("type"))
+	if err != nil {
+		respondEngagementNotFound(c, "Unknown content type")
+		return engagement.Target{}, "", false
+	}
+	id := c.Param("id")
+	if !t.ValidID(id) {
+		respondEngagementNotFound(c, "Item not found")
+		return engagement.Target{}, "", false
+	}
+	return t, id, true
+}
+
+func respondEngagementNotFound(c *gin.Context, message string) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: message,
+	})
+}
+
+// visitorOf identifies the requesting visitor for view and like de-duplication
+func visitorOf(c *gin.Context) string {
+	return engagement.Visitor(c.GetString("user_id"), c.ClientIP(), c.Request.UserAgent())
+}
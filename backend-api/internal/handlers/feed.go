@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/feeds"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// feedMaxAge is how long clients and proxies may reuse a feed without revalidating
+const feedMaxAge = 15 * time.Minute
+
+type FeedHandler struct {
+	db          *gorm.DB
+	siteURL     string
+	apiURL      string
+	fullContent bool
+	items       int
+}
+
+func NewFeedHandler(db *gorm.DB, cfg *config.Config) *FeedHandler {
+	return &FeedHandler{
+		db:          db,
+		siteURL:     strings.TrimSuffix(cfg.FrontendURL, "/"),
+		apiURL:      cfg.APIURL,
+		fullContent: cfg.FeedFullContent,
+		items:       cfg.FeedItems,
+	}
+}
+
+// @Summary Blog RSS feed
+// @Description Latest blog posts as RSS 2.0
+// @Tags feeds
+// @Produce xml
+// @Param category query string false "Only posts in this category slug, including subcategories"
+// @Success 200 {string} string "RSS document"
+// @Success 304 {string} string "Not modified"
+// @Failure 404 {object} models.APIResponse
+// @Router /api/feeds/blog.rss [get]
+func (h *FeedHandler) BlogRSS(c *gin.Context) {
+	h.serveBlogFeed(c, "application/rss+xml; charset=utf-8", feeds.Feed.RSS)
+}
+
+// @Summary Blog Atom feed
+// @Description Latest blog posts as Atom 1.0
+// @Tags feeds
+// @Produce xml
+// @Param category query string false "Only posts in this category slug, including subcategories"
+// @Success 200 {string} string "Atom document"
+// @Success 304 {string} string "Not modified"
+// @Failure 404 {object} models.APIResponse
+// @Router /api/feeds/blog.atom [get]
+func (h *FeedHandler) BlogAtom(c *gin.Context) {
+	h.serveBlogFeed(c, "application/atom+xml; charset=utf-8", feeds.Feed.Atom)
+}
+
+// @Summary Blog JSON feed
+// @Description Latest blog posts as JSON Feed 1.1
+// @Tags feeds
+// @Produce json
+// @Param category query string false "Only posts in this category slug, including subcategories"
+// @Success 200 {string} string "JSON Feed document"
+// @Success 304 {string} string "Not modified"
+// @Failure 404 {object} models.APIResponse
+// @Router /api/feeds/blog.json [get]
+func (h *FeedHandler) BlogJSON(c *gin.Context) {
+	h.serveBlogFeed(c, "application/feed+json; charset=utf-8", feeds.Feed.JSON)
+}
+
+func (h *FeedHandler) serveBlogFeed(c *gin.Context, contentType string, encode func(feeds.Feed) ([]byte, error)) {
+	feed := feeds.Feed{
+		Title:       "MSC - Chia sẻ",
+		Description: "Bài viết chia sẻ mới nhất từ MSC",
+		Link:        h.siteURL + "/chia-se",
+		FeedURL:     h.apiURL + c.Request.URL.RequestURI(),
+		Language:    "vi",
+	}
+
+	query := h.db.Model(&models.AllBlogPost{}).Where("publish_date <= ?", time.Now())
+	if slug := c.Query("category"); slug != "" {
+		var category models.Category
+		if err := h.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, models.APIResponse{
+					Success: false,
+					Message: "Category not found",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Failed to build feed",
+			})
+			return
+		}
+		query = taxonomy.Filter(query, taxonomy.AllBlogPosts, category.Slug, "")
+		feed.Title += " - " + category.Name
+	}
+
+	var posts []models.AllBlogPost
+	if err := query.Order("publish_date DESC").Limit(h.items).Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to build feed",
+		})
+		return
+	}
+
+	ids := make([]string, len(posts))
+	for i := range posts {
+		ids[i] = strconv.FormatUint(uint64(posts[i].ID), 10)
+	}
+	taxonomies := loadTaxonomy(h.db, taxonomy.AllBlogPosts, ids)
+
+	for i, post := range posts {
+		item := feeds.Item{
+			Title:     post.Title,
+			Link:      h.siteURL + "/chia-se/" + post.Slug,
+			Summary:   post.Excerpt,
+			Author:    post.Author,
+			Image:     post.Image,
+			Published: post.PublishDate,
+			Updated:   post.UpdatedAt,
+		}
+		if h.fullContent {
+			item.Content = post.DetailsBlog
+		}
+		if item.Updated.Before(item.Published) {
+			item.Updated = item.Published
+		}
+		for _, category := range taxonomies[ids[i]].Categories {
+			item.Categories = append(item.Categories, category.Name)
+		}
+		if len(item.Categories) == 0 && post.Category != "" {
+			item.Categories = []string{post.Category}
+		}
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Unix(0, 0)
+	}
+
+	body, err := encode(feed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to build feed",
+		})
+		return
+	}
+
+	serveCacheable(c, contentType, body, feed.Updated, feedMaxAge)
+}
@@ -1 +1,126 @@
 package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/auth"
+	"msc-backend-api/pkg/slug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// currentUserID returns the authenticated user's ID, or uuid.Nil when it is missing
+func currentUserID(c *gin.Context) uuid.UUID {
+	userID, _ := uuid.Parse(c.GetString("user_id"))
+	return userID
+}
+
+// currentUserRoles returns the authenticated user's role names
+func currentUserRoles(c *gin.Context) []string {
+	userRoles, _ := c.Get("user_roles")
+	userRolesList, _ := userRoles.([]string)
+	return userRolesList
+}
+
+// canAccessOwned reports whether the current user may access content owned by authorID.
+// Partners without an admin or editor role are limited to their own content.
+func canAccessOwned(c *gin.Context, authorID uuid.UUID) bool {
+	roles := currentUserRoles(c)
+	if auth.HasRole(roles, "partner") && !auth.HasAnyRole(roles, []string{"admin", "editor"}) {
+		return authorID == currentUserID(c)
+	}
+	return true
+}
+
+// serveCacheable writes a generated document with an ETag derived from its content and
+// the given Last-Modified time, answering conditional requests with 304 Not Modified
+func serveCacheable(c *gin.Context, contentType string, body []byte, lastModified time.Time, maxAge time.Duration) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
+	} else if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil && !lastModified.IsZero() {
+		if !lastModified.Truncate(time.Second).After(since) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// slugBase returns the slug new content asks for: the requested slug normalised, or one
+// derived from the title when none is given. It is empty when neither has letters or digits.
+func slugBase(requested, title string) string {
+	if base := slug.Make(requested); base != "" {
+		return base
+	}
+	return slug.Make(title)
+}
+
+// uniqueSlug makes base unique in table by appending a numeric suffix when needed. It
+// must run inside the transaction that stores the row.
+func uniqueSlug(tx *gorm.DB, table, base string, checks ...slug.Taken) (string, error) {
+	if err := slug.Lock(tx, table, base); err != nil {
+		return "", err
+	}
+	return slug.Unique(base, append([]slug.Taken{slug.InTable(tx, table, "")}, checks...)...)
+}
+
+// checkSlugChange resolves the slug of an update: the current slug when none is requested,
+// the normalised requested slug otherwise. A taken slug is refused with a free
+// alternative in the response.
+func checkSlugChange(c *gin.Context, db *gorm.DB, table, id, current, requested string, checks ...slug.Taken) (string, bool) {
+	next := slug.Make(requested)
+	if next == "" || next == current {
+		return current, true
+	}
+
+	checks = append([]slug.Taken{slug.InTable(db, table, id)}, checks...)
+	suggestion, err := slug.Unique(next, checks...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to check slug",
+		})
+		return "", false
+	}
+	if suggestion != next {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Slug already exists",
+			Data:    gin.H{"suggested_slug": suggestion},
+		})
+		return "", false
+	}
+	return next, true
+}
+
+func respondInvalidSlug(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, models.APIResponse{
+		Success: false,
+		Message: "Title or slug must contain letters or digits",
+	})
+}
@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"msc-backend-api/internal/i18n"
+	"msc-backend-api/internal/mentors"
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/redirects"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/textsearch"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -26,7 +33,10 @@ func NewMentorHandler(db *gorm.DB) *MentorHandler {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param status query string false "Filter by status"
-// @Param search query string false "Search in name and bio"
+// @Param category query string false "Filter by category slug or name, including subcategories"
+// @Param tag query string false "Filter by tag slug"
+// @Param search query string false "Full-text search (accent-insensitive), results ranked by relevance"
+// @Param sort query string false "rating for the best rated first, reviews for the most reviewed first"
 // @Success 200 {object} models.PaginatedResponse
 // @Failure 401 {object} models.APIResponse
 // @Router /mentors [get]
@@ -43,6 +53,11 @@ func (h *MentorHandler) GetMentors(c *gin.Context) {
 		limit = 10
 	}
 
+	order, ok := ratingOrder(c)
+	if !ok {
+		return
+	}
+
 	offset := (page - 1) * limit
 
 	query := h.db.Model(&models.Mentor{})
@@ -51,12 +66,17 @@ func (h *MentorHandler) GetMentors(c *gin.Context) {
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if order != "" {
+		query = query.Order(order)
+	}
 	if search != "" {
-		query = query.Where("name ILIKE ? OR bio ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Where(textsearch.Mentors.Match(search)).Order(textsearch.Mentors.Rank(search))
 	}
+	query = filterTaxonomy(c, query, taxonomy.Mentors)
 
 	var total int64
 	query.Count(&total)
+	facets := listFacets(h.db, query, taxonomy.Mentors)
 
 	var mentors []models.Mentor
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&mentors).Error; err != nil {
@@ -67,6 +87,31 @@ func (h *MentorHandler) GetMentors(c *gin.Context) {
 		return
 	}
 
+	if search != "" && len(mentors) > 0 {
+		ids := make([]uuid.UUID, len(mentors))
+		for i := range mentors {
+			ids[i] = mentors[i].ID
+		}
+		snippets := searchSnippets(h.db, textsearch.Mentors, search, ids)
+		for i := range mentors {
+			mentors[i].Snippet = snippets[mentors[i].ID.String()]
+		}
+	}
+
+	ids := make([]string, len(mentors))
+	for i := range mentors {
+		ids[i] = mentors[i].ID.String()
+	}
+	translations := loadTranslations(c, h.db, i18n.Mentors, ids)
+	for i := range mentors {
+		i18n.Apply(&mentors[i], translations[ids[i]])
+	}
+	taxonomies := loadTaxonomy(h.db, taxonomy.Mentors, ids)
+	for i := range mentors {
+		tax := taxonomies[ids[i]]
+		mentors[i].Categories, mentors[i].Tags = tax.Categories, tax.Tags
+	}
+
 	totalPages := (int(total) + limit - 1) / limit
 
 	c.JSON(http.StatusOK, models.PaginatedResponse{
@@ -75,11 +120,12 @@ func (h *MentorHandler) GetMentors(c *gin.Context) {
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		Facets:     facets,
 	})
 }
 
 // @Summary Get single mentor
-// @Description Get mentor details by ID
+// @Description Get mentor details by ID, including the structured profile and the courses and programs the mentor teaches
 // @Tags mentors
 // @Produce json
 // @Security BearerAuth
@@ -100,6 +146,163 @@ func (h *MentorHandler) GetMentor(c *gin.Context) {
 		return
 	}
 
+	i18n.Apply(&mentor, itemTranslations(c, h.db, i18n.Mentors, mentor.ID.String()))
+	mentor.Categories, mentor.Tags = itemTaxonomy(h.db, taxonomy.Mentors, mentor.ID.String())
+	profile, err := mentors.LoadProfile(h.db, mentor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentor profile",
+		})
+		return
+	}
+	mentor.Profile = profile
+	if mentor.Courses, mentor.Programs, err = mentors.LoadTaught(h.db, mentor.ID, false); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentor courses",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    mentor,
+	})
+}
+
+// @Summary Get public mentors
+// @Description List active mentors for the public site. Contact details are not included.
+// @Tags mentors
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param category query string false "Filter by category slug or name, including subcategories"
+// @Param tag query string false "Filter by tag slug"
+// @Param search query string false "Full-text search (accent-insensitive), results ranked by relevance"
+// @Param sort query string false "rating for the best rated first, reviews for the most reviewed first"
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Router /api/mentors [get]
+func (h *MentorHandler) GetPublicMentors(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	search := c.Query("search")
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	order, ok := ratingOrder(c)
+	if !ok {
+		return
+	}
+
+	query := h.db.Model(&models.Mentor{}).Where("status = ?", "active")
+	if order != "" {
+		query = query.Order(order)
+	}
+	if search != "" {
+		query = query.Where(textsearch.Mentors.Match(search)).Order(textsearch.Mentors.Rank(search))
+	}
+	query = filterTaxonomy(c, query, taxonomy.Mentors)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to count mentors",
+		})
+		return
+	}
+	facets := listFacets(h.db, query, taxonomy.Mentors)
+
+	var mentorList []models.Mentor
+	if err := query.Offset((page - 1) * limit).Limit(limit).Order("name").Find(&mentorList).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentors",
+		})
+		return
+	}
+
+	ids := make([]string, len(mentorList))
+	for i := range mentorList {
+		ids[i] = mentorList[i].ID.String()
+	}
+	translations := loadTranslations(c, h.db, i18n.Mentors, ids)
+	taxonomies := loadTaxonomy(h.db, taxonomy.Mentors, ids)
+	for i := range mentorList {
+		mentorList[i].HidePrivate()
+		i18n.Apply(&mentorList[i], translations[ids[i]])
+		tax := taxonomies[ids[i]]
+		mentorList[i].Categories, mentorList[i].Tags = tax.Categories, tax.Tags
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.PaginatedResponse{
+			Data:       mentorList,
+			Total:      total,
+			Page:       page,
+			Limit:      limit,
+			TotalPages: (int(total) + limit - 1) / limit,
+			Facets:     facets,
+		},
+	})
+}
+
+// @Summary Get public mentor by slug
+// @Description Get an active mentor with the structured profile and the published courses and programs they teach, for the public site. Contact details are not included.
+// @Tags mentors
+// @Produce json
+// @Param slug path string true "Mentor slug"
+// @Success 200 {object} models.APIResponse{data=models.Mentor}
+// @Success 301 {object} models.APIResponse{data=models.Redirect} "Old slug; Location has the current one"
+// @Failure 404 {object} models.APIResponse
+// @Router /api/mentors/slug/{slug} [get]
+func (h *MentorHandler) GetMentorBySlug(c *gin.Context) {
+	var mentor models.Mentor
+	if err := h.db.Where("slug = ? AND status = ?", c.Param("slug"), "active").First(&mentor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if respondMovedSlug(c, h.db, redirects.Mentors) {
+				return
+			}
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Message: "Mentor not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentor",
+		})
+		return
+	}
+
+	profile, err := mentors.LoadProfile(h.db, mentor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentor profile",
+		})
+		return
+	}
+	mentor.Profile = profile
+	if mentor.Courses, mentor.Programs, err = mentors.LoadTaught(h.db, mentor.ID, true); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentor courses",
+		})
+		return
+	}
+	mentor.HidePrivate()
+	i18n.Apply(&mentor, itemTranslations(c, h.db, i18n.Mentors, mentor.ID.String()))
+	mentor.Categories, mentor.Tags = itemTaxonomy(h.db, taxonomy.Mentors, mentor.ID.String())
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    mentor,
@@ -132,6 +335,13 @@ func (h *MentorHandler) CreateMentor(c *gin.Context) {
 		req.Status = "active"
 	}
 
+	// The slug is optional; a taken one gets a numeric suffix
+	base := slugBase(req.Slug, req.Name)
+	if base == "" {
+		respondInvalidSlug(c)
+		return
+	}
+
 	mentor := models.Mentor{
 		Name:        req.Name,
 		Title:       req.Title,
@@ -142,9 +352,16 @@ func (h *MentorHandler) CreateMentor(c *gin.Context) {
 		LinkedinURL: req.LinkedinURL,
 		Specialties: req.Specialties,
 		Status:      req.Status,
+		UserID:      req.UserID,
 	}
 
-	if err := h.db.Create(&mentor).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if mentor.Slug, err = uniqueSlug(tx, "mentors", base); err != nil {
+			return err
+		}
+		return tx.Create(&mentor).Error
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to create mentor",
@@ -193,7 +410,15 @@ func (h *MentorHandler) UpdateMentor(c *gin.Context) {
 		return
 	}
 
-	// Update mentor
+	// Keep the slug unless a new one is given
+	mentorSlug, ok := checkSlugChange(c, h.db, "mentors", mentor.ID.String(), mentor.Slug, req.Slug)
+	if !ok {
+		return
+	}
+
+	// Update mentor; the old slug keeps redirecting to the mentor
+	oldSlug := mentor.Slug
+	mentor.Slug = mentorSlug
 	mentor.Name = req.Name
 	mentor.Title = req.Title
 	mentor.Bio = req.Bio
@@ -202,11 +427,17 @@ func (h *MentorHandler) UpdateMentor(c *gin.Context) {
 	mentor.Phone = req.Phone
 	mentor.LinkedinURL = req.LinkedinURL
 	mentor.Specialties = req.Specialties
+	mentor.UserID = req.UserID
 	if req.Status != "" {
 		mentor.Status = req.Status
 	}
 
-	if err := h.db.Save(&mentor).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&mentor).Error; err != nil {
+			return err
+		}
+		return redirects.Record(tx, models.EntityTypeMentor, mentor.ID.String(), oldSlug, mentor.Slug)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to update mentor",
@@ -222,7 +453,7 @@ func (h *MentorHandler) UpdateMentor(c *gin.Context) {
 }
 
 // @Summary Delete mentor
-// @Description Delete mentor by ID
+// @Description Move mentor to trash
 // @Tags mentors
 // @Produce json
 // @Security BearerAuth
@@ -244,6 +475,108 @@ func (h *MentorHandler) DeleteMentor(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Mentor deleted successfully",
+		Message: "Mentor moved to trash",
+	})
+}
+
+// @Summary Get mentor profile
+// @Description Get the education, work history, publications, awards and research areas of a mentor
+// @Tags mentors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Success 200 {object} models.APIResponse{data=models.MentorProfile}
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/profile [get]
+func (h *MentorHandler) GetMentorProfile(c *gin.Context) {
+	var mentor models.Mentor
+	if err := h.db.Where("id = ?", c.Param("id")).First(&mentor).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Mentor not found",
+		})
+		return
+	}
+
+	profile, err := mentors.LoadProfile(h.db, mentor.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentor profile",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    profile,
+	})
+}
+
+// @Summary Set mentor profile
+// @Description Replace sections of a mentor's structured profile. Sections left out are kept; an empty list clears a section. Entries are stored in the order given.
+// @Tags mentors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Param profile body models.SetMentorProfileRequest true "Profile sections"
+// @Success 200 {object} models.APIResponse{data=models.MentorProfile}
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/profile [put]
+func (h *MentorHandler) SetMentorProfile(c *gin.Context) {
+	mentorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Mentor not found",
+		})
+		return
+	}
+
+	var req models.SetMentorProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	if err := mentors.SetProfile(h.db, mentorID, req); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Message: "Mentor not found",
+			})
+		case errors.Is(err, mentors.ErrInvalidProfile):
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Failed to update mentor profile",
+			})
+		}
+		return
+	}
+
+	profile, err := mentors.LoadProfile(h.db, mentorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch mentor profile",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Mentor profile updated successfully",
+		Data:    profile,
 	})
 }
@@ -0,0 +1,485 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/redirects"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type MSCerHandler struct {
+	db *gorm.DB
+}
+
+func NewMSCerHandler(db *gorm.DB) *MSCerHandler {
+	return &MSCerHandler{db: db}
+}
+
+// errMSCerLink is returned when a profile links to a user or program that cannot be used
+var errMSCerLink = errors.New("invalid link")
+
+// @Summary Get MSCers
+// @Description Get list of alumni profiles with pagination and filtering, including drafts
+// @Tags mscers
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (draft or published)"
+// @Param course query string false "Filter by course name or program slug"
+// @Param graduation_year query int false "Filter by graduation year"
+// @Param search query string false "Search by name, company or position"
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /mscers [get]
+func (h *MSCerHandler) GetMSCers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	query := h.db.Model(&models.MSCer{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if search := strings.TrimSpace(c.Query("search")); search != "" {
+		like := "%" + search + "%"
+		query = query.Where("name ILIKE ? OR company ILIKE ? OR position ILIKE ?", like, like, like)
+	}
+	query, ok := filterMSCers(c, query)
+	if !ok {
+		return
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var list []models.MSCer
+	if err := query.Preload("Program").Offset((page - 1) * limit).Limit(limit).Order("created_at DESC").Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch MSCers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       list,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (int(total) + limit - 1) / limit,
+	})
+}
+
+// @Summary Get single MSCer
+// @Description Get an alumni profile by ID
+// @Tags mscers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "MSCer ID"
+// @Success 200 {object} models.APIResponse{data=models.MSCer}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mscers/{id} [get]
+func (h *MSCerHandler) GetMSCer(c *gin.Context) {
+	var mscer models.MSCer
+	if err := h.db.Preload("Program").Where("id = ?", c.Param("id")).First(&mscer).Error; err != nil {
+		respondMSCerNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    mscer,
+	})
+}
+
+// @Summary Get public MSCers
+// @Description List published alumni profiles whose alumni consented, for the public site, by graduation year then name
+// @Tags mscers
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Param course query string false "Filter by course name or program slug"
+// @Param graduation_year query int false "Filter by graduation year"
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Failure 400 {object} models.APIResponse
+// @Router /api/mscers [get]
+func (h *MSCerHandler) GetPublicMSCers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query, ok := filterMSCers(c, publicMSCers(h.db))
+	if !ok {
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to count MSCers",
+		})
+		return
+	}
+
+	var list []models.MSCer
+	if err := query.Preload("Program").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Order("graduation_year DESC, name").
+		Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch MSCers",
+		})
+		return
+	}
+	for i := range list {
+		list[i].HidePrivate()
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.PaginatedResponse{
+			Data:       list,
+			Total:      total,
+			Page:       page,
+			Limit:      limit,
+			TotalPages: (int(total) + limit - 1) / limit,
+		},
+	})
+}
+
+// @Summary Get public MSCer by slug
+// @Description Get a published alumni profile whose alumnus consented, for the public site
+// @Tags mscers
+// @Produce json
+// @Param slug path string true "MSCer slug"
+// @Success 200 {object} models.APIResponse{data=models.MSCer}
+// @Success 301 {object} models.APIResponse{data=models.Redirect} "Old slug; Location has the current one"
+// @Failure 404 {object} models.APIResponse
+// @Router /api/mscers/slug/{slug} [get]
+func (h *MSCerHandler) GetMSCerBySlug(c *gin.Context) {
+	var mscer models.MSCer
+	if err := publicMSCers(h.db).Preload("Program").Where("slug = ?", c.Param("slug")).First(&mscer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if respondMovedSlug(c, h.db, redirects.MSCers) {
+				return
+			}
+			respondMSCerNotFound(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch MSCer",
+		})
+		return
+	}
+	mscer.HidePrivate()
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    mscer,
+	})
+}
+
+// @Summary Create MSCer
+// @Description Create an alumni profile. A profile can only be published with the alumnus' consent.
+// @Tags mscers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param mscer body models.CreateMSCerRequest true "MSCer data"
+// @Success 201 {object} models.APIResponse{data=models.MSCer}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /mscers [post]
+func (h *MSCerHandler) CreateMSCer(c *gin.Context) {
+	var req models.CreateMSCerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	// Set default status
+	if req.Status == "" {
+		req.Status = models.MSCerStatusDraft
+	}
+	if !checkMSCerConsent(c, req) {
+		return
+	}
+
+	// The slug is optional; a taken one gets a numeric suffix
+	base := slugBase(req.Slug, req.Name)
+	if base == "" {
+		respondInvalidSlug(c)
+		return
+	}
+
+	var mscer models.MSCer
+	applyMSCerRequest(&mscer, req)
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkMSCerLinks(tx, &mscer); err != nil {
+			return err
+		}
+		var err error
+		if mscer.Slug, err = uniqueSlug(tx, "mscers", base); err != nil {
+			return err
+		}
+		return tx.Create(&mscer).Error
+	})
+	if err != nil {
+		respondMSCerSaveError(c, err, "Failed to create MSCer")
+		return
+	}
+	h.db.Preload("Program").Where("id = ?", mscer.ID).First(&mscer)
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "MSCer created successfully",
+		Data:    mscer,
+	})
+}
+
+// @Summary Update MSCer
+// @Description Update an alumni profile by ID. A profile can only be published with the alumnus' consent.
+// @Tags mscers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "MSCer ID"
+// @Param mscer body models.CreateMSCerRequest true "MSCer data"
+// @Success 200 {object} models.APIResponse{data=models.MSCer}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mscers/{id} [put]
+func (h *MSCerHandler) UpdateMSCer(c *gin.Context) {
+	var mscer models.MSCer
+	if err := h.db.Where("id = ?", c.Param("id")).First(&mscer).Error; err != nil {
+		respondMSCerNotFound(c)
+		return
+	}
+
+	var req models.CreateMSCerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	// Keep the status unless a new one is given
+	if req.Status == "" {
+		req.Status = mscer.Status
+	}
+	if !checkMSCerConsent(c, req) {
+		return
+	}
+
+	// Keep the slug unless a new one is given
+	mscerSlug, ok := checkSlugChange(c, h.db, "mscers", mscer.ID.String(), mscer.Slug, req.Slug)
+	if !ok {
+		return
+	}
+
+	// Update the profile; the old slug keeps redirecting to it
+	oldSlug := mscer.Slug
+	mscer.Slug = mscerSlug
+	applyMSCerRequest(&mscer, req)
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkMSCerLinks(tx, &mscer); err != nil {
+			return err
+		}
+		if err := tx.Save(&mscer).Error; err != nil {
+			return err
+		}
+		return redirects.Record(tx, models.EntityTypeMSCer, mscer.ID.String(), oldSlug, mscer.Slug)
+	})
+	if err != nil {
+		respondMSCerSaveError(c, err, "Failed to update MSCer")
+		return
+	}
+	h.db.Preload("Program").Where("id = ?", mscer.ID).First(&mscer)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "MSCer updated successfully",
+		Data:    mscer,
+	})
+}
+
+// @Summary Delete MSCer
+// @Description Move an alumni profile to trash
+// @Tags mscers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "MSCer ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mscers/{id} [delete]
+func (h *MSCerHandler) DeleteMSCer(c *gin.Context) {
+	result := h.db.Delete(&models.MSCer{}, "id = ?", c.Param("id"))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to delete MSCer",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondMSCerNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "MSCer moved to trash",
+	})
+}
+
+// publicMSCers scopes a query to the profiles shown on the public site
+func publicMSCers(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.MSCer{}).Where("status = ? AND consent", models.MSCerStatusPublished)
+}
+
+// filterMSCers applies the ?course= and ?graduation_year= filters of MSCer lists. A
+// course matches the course name, ignoring case, or the slug of the linked program.
+func filterMSCers(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	if course := strings.TrimSpace(c.Query("course")); course != "" {
+		query = query.Where("LOWER(mscers.course) = LOWER(?) OR mscers.program_id IN (SELECT id FROM programs WHERE slug = ?)", course, course)
+	}
+	if year := c.Query("graduation_year"); year != "" {
+		graduationYear, err := strconv.Atoi(year)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid graduation year",
+			})
+			return nil, false
+		}
+		query = query.Where("mscers.graduation_year = ?", graduationYear)
+	}
+	return query, true
+}
+
+// applyMSCerRequest copies the editable fields of a request onto a profile and keeps
+// the time consent was given
+func applyMSCerRequest(mscer *models.MSCer, req models.CreateMSCerRequest) {
+	mscer.Name = req.Name
+	mscer.Company = req.Company
+	mscer.Position = req.Position
+	mscer.AvatarURL = req.AvatarURL
+	mscer.Achievement = req.Achievement
+	mscer.Testimonial = req.Testimonial
+	mscer.GraduationYear = req.GraduationYear
+	mscer.Promotion = req.Promotion
+	mscer.SocialImpact = req.SocialImpact
+	mscer.Course = req.Course
+	mscer.Skills = models.StringArray(req.Skills)
+	mscer.Achievements = models.StringArray(req.Achievements)
+	mscer.Mentoring = req.Mentoring
+	mscer.Background = req.Background
+	mscer.Status = req.Status
+	mscer.UserID = req.UserID
+	mscer.ProgramID = req.ProgramID
+
+	switch {
+	case !req.Consent:
+		mscer.ConsentAt = nil
+	case !mscer.Consent || mscer.ConsentAt == nil:
+		now := time.Now()
+		mscer.ConsentAt = &now
+	}
+	mscer.Consent = req.Consent
+}
+
+// checkMSCerConsent refuses to publish a profile without the alumnus' consent
+func checkMSCerConsent(c *gin.Context, req models.CreateMSCerRequest) bool {
+	if req.Status == models.MSCerStatusPublished && !req.Consent {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "A profile can only be published with the alumnus' consent",
+		})
+		return false
+	}
+	return true
+}
+
+// checkMSCerLinks verifies that the linked user and program exist and that the user has
+// no other profile
+func checkMSCerLinks(tx *gorm.DB, mscer *models.MSCer) error {
+	var count int64
+	if mscer.UserID != nil {
+		if err := tx.Model(&models.User{}).Where("id = ?", *mscer.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: user not found", errMSCerLink)
+		}
+		if err := tx.Unscoped().Model(&models.MSCer{}).Where("user_id = ? AND id != ?", *mscer.UserID, mscer.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: user already has an MSCer profile", errMSCerLink)
+		}
+	}
+	if mscer.ProgramID != nil {
+		if err := tx.Model(&models.Program{}).Where("id = ?", *mscer.ProgramID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: program not found", errMSCerLink)
+		}
+	}
+	return nil
+}
+
+func respondMSCerSaveError(c *gin.Context, err error, message string) {
+	if errors.Is(err, errMSCerLink) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.APIResponse{
+		Success: false,
+		Message: message,
+	})
+}
+
+func respondMSCerNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: "MSCer not found",
+	})
+}
@@ -1,17 +1,29 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	"msc-backend-api/internal/blog"
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/revisions"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/textsearch"
 	"msc-backend-api/pkg/auth"
+	"msc-backend-api/pkg/slug"
+	"msc-backend-api/pkg/workflow"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// errRevisionSlugTaken marks a restore whose old slug is used by another post or blog row
+var errRevisionSlugTaken = errors.New("revision slug is taken")
+
 type PostHandler struct {
 	db *gorm.DB
 }
@@ -28,8 +40,10 @@ func NewPostHandler(db *gorm.DB) *PostHandler {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param status query string false "Filter by status"
-// @Param category query string false "Filter by category"
-// @Param search query string false "Search in title and content"
+// @Param category query string false "Filter by category slug or name, including subcategories"
+// @Param tag query string false "Filter by tag slug"
+// @Param search query string false "Full-text search (accent-insensitive), results ranked by relevance"
+// @Param render query string false "Include rendered content (html or text)"
 // @Success 200 {object} models.PaginatedResponse
 // @Failure 401 {object} models.APIResponse
 // @Router /posts [get]
@@ -37,8 +51,11 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	status := c.Query("status")
-	category := c.Query("category")
 	search := c.Query("search")
+	render, ok := renderMode(c)
+	if !ok {
+		return
+	}
 
 	if page < 1 {
 		page = 1
@@ -55,11 +72,9 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
-	if category != "" {
-		query = query.Where("category = ?", category)
-	}
+	query = filterTaxonomy(c, query, taxonomy.Posts)
 	if search != "" {
-		query = query.Where("title ILIKE ? OR excerpt ILIKE ?", "%"+search+"%", "%"+search+"%")
+		query = query.Where(textsearch.Posts.Match(search)).Order(textsearch.Posts.Rank(search))
 	}
 
 	// Check user role for filtering
@@ -75,6 +90,7 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 
 	var total int64
 	query.Count(&total)
+	facets := listFacets(h.db, query, taxonomy.Posts)
 
 	var posts []models.Post
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&posts).Error; err != nil {
@@ -85,6 +101,31 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 		return
 	}
 
+	if search != "" && len(posts) > 0 {
+		ids := make([]uuid.UUID, len(posts))
+		for i := range posts {
+			ids[i] = posts[i].ID
+		}
+		snippets := searchSnippets(h.db, textsearch.Posts, search, ids)
+		for i := range posts {
+			posts[i].Snippet = snippets[posts[i].ID.String()]
+		}
+	}
+
+	ids := make([]string, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID.String()
+	}
+	taxonomies := loadTaxonomy(h.db, taxonomy.Posts, ids)
+	for i := range posts {
+		tax := taxonomies[ids[i]]
+		posts[i].Categories, posts[i].Tags = tax.Categories, tax.Tags
+	}
+
+	for i := range posts {
+		renderPost(render, &posts[i])
+	}
+
 	totalPages := (int(total) + limit - 1) / limit
 
 	c.JSON(http.StatusOK, models.PaginatedResponse{
@@ -93,6 +134,7 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		Facets:     facets,
 	})
 }
 
@@ -102,12 +144,17 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Post ID"
+// @Param render query string false "Include rendered content (html or text)"
 // @Success 200 {object} models.APIResponse{data=models.Post}
 // @Failure 401 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
 // @Router /posts/{id} [get]
 func (h *PostHandler) GetPost(c *gin.Context) {
 	id := c.Param("id")
+	render, ok := renderMode(c)
+	if !ok {
+		return
+	}
 
 	var post models.Post
 	if err := h.db.Preload("Author").Where("id = ?", id).First(&post).Error; err != nil {
@@ -118,6 +165,10 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		return
 	}
 
+	renderPost(render, &post)
+
+	post.Categories, post.Tags = itemTaxonomy(h.db, taxonomy.Posts, post.ID.String())
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    post,
@@ -144,44 +195,56 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		})
 		return
 	}
+	if !checkContent(c, req.Content) {
+		return
+	}
 
 	userID, _ := c.Get("user_id")
 	userIDStr := userID.(string)
 	authorID, _ := uuid.Parse(userIDStr)
 
-	// Check if slug is unique
-	var existingPost models.Post
-	if err := h.db.Where("slug = ?", req.Slug).First(&existingPost).Error; err == nil {
-		c.JSON(http.StatusBadRequest, models.APIResponse{
-			Success: false,
-			Message: "Slug already exists",
-		})
+	// The slug is optional; a taken one gets a numeric suffix
+	base := slugBase(req.Slug, req.Title)
+	if base == "" {
+		respondInvalidSlug(c)
 		return
 	}
 
-	// Set default status for partners
-	if req.Status == "" {
-		userRoles, _ := c.Get("user_roles")
-		if userRolesList, ok := userRoles.([]string); ok {
-			if auth.HasRole(userRolesList, "partner") {
-				req.Status = "pending_review"
-			} else {
-				req.Status = "draft"
-			}
-		}
+	// New content starts as a draft (or in review for partners); publishing needs a workflow action
+	status, ok := checkInitialStatus(c, req.Status)
+	if !ok {
+		return
+	}
+	if !checkScheduleWindow(c, status, req.PublishAt, req.UnpublishAt) {
+		return
 	}
 
 	post := models.Post{
 		Title:        req.Title,
-		Slug:         req.Slug,
 		Content:      req.Content,
 		Excerpt:      req.Excerpt,
 		ThumbnailURL: req.ThumbnailURL,
-		Status:       req.Status,
+		Status:       status,
+		PublishAt:    req.PublishAt,
+		UnpublishAt:  req.UnpublishAt,
 		AuthorID:     authorID,
 	}
 
-	if err := h.db.Create(&post).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Published posts share the public blog's slug space
+		var err error
+		post.Slug, err = uniqueSlug(tx, "posts", base, func(candidate string) (bool, error) {
+			return blog.SlugTaken(tx, candidate, uuid.Nil)
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&post).Error; err != nil {
+			return err
+		}
+		_, err = revisions.Save(tx, models.EntityTypePost, post.ID, authorID, post.Snapshot(), nil)
+		return err
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to create post",
@@ -200,7 +263,7 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 }
 
 // @Summary Update post
-// @Description Update post by ID
+// @Description Update post by ID. A partner's edit to a scheduled or published post sends it back to review and off the blog until approved.
 // @Tags posts
 // @Accept json
 // @Produce json
@@ -246,30 +309,45 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		})
 		return
 	}
+	if !checkContent(c, req.Content) {
+		return
+	}
 
-	// Check if new slug is unique (if changed)
-	if req.Slug != post.Slug {
-		var existingPost models.Post
-		if err := h.db.Where("slug = ? AND id != ?", req.Slug, id).First(&existingPost).Error; err == nil {
-			c.JSON(http.StatusBadRequest, models.APIResponse{
-				Success: false,
-				Message: "Slug already exists",
-			})
-			return
-		}
+	if !checkUnchangedStatus(c, post.Status, req.Status) || !checkScheduleWindow(c, post.Status, req.PublishAt, req.UnpublishAt) {
+		return
+	}
+
+	// Keep the slug unless a new one is given
+	postSlug, ok := checkSlugChange(c, h.db, "posts", post.ID.String(), post.Slug, req.Slug, func(candidate string) (bool, error) {
+		return blog.SlugTaken(h.db, candidate, post.ID)
+	})
+	if !ok {
+		return
 	}
 
 	// Update post
 	post.Title = req.Title
-	post.Slug = req.Slug
+	post.Slug = postSlug
 	post.Content = req.Content
 	post.Excerpt = req.Excerpt
 	post.ThumbnailURL = req.ThumbnailURL
-	if req.Status != "" {
-		post.Status = req.Status
-	}
+	post.PublishAt = req.PublishAt
+	post.UnpublishAt = req.UnpublishAt
 
-	if err := h.db.Save(&post).Error; err != nil {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := resubmitEdited(c, tx, models.EntityTypePost, post.ID, &post.Status); err != nil {
+			return err
+		}
+		if err := tx.Save(&post).Error; err != nil {
+			return err
+		}
+		if _, err := revisions.Save(tx, models.EntityTypePost, post.ID, currentUserID(c), post.Snapshot(), nil); err != nil {
+			return err
+		}
+		// A reviewer's edits to a published post go live on the public blog right away; a
+		// partner's take it off the blog until they are approved
+		return blog.SyncPost(tx, post.ID)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to update post",
@@ -288,7 +366,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 }
 
 // @Summary Delete post
-// @Description Delete post by ID
+// @Description Move post to trash
 // @Tags posts
 // @Produce json
 // @Security BearerAuth
@@ -300,7 +378,22 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 func (h *PostHandler) DeletePost(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.db.Delete(&models.Post{}, "id = ?", id).Error; err != nil {
+	postID, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Post not found",
+		})
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Post{}, "id = ?", postID).Error; err != nil {
+			return err
+		}
+		// Take it off the public blog as well
+		return blog.SyncPost(tx, postID)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to delete post",
@@ -310,86 +403,325 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Post deleted successfully",
+		Message: "Post moved to trash",
 	})
 }
 
 // @Summary Approve post
-// @Description Approve a pending post
+// @Description Publish a post that is pending review
 // @Tags posts
+// @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Post ID"
+// @Param review body models.ReviewRequest false "Optional review note"
 // @Success 200 {object} models.APIResponse{data=models.Post}
+// @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
 // @Router /posts/{id}/approve [patch]
 func (h *PostHandler) ApprovePost(c *gin.Context) {
-	id := c.Param("id")
+	h.transitionPost(c, workflow.ActionApprove, "Post approved successfully")
+}
 
-	var post models.Post
-	if err := h.db.Where("id = ?", id).First(&post).Error; err != nil {
-		c.JSON(http.StatusNotFound, models.APIResponse{
+// @Summary Reject post
+// @Description Send a pending post back to draft; a reason is required
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Param review body models.ReviewRequest true "Rejection reason"
+// @Success 200 {object} models.APIResponse{data=models.Post}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /posts/{id}/reject [patch]
+func (h *PostHandler) RejectPost(c *gin.Context) {
+	h.transitionPost(c, workflow.ActionReject, "Post rejected successfully")
+}
+
+// @Summary Archive post
+// @Description Archive a post from any active status
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Param review body models.ReviewRequest false "Optional note"
+// @Success 200 {object} models.APIResponse{data=models.Post}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /posts/{id}/archive [patch]
+func (h *PostHandler) ArchivePost(c *gin.Context) {
+	h.transitionPost(c, workflow.ActionArchive, "Post archived successfully")
+}
+
+// @Summary Run post workflow action
+// @Description Apply a workflow action (submit, approve, reject, schedule, unschedule, publish, unpublish, archive, restore) to a post
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Param transition body models.TransitionRequest true "Workflow action"
+// @Success 200 {object} models.APIResponse{data=models.Post}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /posts/{id}/transitions [post]
+func (h *PostHandler) TransitionPost(c *gin.Context) {
+	var req models.TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
-			Message: "Post not found",
+			Message: "Invalid request data",
 		})
 		return
 	}
 
-	post.Status = "published"
-	if err := h.db.Save(&post).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Success: false,
-			Message: "Failed to approve post",
-		})
+	post, ok := h.findAccessiblePost(c)
+	if !ok || !checkSchedulable(c, req.Action, post.PublishAt) {
 		return
 	}
 
-	h.db.Preload("Author").First(&post, post.ID)
+	if _, ok := changeStatus(c, h.db, post, models.EntityTypePost, post.ID, post.Status, req.Action, req.Reason); !ok {
+		return
+	}
+
+	h.db.Preload("Author").First(post, post.ID)
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Post approved successfully",
+		Message: "Post status updated successfully",
 		Data:    post,
 	})
 }
 
-// @Summary Reject post
-// @Description Reject a pending post
+// @Summary Get post status history
+// @Description List the workflow transitions of a post and the actions available to the caller
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /posts/{id}/history [get]
+func (h *PostHandler) GetPostHistory(c *gin.Context) {
+	post, ok := h.findAccessiblePost(c)
+	if !ok {
+		return
+	}
+
+	respondStatusHistory(c, h.db, models.EntityTypePost, post.ID, post.Status)
+}
+
+// transitionPost backs the fixed-action endpoints; the review note in the body is optional
+func (h *PostHandler) transitionPost(c *gin.Context, action, message string) {
+	var req models.ReviewRequest
+	_ = c.ShouldBindJSON(&req) // body is optional
+
+	post, ok := h.findAccessiblePost(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := changeStatus(c, h.db, post, models.EntityTypePost, post.ID, post.Status, action, req.Reason); !ok {
+		return
+	}
+
+	h.db.Preload("Author").First(post, post.ID)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    post,
+	})
+}
+
+// @Summary Get post revisions
+// @Description List every stored revision of a post, newest first
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Success 200 {object} models.APIResponse{data=[]models.Revision}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /posts/{id}/revisions [get]
+func (h *PostHandler) GetPostRevisions(c *gin.Context) {
+	post, ok := h.findAccessiblePost(c)
+	if !ok {
+		return
+	}
+
+	respondRevisions(c, h.db, models.EntityTypePost, post.ID)
+}
+
+// @Summary Get post revision
+// @Description Get a single revision of a post with its full snapshot
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Param version path int true "Revision version"
+// @Success 200 {object} models.APIResponse{data=models.Revision}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /posts/{id}/revisions/{version} [get]
+func (h *PostHandler) GetPostRevision(c *gin.Context) {
+	post, ok := h.findAccessiblePost(c)
+	if !ok {
+		return
+	}
+
+	respondRevision(c, h.db, models.EntityTypePost, post.ID)
+}
+
+// @Summary Diff post revisions
+// @Description Compare two revisions of a post field by field, including the Tiptap content tree
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Post ID"
+// @Param from query int false "Source version (defaults to the one before to)"
+// @Param to query int false "Target version (defaults to the latest)"
+// @Success 200 {object} models.APIResponse{data=RevisionDiff}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /posts/{id}/revisions/diff [get]
+func (h *PostHandler) DiffPostRevisions(c *gin.Context) {
+	post, ok := h.findAccessiblePost(c)
+	if !ok {
+		return
+	}
+
+	respondRevisionDiff(c, h.db, models.EntityTypePost, post.ID)
+}
+
+// @Summary Restore post revision
+// @Description Restore the content of an old revision; the result is stored as a new revision. Restored by a partner, a scheduled or published post goes back to review.
 // @Tags posts
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Post ID"
+// @Param version path int true "Revision version"
 // @Success 200 {object} models.APIResponse{data=models.Post}
+// @Failure 400 {object} models.APIResponse
 // @Failure 401 {object} models.APIResponse
 // @Failure 404 {object} models.APIResponse
-// @Router /posts/{id}/reject [patch]
-func (h *PostHandler) RejectPost(c *gin.Context) {
-	id := c.Param("id")
+// @Router /posts/{id}/revisions/{version}/restore [post]
+func (h *PostHandler) RestorePostRevision(c *gin.Context) {
+	post, ok := h.findAccessiblePost(c)
+	if !ok {
+		return
+	}
 
-	var post models.Post
-	if err := h.db.Where("id = ?", id).First(&post).Error; err != nil {
-		c.JSON(http.StatusNotFound, models.APIResponse{
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
-			Message: "Post not found",
+			Message: "Invalid revision version",
 		})
 		return
 	}
 
-	post.Status = "draft"
-	if err := h.db.Save(&post).Error; err != nil {
+	revision, err := findRevision(h.db, models.EntityTypePost, post.ID, version)
+	if err != nil {
+		respondRevisionLookupError(c, err)
+		return
+	}
+
+	var snapshot models.PostSnapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
-			Message: "Failed to reject post",
+			Message: "Failed to read revision",
 		})
 		return
 	}
 
-	h.db.Preload("Author").First(&post, post.ID)
+	slugChanged := snapshot.Slug != post.Slug
+	post.ApplySnapshot(snapshot)
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		// The old slug may have been taken since by another post or a legacy blog row
+		if slugChanged {
+			if err := slug.Lock(tx, "posts", post.Slug); err != nil {
+				return err
+			}
+			taken, err := slug.InTable(tx, "posts", post.ID.String())(post.Slug)
+			if err == nil && !taken {
+				taken, err = blog.SlugTaken(tx, post.Slug, post.ID)
+			}
+			if err != nil {
+				return err
+			}
+			if taken {
+				return errRevisionSlugTaken
+			}
+		}
+		if err := resubmitEdited(c, tx, models.EntityTypePost, post.ID, &post.Status); err != nil {
+			return err
+		}
+		if err := tx.Save(post).Error; err != nil {
+			return err
+		}
+		if _, err := revisions.Save(tx, models.EntityTypePost, post.ID, currentUserID(c), post.Snapshot(), &revision.Version); err != nil {
+			return err
+		}
+		return blog.SyncPost(tx, post.ID)
+	})
+	if errors.Is(err, errRevisionSlugTaken) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Slug already exists",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to restore revision",
+		})
+		return
+	}
+
+	h.db.Preload("Author").First(post, post.ID)
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Post rejected successfully",
+		Message: fmt.Sprintf("Post restored from revision %d", revision.Version),
 		Data:    post,
 	})
 }
+
+// findAccessiblePost loads the post from the :id path param and checks that a partner
+// only reaches their own posts. It writes the error response itself when it fails.
+func (h *PostHandler) findAccessiblePost(c *gin.Context) (*models.Post, bool) {
+	var post models.Post
+	if err := h.db.Where("id = ?", c.Param("id")).First(&post).Error; err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Post not found",
+		})
+		return nil, false
+	}
+
+	if !canAccessOwned(c, post.AuthorID) {
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Message: "You can only access your own posts",
+		})
+		return nil, false
+	}
+
+	return &post, true
+}
@@ -9,7 +9,11 @@ import (
 	"strconv"
 	"time"
 
+	"msc-backend-api/internal/i18n"
+	"msc-backend-api/internal/mentors"
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/textsearch"
 	"msc-backend-api/pkg/database"
 
 	"github.com/gin-gonic/gin"
@@ -25,16 +29,26 @@ func NewProgramHandler(db *gorm.DB) *ProgramHandler {
 	return &ProgramHandler{db: db}
 }
 
-// GET /api/programs
+// GET /api/programs?search=&category=&tag=
 func (h *ProgramHandler) GetPrograms(c *gin.Context) {
 	limit := parseIntWithDefaultClamp(c.Query("limit"), 20, 1, 100)
 	offset := parseIntWithDefaultClamp(c.Query("offset"), 0, 0, 1_000_000)
+	search := c.Query("search")
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 3*time.Second)
 	defer cancel()
 
 	var programs []models.Program
 	session := database.GetFreshSession(h.db).WithContext(ctx)
+	countSession := database.GetFreshSession(h.db).WithContext(ctx).Model(&models.Program{})
+	if search != "" {
+		// Tìm kiếm toàn văn, không phân biệt dấu, xếp theo mức độ liên quan
+		session = session.Where(textsearch.Programs.Match(search)).Order(textsearch.Programs.Rank(search))
+		countSession = countSession.Where(textsearch.Programs.Match(search))
+	}
+	// Lọc theo danh mục (gồm danh mục con) và thẻ
+	session = filterTaxonomy(c, session, taxonomy.Programs)
+	countSession = filterTaxonomy(c, countSession, taxonomy.Programs)
 
 	if err := session.
 		Order("created_at DESC").
@@ -50,10 +64,35 @@ func (h *ProgramHandler) GetPrograms(c *gin.Context) {
 		return
 	}
 
+	if search != "" && len(programs) > 0 {
+		ids := make([]uuid.UUID, len(programs))
+		for i := range programs {
+			ids[i] = programs[i].ID
+		}
+		snippets := searchSnippets(database.GetFreshSession(h.db).WithContext(ctx), textsearch.Programs, search, ids)
+		for i := range programs {
+			programs[i].Snippet = snippets[programs[i].ID.String()]
+		}
+	}
+
+	ids := make([]string, len(programs))
+	for i := range programs {
+		ids[i] = programs[i].ID.String()
+	}
+	translations := loadTranslations(c, database.GetFreshSession(h.db).WithContext(ctx), i18n.Programs, ids)
+	for i := range programs {
+		i18n.Apply(&programs[i], translations[ids[i]])
+	}
+	taxonomies := loadTaxonomy(database.GetFreshSession(h.db).WithContext(ctx), taxonomy.Programs, ids)
+	for i := range programs {
+		tax := taxonomies[ids[i]]
+		programs[i].Categories, programs[i].Tags = tax.Categories, tax.Tags
+	}
+
 	// Get total count for pagination
 	var total int64
-	countSession := database.GetFreshSession(h.db).WithContext(ctx).Model(&models.Program{})
 	countSession.Count(&total)
+	facets := listFacets(database.GetFreshSession(h.db).WithContext(ctx), countSession, taxonomy.Programs)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -64,6 +103,7 @@ func (h *ProgramHandler) GetPrograms(c *gin.Context) {
 			"count":  len(programs),
 			"total":  total,
 		},
+		"facets": facets,
 	})
 }
 
@@ -100,12 +140,63 @@ func (h *ProgramHandler) GetProgramByID(c *gin.Context) {
 		})
 		return
 	}
+	i18n.Apply(&program, itemTranslations(c, h.db, i18n.Programs, program.ID.String()))
+	program.Categories, program.Tags = itemTaxonomy(h.db, taxonomy.Programs, program.ID.String())
+	assigned, err := mentors.LoadAssigned(session, models.EntityTypeProgram, program.ID, true)
+	if err != nil {
+		log.Printf("DB error GetProgramByID(%s) mentors: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Lỗi truy vấn dữ liệu",
+		})
+		return
+	}
+	program.Mentors = assigned
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    program,
 	})
 }
 
+// GET /api/v1/programs/:id/mentors
+// Mentors assigned to a program, including inactive ones
+func (h *ProgramHandler) GetProgramMentors(c *gin.Context) {
+	programID, ok := h.findProgramID(c)
+	if !ok {
+		return
+	}
+	respondAssignedMentors(c, h.db, models.EntityTypeProgram, programID)
+}
+
+// PUT /api/v1/programs/:id/mentors
+// Replaces the mentors of a program, in display order; body is models.SetAssignmentsRequest
+func (h *ProgramHandler) SetProgramMentors(c *gin.Context) {
+	programID, ok := h.findProgramID(c)
+	if !ok {
+		return
+	}
+	setAssignedMentors(c, h.db, models.EntityTypeProgram, programID, "Program not found")
+}
+
+// findProgramID returns the ID of the program named by the :id path param, answering 404
+// when there is none
+func (h *ProgramHandler) findProgramID(c *gin.Context) (uuid.UUID, bool) {
+	programID, err := uuid.Parse(c.Param("id"))
+	var count int64
+	if err == nil {
+		err = h.db.Model(&models.Program{}).Where("id = ?", programID).Count(&count).Error
+	}
+	if err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Program not found",
+		})
+		return uuid.Nil, false
+	}
+	return programID, true
+}
+
 // --- helpers ---
 
 func parseIntWithDefaultClamp(s string, def, min, max int) int {
@@ -4,10 +4,16 @@ import (
 	"net/http"
 	"strconv"
 
+	"msc-backend-api/internal/i18n"
+	"msc-backend-api/internal/mentors"
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/redirects"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/textsearch"
 	"msc-backend-api/pkg/database"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -20,14 +26,16 @@ func NewProjectHandler(db *gorm.DB) *ProjectHandler {
 }
 
 // @Summary Get all projects
-// @Description Retrieve all projects with pagination and filtering
+// @Description Retrieve all projects with pagination and filtering. Mentors linked to mentor records are filled from the live records.
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
-// @Param category query string false "Filter by category"
+// @Param category query string false "Filter by category slug or name, including subcategories"
+// @Param tag query string false "Filter by tag slug"
 // @Param status query string false "Filter by status"
+// @Param search query string false "Full-text search (accent-insensitive), results ranked by relevance"
 // @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
 // @Router /api/projects [get]
 func (h *ProjectHandler) GetProjects(c *gin.Context) {
@@ -35,6 +43,7 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
 	category := c.Query("category")
 	status := c.Query("status")
+	search := c.Query("search")
 
 	if page < 1 {
 		page = 1
@@ -49,12 +58,13 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 	query := db.Model(&models.Project{})
 
 	// Apply filters
-	if category != "" {
-		query = query.Where("category = ?", category)
-	}
+	query = taxonomy.Filter(query, taxonomy.Projects, category, c.Query("tag"))
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
+	if search != "" {
+		query = query.Where(textsearch.Projects.Match(search)).Order(textsearch.Projects.Rank(search))
+	}
 	// Remove default status filter - get all projects
 
 	// Get total count
@@ -66,6 +76,7 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 		})
 		return
 	}
+	facets := listFacets(db, query, taxonomy.Projects)
 
 	// Get projects
 	var projects []models.Project
@@ -77,6 +88,38 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 		return
 	}
 
+	if search != "" && len(projects) > 0 {
+		ids := make([]uuid.UUID, len(projects))
+		for i := range projects {
+			ids[i] = projects[i].ID
+		}
+		snippets := searchSnippets(db, textsearch.Projects, search, ids)
+		for i := range projects {
+			projects[i].Snippet = snippets[projects[i].ID.String()]
+		}
+	}
+
+	ids := make([]string, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID.String()
+	}
+	translations := loadTranslations(c, db, i18n.Projects, ids)
+	for i := range projects {
+		i18n.Apply(&projects[i], translations[ids[i]])
+	}
+	taxonomies := loadTaxonomy(db, taxonomy.Projects, ids)
+	for i := range projects {
+		tax := taxonomies[ids[i]]
+		projects[i].Categories, projects[i].Tags = tax.Categories, tax.Tags
+	}
+	if err := mentors.FillProjectMentors(db, projects, !isStaff(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch project mentors",
+		})
+		return
+	}
+
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
 
 	response := models.PaginatedResponse{
@@ -85,6 +128,7 @@ func (h *ProjectHandler) GetProjects(c *gin.Context) {
 		Page:       page,
 		Limit:      limit,
 		TotalPages: totalPages,
+		Facets:     facets,
 	}
 
 	c.JSON(http.StatusOK, models.APIResponse{
@@ -122,6 +166,12 @@ func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
 		return
 	}
 
+	i18n.Apply(&project, itemTranslations(c, h.db, i18n.Projects, project.ID.String()))
+	project.Categories, project.Tags = itemTaxonomy(h.db, taxonomy.Projects, project.ID.String())
+	if !h.fillMentors(c, &project, !isStaff(c)) {
+		return
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    project,
@@ -129,12 +179,13 @@ func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
 }
 
 // @Summary Get project by slug
-// @Description Retrieve a specific project by slug
+// @Description Retrieve a specific project by slug. Mentors linked to mentor records are filled from the live records.
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Param slug path string true "Project slug"
 // @Success 200 {object} models.APIResponse{data=models.Project}
+// @Success 301 {object} models.APIResponse{data=models.Redirect} "Old slug; Location has the current one"
 // @Failure 404 {object} models.APIResponse
 // @Router /api/projects/slug/{slug} [get]
 func (h *ProjectHandler) GetProjectBySlug(c *gin.Context) {
@@ -145,6 +196,9 @@ func (h *ProjectHandler) GetProjectBySlug(c *gin.Context) {
 
 	if err := db.Where("slug = ?", slug).First(&project).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
+			if respondMovedSlug(c, db, redirects.Projects) {
+				return
+			}
 			c.JSON(http.StatusNotFound, models.APIResponse{
 				Success: false,
 				Message: "Project not found",
@@ -158,6 +212,12 @@ func (h *ProjectHandler) GetProjectBySlug(c *gin.Context) {
 		return
 	}
 
+	i18n.Apply(&project, itemTranslations(c, h.db, i18n.Projects, project.ID.String()))
+	project.Categories, project.Tags = itemTaxonomy(h.db, taxonomy.Projects, project.ID.String())
+	if !h.fillMentors(c, &project, !isStaff(c)) {
+		return
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    project,
@@ -184,14 +244,20 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		return
 	}
 
+	// The slug is optional; a taken one gets a numeric suffix
+	base := slugBase(req.Slug, req.Title)
+	if base == "" {
+		respondInvalidSlug(c)
+		return
+	}
+
 	project := models.Project{
-		Slug:        req.Slug,
 		Title:       req.Title,
 		Description: req.Description,
 		Image:       req.Image,
 		Category:    req.Category,
 		Status:      req.Status,
-		MentorsJSON: req.Mentors,
+		Mentors:     "[]",
 	}
 
 	if project.Status == "" {
@@ -199,7 +265,16 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	}
 
 	db := database.GetFreshSession(h.db)
-	if err := db.Create(&project).Error; err != nil {
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if project.Slug, err = uniqueSlug(tx, "projects", base); err != nil {
+			return err
+		}
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		return mentors.SetProjectMentors(tx, &project, req.Mentors)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to create project",
@@ -207,6 +282,10 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		return
 	}
 
+	if !h.fillMentors(c, &project, false) {
+		return
+	}
+
 	c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
 		Message: "Project created successfully",
@@ -255,16 +334,30 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	// Update fields
-	project.Slug = req.Slug
+	// Keep the slug unless a new one is given
+	projectSlug, ok := checkSlugChange(c, db, "projects", project.ID.String(), project.Slug, req.Slug)
+	if !ok {
+		return
+	}
+
+	// Update fields; the old slug keeps redirecting to the project
+	oldSlug := project.Slug
+	project.Slug = projectSlug
 	project.Title = req.Title
 	project.Description = req.Description
 	project.Image = req.Image
 	project.Category = req.Category
 	project.Status = req.Status
-	project.MentorsJSON = req.Mentors
 
-	if err := db.Save(&project).Error; err != nil {
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&project).Error; err != nil {
+			return err
+		}
+		if err := mentors.SetProjectMentors(tx, &project, req.Mentors); err != nil {
+			return err
+		}
+		return redirects.Record(tx, models.EntityTypeProject, project.ID.String(), oldSlug, project.Slug)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
 			Message: "Failed to update project",
@@ -272,6 +365,10 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
+	if !h.fillMentors(c, &project, false) {
+		return
+	}
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "Project updated successfully",
@@ -280,7 +377,7 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 }
 
 // @Summary Delete a project
-// @Description Delete a project (admin/editor only)
+// @Description Move a project to trash (admin/editor only)
 // @Tags projects
 // @Accept json
 // @Produce json
@@ -313,6 +410,21 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Project deleted successfully",
+		Message: "Project moved to trash",
 	})
 }
+
+// fillMentors sets a project's mentors from the live mentor records, answering 500 when
+// they cannot be loaded. With publicOnly inactive mentors are left out.
+func (h *ProjectHandler) fillMentors(c *gin.Context, project *models.Project, publicOnly bool) bool {
+	projects := []models.Project{*project}
+	if err := mentors.FillProjectMentors(h.db, projects, publicOnly); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch project mentors",
+		})
+		return false
+	}
+	project.MentorsJSON = projects[0].MentorsJSON
+	return true
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/redirects"
+	"msc-backend-api/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RedirectHandler struct {
+	db      *gorm.DB
+	siteURL string
+}
+
+func NewRedirectHandler(db *gorm.DB, cfg *config.Config) *RedirectHandler {
+	return &RedirectHandler{
+		db:      db,
+		siteURL: strings.TrimSuffix(cfg.FrontendURL, "/"),
+	}
+}
+
+// @Summary Resolve a slug
+// @Description Resolve a slug to the public page of its item. Old slugs answer with a 301 to the page under the current slug.
+// @Tags redirects
+// @Produce json
+// @Param type path string true "Content type (projects, blog, mentors, mscers)"
+// @Param slug path string true "Slug, current or old"
+// @Success 200 {object} models.APIResponse{data=models.Redirect}
+// @Success 301 {object} models.APIResponse{data=models.Redirect}
+// @Failure 404 {object} models.APIResponse
+// @Router /api/redirects/{type}/{slug} [get]
+func (h *RedirectHandler) ResolveSlug(c *gin.Context) {
+	t, err := redirects.Lookup(c.Param("type"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Unknown content type",
+		})
+		return
+	}
+	requested := c.Param("slug")
+
+	var live int64
+	if err := h.db.Table(t.Table).Where("slug = ?", requested).Where(t.Public).Count(&live).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to resolve slug",
+		})
+		return
+	}
+	if live > 0 {
+		c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Data:    models.Redirect{Slug: requested, Path: t.Path(requested)},
+		})
+		return
+	}
+
+	current, err := redirects.Current(h.db, t, requested)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, models.APIResponse{
+				Success: false,
+				Message: "Slug not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to resolve slug",
+		})
+		return
+	}
+
+	c.Header("Location", h.siteURL+t.Path(url.PathEscape(current)))
+	c.JSON(http.StatusMovedPermanently, models.APIResponse{
+		Success: false,
+		Message: "Moved permanently",
+		Data:    models.Redirect{Slug: current, Path: t.Path(current)},
+	})
+}
+
+// respondMovedSlug answers a by-slug request for an old slug of an item. The Location
+// points at the same endpoint with the current slug, so clients that follow redirects
+// get the item, and the payload tells the rest where it went. It returns false when the
+// slug is not in the history and the request still needs a response.
+func respondMovedSlug(c *gin.Context, db *gorm.DB, t redirects.Type) bool {
+	current, err := redirects.Current(db, t, c.Param("slug"))
+	if err != nil {
+		return false
+	}
+
+	c.Header("Location", strings.Replace(c.FullPath(), ":slug", url.PathEscape(current), 1))
+	c.JSON(http.StatusMovedPermanently, models.APIResponse{
+		Success: false,
+		Message: "Moved permanently",
+		Data:    models.Redirect{Slug: current, Path: t.Path(current)},
+	})
+	return true
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/textsearch"
+	"msc-backend-api/pkg/tiptap"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Values of the ?render= query param
+const (
+	renderHTML = "html"
+	renderText = "text"
+)
+
+// renderMode reads the ?render= query param. It writes a 400 response and returns false
+// for unsupported values.
+func renderMode(c *gin.Context) (string, bool) {
+	switch mode := c.Query("render"); mode {
+	case "", renderHTML, renderText:
+		return mode, true
+	}
+	c.JSON(http.StatusBadRequest, models.APIResponse{
+		Success: false,
+		Message: "render must be html or text",
+	})
+	return "", false
+}
+
+// renderContent renders stored Tiptap content in the requested mode. Legacy documents
+// that fail validation render as empty instead of failing the whole response.
+func renderContent(mode, content string) (html, text string) {
+	if mode == "" {
+		return "", ""
+	}
+
+	doc, err := tiptap.Parse(content)
+	if err != nil {
+		log.Printf("Cannot render stored content: %v", err)
+		return "", ""
+	}
+	if mode == renderHTML {
+		return tiptap.HTML(doc), ""
+	}
+	return "", tiptap.Text(doc)
+}
+
+func renderPost(mode string, post *models.Post) {
+	post.ContentHTML, post.ContentText = renderContent(mode, post.Content)
+}
+
+func renderLessons(mode string, lessons []models.Lesson) {
+	for i := range lessons {
+		lessons[i].ContentHTML, lessons[i].ContentText = renderContent(mode, lessons[i].Content)
+	}
+}
+
+// checkContent rejects Tiptap documents the renderer would not accept
+func checkContent(c *gin.Context, content string) bool {
+	if err := tiptap.Validate(content); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid content: " + err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// searchSnippets loads highlighted snippets for a page of search results, keyed by ID.
+// A failure only costs the highlights, not the results.
+func searchSnippets(db *gorm.DB, src textsearch.Source, term string, ids interface{}) map[string]string {
+	snippets, err := src.Snippets(db, term, ids)
+	if err != nil {
+		log.Printf("Cannot load search snippets for %s: %v", src.Table, err)
+	}
+	return snippets
+}
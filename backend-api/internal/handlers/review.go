@@ -0,0 +1,660 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/reviews"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewHandler struct {
+	db *gorm.DB
+}
+
+func NewReviewHandler(db *gorm.DB) *ReviewHandler {
+	return &ReviewHandler{db: db}
+}
+
+// @Summary Get course reviews
+// @Description Get the approved reviews of a course, newest first
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/reviews [get]
+func (h *ReviewHandler) GetCourseReviews(c *gin.Context) {
+	h.list(c, models.EntityTypeCourse, false)
+}
+
+// @Summary Get my course review
+// @Description Get the current user's review of a course, whatever its moderation status
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.APIResponse{data=models.Review}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/reviews/mine [get]
+func (h *ReviewHandler) GetMyCourseReview(c *gin.Context) {
+	h.mine(c, models.EntityTypeCourse)
+}
+
+// @Summary Review a course
+// @Description Rate a course from 1 to 5 with an optional text, or replace your earlier review. Only learners enrolled in the course can review it; reviews wait for moderation unless written by an editor.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Param review body models.SubmitReviewRequest true "Review"
+// @Success 200 {object} models.APIResponse{data=models.Review}
+// @Success 201 {object} models.APIResponse{data=models.Review}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/reviews/mine [put]
+func (h *ReviewHandler) SubmitCourseReview(c *gin.Context) {
+	h.submit(c, models.EntityTypeCourse)
+}
+
+// @Summary Delete my course review
+// @Description Withdraw the current user's review of a course
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Course ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /courses/{id}/reviews/mine [delete]
+func (h *ReviewHandler) DeleteMyCourseReview(c *gin.Context) {
+	h.withdraw(c, models.EntityTypeCourse)
+}
+
+// @Summary Get mentor reviews
+// @Description Get the approved reviews of a mentor, newest first
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/reviews [get]
+func (h *ReviewHandler) GetMentorReviews(c *gin.Context) {
+	h.list(c, models.EntityTypeMentor, false)
+}
+
+// @Summary Get public mentor reviews
+// @Description Get the approved reviews of an active mentor for the public site, newest first
+// @Tags reviews
+// @Produce json
+// @Param id path string true "Mentor ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Failure 404 {object} models.APIResponse
+// @Router /api/mentors/{id}/reviews [get]
+func (h *ReviewHandler) GetPublicMentorReviews(c *gin.Context) {
+	h.list(c, models.EntityTypeMentor, true)
+}
+
+// @Summary Get my mentor review
+// @Description Get the current user's review of a mentor, whatever its moderation status
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Success 200 {object} models.APIResponse{data=models.Review}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/reviews/mine [get]
+func (h *ReviewHandler) GetMyMentorReview(c *gin.Context) {
+	h.mine(c, models.EntityTypeMentor)
+}
+
+// @Summary Review a mentor
+// @Description Rate a mentor from 1 to 5 with an optional text, or replace your earlier review. Only learners enrolled in a course the mentor teaches, or who had a session with the mentor, can review them; reviews wait for moderation unless written by an editor.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Param review body models.SubmitReviewRequest true "Review"
+// @Success 200 {object} models.APIResponse{data=models.Review}
+// @Success 201 {object} models.APIResponse{data=models.Review}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/reviews/mine [put]
+func (h *ReviewHandler) SubmitMentorReview(c *gin.Context) {
+	h.submit(c, models.EntityTypeMentor)
+}
+
+// @Summary Delete my mentor review
+// @Description Withdraw the current user's review of a mentor
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Mentor ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /mentors/{id}/reviews/mine [delete]
+func (h *ReviewHandler) DeleteMyMentorReview(c *gin.Context) {
+	h.withdraw(c, models.EntityTypeMentor)
+}
+
+// @Summary Get reviews for moderation
+// @Description Get course and mentor reviews by moderation status, newest first
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending (default), approved or rejected"
+// @Param entity_type query string false "course or mentor"
+// @Param entity_id query string false "Only reviews of this course or mentor"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} models.APIResponse{data=models.PaginatedResponse}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /reviews [get]
+func (h *ReviewHandler) GetReviews(c *gin.Context) {
+	page, limit := reviewPage(c)
+
+	status := c.DefaultQuery("status", models.ReviewStatusPending)
+	switch status {
+	case models.ReviewStatusPending, models.ReviewStatusApproved, models.ReviewStatusRejected:
+	default:
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid status",
+		})
+		return
+	}
+
+	query := h.db.Model(&models.Review{}).Where("status = ?", status)
+	if entityType := c.Query("entity_type"); entityType != "" {
+		if !reviews.Valid(entityType) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid entity type",
+			})
+			return
+		}
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if entityID := c.Query("entity_id"); entityID != "" {
+		id, err := uuid.Parse(entityID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid entity id",
+			})
+			return
+		}
+		query = query.Where("entity_id = ?", id)
+	}
+
+	h.respondPage(c, query, page, limit)
+}
+
+// @Summary Approve review
+// @Description Publish a review and count it in the rating of its course or mentor
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Review ID"
+// @Success 200 {object} models.APIResponse{data=models.Review}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /reviews/{id}/approve [patch]
+func (h *ReviewHandler) ApproveReview(c *gin.Context) {
+	h.moderate(c, models.ReviewStatusApproved, "Review approved successfully")
+}
+
+// @Summary Reject review
+// @Description Hide a review and leave it out of the rating of its course or mentor
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Review ID"
+// @Success 200 {object} models.APIResponse{data=models.Review}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /reviews/{id}/reject [patch]
+func (h *ReviewHandler) RejectReview(c *gin.Context) {
+	h.moderate(c, models.ReviewStatusRejected, "Review rejected successfully")
+}
+
+// @Summary Delete review
+// @Description Permanently delete a review
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Review ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /reviews/{id} [delete]
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondReviewNotFound(c)
+		return
+	}
+
+	var review models.Review
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&review).Error; err != nil {
+			return err
+		}
+		if err := reviews.Lock(tx, review.EntityType, review.EntityID); err != nil {
+			return err
+		}
+		result := tx.Delete(&review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return reviews.Refresh(tx, review.EntityType, review.EntityID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondReviewNotFound(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to delete review",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Review deleted successfully",
+	})
+}
+
+// list writes a page of the approved reviews of the course or mentor of the :id parameter
+func (h *ReviewHandler) list(c *gin.Context, entityType string, publicOnly bool) {
+	entityID, ok := h.findTarget(c, entityType, publicOnly)
+	if !ok {
+		return
+	}
+	page, limit := reviewPage(c)
+
+	query := h.db.Model(&models.Review{}).
+		Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, models.ReviewStatusApproved)
+	h.respondPage(c, query, page, limit)
+}
+
+// mine writes the current user's review of the course or mentor of the :id parameter
+func (h *ReviewHandler) mine(c *gin.Context, entityType string) {
+	entityID, ok := h.findTarget(c, entityType, false)
+	if !ok {
+		return
+	}
+
+	var review models.Review
+	if err := h.db.Preload("User").
+		Where("entity_type = ? AND entity_id = ? AND user_id = ?", entityType, entityID, currentUserID(c)).
+		First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondReviewNotFound(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch review",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    review,
+	})
+}
+
+// submit creates the current user's review of the course or mentor of the :id parameter
+// or replaces the one they wrote before. A replaced review goes through moderation again.
+func (h *ReviewHandler) submit(c *gin.Context, entityType string) {
+	entityID, ok := h.findTarget(c, entityType, false)
+	if !ok {
+		return
+	}
+
+	var req models.SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+
+	userID := currentUserID(c)
+	allowed, err := reviews.CanReview(h.db, entityType, entityID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to save review",
+		})
+		return
+	}
+	if !allowed {
+		message := "Only learners enrolled in this course can review it"
+		if entityType == models.EntityTypeMentor {
+			message = "Only learners of this mentor can review them"
+		}
+		c.JSON(http.StatusForbidden, models.APIResponse{
+			Success: false,
+			Message: message,
+		})
+		return
+	}
+
+	status := models.ReviewStatusPending
+	message := "Review submitted for moderation"
+	if isStaff(c) {
+		status = models.ReviewStatusApproved
+		message = "Review saved successfully"
+	}
+
+	var review models.Review
+	created := false
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := reviews.Lock(tx, entityType, entityID); err != nil {
+			return err
+		}
+		var existing int64
+		if err := tx.Model(&models.Review{}).
+			Where("entity_type = ? AND entity_id = ? AND user_id = ?", entityType, entityID, userID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		created = existing == 0
+
+		// Insert or replace in one statement, so a concurrent first submit cannot fail
+		// on the one-review-per-author index
+		review = models.Review{
+			EntityType: entityType,
+			EntityID:   entityID,
+			UserID:     userID,
+			Rating:     req.Rating,
+			Content:    req.Content,
+			Status:     status,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "content", "status", "moderated_by", "moderated_at", "updated_at"}),
+		}).Create(&review).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ? AND user_id = ?", entityType, entityID, userID).
+			First(&review).Error; err != nil {
+			return err
+		}
+		return reviews.Refresh(tx, entityType, entityID)
+	})
+	if err != nil {
+		log.Printf("Cannot save review of %s %s: %v", entityType, entityID, err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to save review",
+		})
+		return
+	}
+
+	code := http.StatusOK
+	if created {
+		code = http.StatusCreated
+	}
+	c.JSON(code, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    review,
+	})
+}
+
+// withdraw deletes the current user's review of the course or mentor of the :id parameter
+func (h *ReviewHandler) withdraw(c *gin.Context, entityType string) {
+	entityID, ok := h.findTarget(c, entityType, false)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := reviews.Lock(tx, entityType, entityID); err != nil {
+			return err
+		}
+		result := tx.Where("entity_type = ? AND entity_id = ? AND user_id = ?", entityType, entityID, currentUserID(c)).
+			Delete(&models.Review{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return reviews.Refresh(tx, entityType, entityID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondReviewNotFound(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to delete review",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Review deleted successfully",
+	})
+}
+
+// moderate moves a review to a new moderation status and refreshes the rating of its
+// course or mentor, guarding on the old status so two moderators can't act on the same
+// review at once
+func (h *ReviewHandler) moderate(c *gin.Context, status, message string) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondReviewNotFound(c)
+		return
+	}
+
+	var review models.Review
+	if err := h.db.Preload("User").Where("id = ?", id).First(&review).Error; err != nil {
+		respondReviewNotFound(c)
+		return
+	}
+	if review.Status == status {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Review is already " + status,
+		})
+		return
+	}
+
+	moderatorID := currentUserID(c)
+	now := time.Now()
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := reviews.Lock(tx, review.EntityType, review.EntityID); err != nil {
+			return err
+		}
+		result := tx.Model(&models.Review{}).
+			Where("id = ? AND status = ?", review.ID, review.Status).
+			Updates(map[string]interface{}{
+				"status":       status,
+				"moderated_by": moderatorID,
+				"moderated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStatusConflict
+		}
+		return reviews.Refresh(tx, review.EntityType, review.EntityID)
+	})
+	if err != nil {
+		if errors.Is(err, errStatusConflict) {
+			c.JSON(http.StatusConflict, models.APIResponse{
+				Success: false,
+				Message: "Review was moderated by someone else, reload and try again",
+			})
+			return
+		}
+		log.Printf("Cannot moderate review %s: %v", review.ID, err)
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to moderate review",
+		})
+		return
+	}
+
+	review.Status = status
+	review.ModeratedBy = &moderatorID
+	review.ModeratedAt = &now
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    review,
+	})
+}
+
+// findTarget returns the id of the live course or mentor of the :id parameter or writes
+// a 404. With publicOnly, inactive mentors are not found.
+func (h *ReviewHandler) findTarget(c *gin.Context, entityType string, publicOnly bool) (uuid.UUID, bool) {
+	notFound := "Course not found"
+	query := h.db.Model(&models.Course{})
+	if entityType == models.EntityTypeMentor {
+		notFound = "Mentor not found"
+		query = h.db.Model(&models.Mentor{})
+		if publicOnly {
+			query = query.Where("status = ?", "active")
+		}
+	}
+
+	var count int64
+	id, err := uuid.Parse(c.Param("id"))
+	if err == nil {
+		if err := query.Where("id = ?", id).Count(&count).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Failed to fetch reviews",
+			})
+			return uuid.Nil, false
+		}
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: notFound,
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondPage writes a page of the reviews matched by query, newest first
+func (h *ReviewHandler) respondPage(c *gin.Context, query *gorm.DB, page, limit int) {
+	var total int64
+	query.Count(&total)
+
+	list := []models.Review{}
+	if err := query.
+		Preload("User").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset((page - 1) * limit).
+		Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch reviews",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.PaginatedResponse{
+			Data:       list,
+			Total:      total,
+			Page:       page,
+			Limit:      limit,
+			TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+		},
+	})
+}
+
+// ratingOrder returns the ordering of the ?sort= option of course and mentor lists:
+// "rating" puts the best rated first and "reviews" the most reviewed. Without the option
+// it returns an empty ordering; unknown options get a 400.
+func ratingOrder(c *gin.Context) (string, bool) {
+	switch c.Query("sort") {
+	case "":
+		return "", true
+	case "rating":
+		return "rating_average DESC, rating_count DESC", true
+	case "reviews":
+		return "rating_count DESC, rating_average DESC", true
+	}
+	c.JSON(http.StatusBadRequest, models.APIResponse{
+		Success: false,
+		Message: "Invalid sort, use rating or reviews",
+	})
+	return "", false
+}
+
+func reviewPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func respondReviewNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: "Review not found",
+	})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/jsondiff"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RevisionDiff is the response of the revision diff endpoints
+type RevisionDiff struct {
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	Changes []jsondiff.Change `json:"changes"`
+}
+
+// findRevision loads a single revision of an entity by version number
+func findRevision(db *gorm.DB, entityType string, entityID uuid.UUID, version int) (*models.Revision, error) {
+	var revision models.Revision
+	if err := db.Preload("Author").
+		Where("entity_type = ? AND entity_id = ? AND version = ?", entityType, entityID, version).
+		First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// respondRevisions writes the revision list of an entity, newest first
+func respondRevisions(c *gin.Context, db *gorm.DB, entityType string, entityID uuid.UUID) {
+	var revisions []models.Revision
+	if err := db.Preload("Author").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("version DESC").
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch revisions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    revisions,
+	})
+}
+
+// respondRevision writes a single revision of an entity identified by the :version path param
+func respondRevision(c *gin.Context, db *gorm.DB, entityType string, entityID uuid.UUID) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid revision version",
+		})
+		return
+	}
+
+	revision, err := findRevision(db, entityType, entityID, version)
+	if err != nil {
+		respondRevisionLookupError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    revision,
+	})
+}
+
+// respondRevisionDiff compares the revisions given by the from and to query params.
+// "to" defaults to the latest revision and "from" to the one before it.
+func respondRevisionDiff(c *gin.Context, db *gorm.DB, entityType string, entityID uuid.UUID) {
+	var to int
+	var err error
+	if v := c.Query("to"); v != "" {
+		to, err = strconv.Atoi(v)
+	} else {
+		err = db.Model(&models.Revision{}).
+			Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&to).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid revision version",
+		})
+		return
+	}
+
+	from := to - 1
+	if v := c.Query("from"); v != "" {
+		if from, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid revision version",
+			})
+			return
+		}
+	}
+
+	fromRevision, err := findRevision(db, entityType, entityID, from)
+	if err != nil {
+		respondRevisionLookupError(c, err)
+		return
+	}
+	toRevision, err := findRevision(db, entityType, entityID, to)
+	if err != nil {
+		respondRevisionLookupError(c, err)
+		return
+	}
+
+	changes, err := jsondiff.Diff([]byte(fromRevision.Snapshot), []byte(toRevision.Snapshot))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to diff revisions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: RevisionDiff{
+			From:    from,
+			To:      to,
+			Changes: changes,
+		},
+	})
+}
+
+func respondRevisionLookupError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Revision not found",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.APIResponse{
+		Success: false,
+		Message: "Failed to fetch revision",
+	})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/workflow"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduleHandler struct {
+	db *gorm.DB
+}
+
+func NewScheduleHandler(db *gorm.DB) *ScheduleHandler {
+	return &ScheduleHandler{db: db}
+}
+
+// ScheduleEntry is one upcoming automatic publish or unpublish
+type ScheduleEntry struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   uuid.UUID `json:"entity_id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	Status     string    `json:"status"`
+	Action     string    `json:"action"` // publish, unpublish
+	At         time.Time `json:"at"`
+}
+
+// @Summary Get publishing schedule
+// @Description List upcoming scheduled publishes and unpublishes of posts and courses
+// @Tags schedule
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start of the window (RFC3339, default now)"
+// @Param to query string false "End of the window (RFC3339, default 30 days from now)"
+// @Success 200 {object} models.APIResponse{data=[]ScheduleEntry}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Router /schedule [get]
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	from := time.Now()
+	to := from.AddDate(0, 0, 30)
+
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid from, expected RFC3339",
+			})
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid to, expected RFC3339",
+			})
+			return
+		}
+	}
+
+	var posts []models.Post
+	if err := h.db.Where("(status = ? AND publish_at BETWEEN ? AND ?) OR (status IN ? AND unpublish_at BETWEEN ? AND ?)",
+		workflow.StatusScheduled, from, to,
+		[]string{workflow.StatusScheduled, workflow.StatusPublished}, from, to).
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch schedule",
+		})
+		return
+	}
+
+	var courses []models.Course
+	if err := h.db.Where("(status = ? AND publish_at BETWEEN ? AND ?) OR (status IN ? AND unpublish_at BETWEEN ? AND ?)",
+		workflow.StatusScheduled, from, to,
+		[]string{workflow.StatusScheduled, workflow.StatusPublished}, from, to).
+		Find(&courses).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch schedule",
+		})
+		return
+	}
+
+	entries := []ScheduleEntry{}
+	for _, p := range posts {
+		entries = appendScheduleEntries(entries, models.EntityTypePost, p.ID, p.Title, p.Slug, p.Status, p.PublishAt, p.UnpublishAt, from, to)
+	}
+	for _, co := range courses {
+		entries = appendScheduleEntries(entries, models.EntityTypeCourse, co.ID, co.Title, co.Slug, co.Status, co.PublishAt, co.UnpublishAt, from, to)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    entries,
+	})
+}
+
+func appendScheduleEntries(entries []ScheduleEntry, entityType string, id uuid.UUID, title, slug, status string, publishAt, unpublishAt *time.Time, from, to time.Time) []ScheduleEntry {
+	inWindow := func(t *time.Time) bool {
+		return t != nil && !t.Before(from) && !t.After(to)
+	}
+
+	entry := ScheduleEntry{EntityType: entityType, EntityID: id, Title: title, Slug: slug, Status: status}
+	if status == workflow.StatusScheduled && inWindow(publishAt) {
+		entry.Action, entry.At = "publish", *publishAt
+		entries = append(entries, entry)
+	}
+	if inWindow(unpublishAt) {
+		entry.Action, entry.At = "unpublish", *unpublishAt
+		entries = append(entries, entry)
+	}
+	return entries
+}
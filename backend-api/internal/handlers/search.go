@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/textsearch"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type SearchHandler struct {
+	db *gorm.DB
+}
+
+func NewSearchHandler(db *gorm.DB) *SearchHandler {
+	return &SearchHandler{db: db}
+}
+
+// SearchHit is one result of the site search
+type SearchHit struct {
+	Type    string  `json:"type"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Slug    string  `json:"slug,omitempty"`
+	URL     string  `json:"url"`
+	Snippet string  `json:"snippet,omitempty"`
+	Rank    float64 `json:"rank"`
+}
+
+// SearchGroup holds the hits of one content type
+type SearchGroup struct {
+	Type  string      `json:"type"`
+	Total int64       `json:"total"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// SearchResults is the payload of GET /api/search
+type SearchResults struct {
+	Query  string        `json:"query"`
+	Groups []SearchGroup `json:"groups"`
+}
+
+// Suggestion is one autocomplete entry
+type Suggestion struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// searchScope is a publicly searchable content type and how its hits link to the site
+type searchScope struct {
+	Type   string
+	Source textsearch.Source
+	// Title and Slug are select expressions
+	Title string
+	Slug  string
+	// Public restricts rows to what the public site shows
+	Public string
+	URL    func(id, slug string) string
+}
+
+var searchScopes = []searchScope{
+	{
+		Type:   "program",
+		Source: textsearch.Programs,
+		Title:  "title",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL",
+		URL:    func(id, slug string) string { return "/dao-tao/" + slug },
+	},
+	{
+		Type:   "project",
+		Source: textsearch.Projects,
+		Title:  "title",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL",
+		URL:    func(id, slug string) string { return "/du-an/" + slug },
+	},
+	{
+		Type:   "blog",
+		Source: textsearch.AllBlogPosts,
+		Title:  "title",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL",
+		URL:    func(id, slug string) string { return "/chia-se/" + slug },
+	},
+	{
+		Type:   "mentor",
+		Source: textsearch.Mentors,
+		Title:  "name",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL AND status = 'active'",
+		URL:    func(id, slug string) string { return "/mentors/" + slug },
+	},
+}
+
+const maxQueryLength = 200
+
+// @Summary Search the site
+// @Description Full-text search across programs, projects, blog posts and mentors. Hits are grouped by type and ranked by relevance; groups are ordered by their best hit.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search query (accent-insensitive, supports quotes, OR and -)"
+// @Param limit query int false "Hits per group" default(5)
+// @Success 200 {object} models.APIResponse{data=SearchResults}
+// @Failure 400 {object} models.APIResponse
+// @Router /api/search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	q, ok := searchQuery(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit < 1 || limit > 20 {
+		limit = 5
+	}
+
+	results := SearchResults{Query: q, Groups: []SearchGroup{}}
+	for _, scope := range searchScopes {
+		group, err := h.searchScope(scope, q, limit)
+		if err != nil {
+			log.Printf("Search in %s failed: %v", scope.Source.Table, err)
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Search failed",
+			})
+			return
+		}
+		// Total is counted separately, so rows that changed in between can leave it
+		// positive with no hits; the groups are ordered by their top hit
+		if len(group.Hits) > 0 {
+			results.Groups = append(results.Groups, group)
+		}
+	}
+
+	sort.SliceStable(results.Groups, func(i, j int) bool {
+		return results.Groups[i].Hits[0].Rank > results.Groups[j].Hits[0].Rank
+	})
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    results,
+	})
+}
+
+func (h *SearchHandler) searchScope(scope searchScope, q string, limit int) (SearchGroup, error) {
+	group := SearchGroup{Type: scope.Type, Hits: []SearchHit{}}
+	src := scope.Source
+
+	base := h.db.Table(src.Table).Where(scope.Public).Where(src.Match(q))
+	if err := base.Count(&group.Total).Error; err != nil {
+		return group, err
+	}
+	if group.Total == 0 {
+		return group, nil
+	}
+
+	var rows []struct {
+		ID      string
+		Title   string
+		Slug    string
+		Snippet string
+		Rank    float64
+	}
+	err := h.db.Table(src.Table).
+		Select("id::text AS id, "+scope.Title+" AS title, "+scope.Slug+" AS slug, "+
+			src.HeadlineSQL()+" AS snippet, "+
+			"ts_rank_cd(search_vector, "+textsearch.Query()+") AS rank", q, q).
+		Where(scope.Public).
+		Where(src.Match(q)).
+		Order("rank DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return group, err
+	}
+
+	for _, r := range rows {
+		group.Hits = append(group.Hits, SearchHit{
+			Type:    scope.Type,
+			ID:      r.ID,
+			Title:   r.Title,
+			Slug:    r.Slug,
+			URL:     scope.URL(r.ID, r.Slug),
+			Snippet: textsearch.Highlight(r.Snippet),
+			Rank:    r.Rank,
+		})
+	}
+	return group, nil
+}
+
+// @Summary Search suggestions
+// @Description Prefix autocomplete on titles and tags (categories, mentor specialties) of public content
+// @Tags search
+// @Produce json
+// @Param q query string true "Typed prefix, e.g. \"khoa h\""
+// @Param limit query int false "Max suggestions" default(8)
+// @Success 200 {object} models.APIResponse{data=[]Suggestion}
+// @Failure 400 {object} models.APIResponse
+// @Router /api/search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	q, ok := searchQuery(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "8"))
+	if limit < 1 || limit > 20 {
+		limit = 8
+	}
+
+	suggestions := []Suggestion{}
+	prefix := textsearch.PrefixQuery(q)
+	if prefix == "" {
+		c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: suggestions})
+		return
+	}
+
+	// One indexed query per type, merged into a single ranked list
+	var ranked []struct {
+		Suggestion
+		Rank float64
+	}
+	for _, scope := range searchScopes {
+		src := scope.Source
+		var rows []struct {
+			ID    string
+			Title string
+			Slug  string
+			Rank  float64
+		}
+		err := h.db.Table(src.Table).
+			Select("id::text AS id, "+scope.Title+" AS title, "+scope.Slug+" AS slug, "+
+				"ts_rank("+src.Suggest+", to_tsquery('"+textsearch.Config+"', ?)) AS rank", prefix).
+			Where(scope.Public).
+			Where(src.SuggestMatch(prefix)).
+			Order("rank DESC").
+			Limit(limit).
+			Scan(&rows).Error
+		if err != nil {
+			log.Printf("Suggest in %s failed: %v", src.Table, err)
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Search failed",
+			})
+			return
+		}
+		for _, r := range rows {
+			ranked = append(ranked, struct {
+				Suggestion
+				Rank float64
+			}{Suggestion{Type: scope.Type, Title: r.Title, URL: scope.URL(r.ID, r.Slug)}, r.Rank})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Rank > ranked[j].Rank })
+	for i := 0; i < len(ranked) && i < limit; i++ {
+		suggestions = append(suggestions, ranked[i].Suggestion)
+	}
+
+	// Suggestions change rarely; let browsers and CDNs absorb keystroke bursts
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    suggestions,
+	})
+}
+
+// searchQuery reads and validates the q param
+func searchQuery(c *gin.Context) (string, bool) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "q is required",
+		})
+		return "", false
+	}
+	if utf8.RuneCountInString(q) > maxQueryLength {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "q must be at most " + strconv.Itoa(maxQueryLength) + " characters",
+		})
+		return "", false
+	}
+	return q, true
+}
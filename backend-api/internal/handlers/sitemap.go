@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/sitemap"
+	"msc-backend-api/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// sitemapMaxAge is how long crawlers and proxies may reuse a sitemap without revalidating
+const sitemapMaxAge = time.Hour
+
+type SitemapHandler struct {
+	generator  *sitemap.Generator
+	siteURL    string
+	production bool
+}
+
+func NewSitemapHandler(db *gorm.DB, cfg *config.Config) *SitemapHandler {
+	return &SitemapHandler{
+		generator:  sitemap.New(db, cfg.FrontendURL),
+		siteURL:    strings.TrimSuffix(cfg.FrontendURL, "/"),
+		production: cfg.Environment == "production",
+	}
+}
+
+// @Summary Sitemap index
+// @Description Sitemap index listing the sitemaps of programs, blog posts, mentors and alumni
+// @Tags seo
+// @Produce xml
+// @Success 200 {string} string "Sitemap index"
+// @Success 304 {string} string "Not modified"
+// @Router /sitemap.xml [get]
+func (h *SitemapHandler) SitemapIndex(c *gin.Context) {
+	body, lastModified, err := h.generator.Index(h.siteURL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to build sitemap",
+		})
+		return
+	}
+	serveCacheable(c, "application/xml; charset=utf-8", body, lastModified, sitemapMaxAge)
+}
+
+// @Summary Sitemap
+// @Description One sitemap file of a content type, e.g. programs-1.xml; each file holds up to 50,000 URLs
+// @Tags seo
+// @Produce xml
+// @Param file path string true "Sitemap file name, <type>-<page>.xml"
+// @Success 200 {string} string "Sitemap"
+// @Success 304 {string} string "Not modified"
+// @Failure 404 {object} models.APIResponse
+// @Router /sitemaps/{file} [get]
+func (h *SitemapHandler) Sitemap(c *gin.Context) {
+	name, page, ok := parseSitemapFile(c.Param("file"))
+	if !ok {
+		respondSitemapNotFound(c)
+		return
+	}
+
+	body, lastModified, err := h.generator.Sitemap(name, page)
+	if err != nil {
+		if errors.Is(err, sitemap.ErrNotFound) {
+			respondSitemapNotFound(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to build sitemap",
+		})
+		return
+	}
+	serveCacheable(c, "application/xml; charset=utf-8", body, lastModified, sitemapMaxAge)
+}
+
+// @Summary robots.txt
+// @Description Crawler rules pointing at the sitemap index. Outside production every crawler is turned away.
+// @Tags seo
+// @Produce plain
+// @Success 200 {string} string "robots.txt"
+// @Router /robots.txt [get]
+func (h *SitemapHandler) Robots(c *gin.Context) {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if h.production {
+		b.WriteString("Disallow: /api/\n")
+		b.WriteString("Disallow: /docs/\n")
+	} else {
+		b.WriteString("Disallow: /\n")
+	}
+	fmt.Fprintf(&b, "\nSitemap: %s/sitemap.xml\n", h.siteURL)
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(sitemapMaxAge.Seconds())))
+	c.String(http.StatusOK, b.String())
+}
+
+// parseSitemapFile splits "programs-2.xml" into its source name and page
+func parseSitemapFile(file string) (string, int, bool) {
+	base, ok := strings.CutSuffix(file, ".xml")
+	if !ok {
+		return "", 0, false
+	}
+	dash := strings.LastIndex(base, "-")
+	if dash < 1 {
+		return "", 0, false
+	}
+	page, err := strconv.Atoi(base[dash+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return base[:dash], page, true
+}
+
+func respondSitemapNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: "Sitemap not found",
+	})
+}
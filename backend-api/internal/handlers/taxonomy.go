@@ -0,0 +1,566 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"msc-backend-api/internal/blog"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/pkg/slug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TaxonomyHandler struct {
+	db *gorm.DB
+}
+
+func NewTaxonomyHandler(db *gorm.DB) *TaxonomyHandler {
+	return &TaxonomyHandler{db: db}
+}
+
+// @Summary Get categories
+// @Description Get all categories as a tree, or as a flat list with ?flat=true
+// @Tags taxonomy
+// @Produce json
+// @Param flat query bool false "Return a flat list"
+// @Success 200 {object} models.APIResponse{data=[]models.Category}
+// @Router /api/categories [get]
+func (h *TaxonomyHandler) GetCategories(c *gin.Context) {
+	var categories []models.Category
+	if err := h.db.Order("position, name").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch categories",
+		})
+		return
+	}
+
+	data := categories
+	if c.Query("flat") != "true" {
+		data = taxonomy.Tree(categories)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// @Summary Create category
+// @Description Create a category, optionally under a parent
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category body models.CategoryRequest true "Category data"
+// @Success 201 {object} models.APIResponse{data=models.Category}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /categories [post]
+func (h *TaxonomyHandler) CreateCategory(c *gin.Context) {
+	var req models.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	category := models.Category{
+		ID:          uuid.New(),
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		ParentID:    req.ParentID,
+		Position:    req.Position,
+	}
+	if !h.checkCategory(c, &category, req.Slug) {
+		return
+	}
+
+	if err := h.db.Create(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to create category",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+// @Summary Update category
+// @Description Update a category; moving it under one of its own descendants is rejected
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Param category body models.CategoryRequest true "Category data"
+// @Success 200 {object} models.APIResponse{data=models.Category}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /categories/{id} [put]
+func (h *TaxonomyHandler) UpdateCategory(c *gin.Context) {
+	var category models.Category
+	if err := h.db.Where("id = ?", c.Param("id")).First(&category).Error; err != nil {
+		respondTaxonomyNotFound(c, "Category not found")
+		return
+	}
+
+	var req models.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	category.Name = strings.TrimSpace(req.Name)
+	category.Description = req.Description
+	category.ParentID = req.ParentID
+	category.Position = req.Position
+	if !h.checkCategory(c, &category, req.Slug) {
+		return
+	}
+
+	if err := h.db.Save(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to update category",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Category updated successfully",
+		Data:    category,
+	})
+}
+
+// @Summary Delete category
+// @Description Delete a category and its content links; child categories move up to its parent
+// @Tags taxonomy
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Category ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /categories/{id} [delete]
+func (h *TaxonomyHandler) DeleteCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondTaxonomyNotFound(c, "Category not found")
+		return
+	}
+
+	if err := taxonomy.DeleteCategory(h.db, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondTaxonomyNotFound(c, "Category not found")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to delete category",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Category deleted successfully",
+	})
+}
+
+// checkCategory fills the slug and validates the parent and slug uniqueness. It writes
+// the error response and returns false when the category cannot be saved.
+func (h *TaxonomyHandler) checkCategory(c *gin.Context, category *models.Category, requestedSlug string) bool {
+	var ok bool
+	if category.Slug, ok = h.resolveSlug(c, "categories", category.ID, requestedSlug, category.Name); !ok {
+		return false
+	}
+
+	if err := taxonomy.CheckParent(h.db, category.ID, category.ParentID); err != nil {
+		if errors.Is(err, taxonomy.ErrCategoryCycle) || errors.Is(err, taxonomy.ErrUnknownCategory) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Invalid parent: " + err.Error(),
+			})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to check parent category",
+		})
+		return false
+	}
+	return true
+}
+
+// @Summary Get tags
+// @Description Get tags ordered by name, optionally filtered by a name prefix
+// @Tags taxonomy
+// @Produce json
+// @Param q query string false "Name prefix"
+// @Success 200 {object} models.APIResponse{data=[]models.Tag}
+// @Router /api/tags [get]
+func (h *TaxonomyHandler) GetTags(c *gin.Context) {
+	query := h.db.Model(&models.Tag{})
+	if q := slug.Make(c.Query("q")); q != "" {
+		query = query.Where("slug LIKE ?", q+"%")
+	}
+
+	var tags []models.Tag
+	if err := query.Order("name").Limit(500).Find(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch tags",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    tags,
+	})
+}
+
+// @Summary Create tag
+// @Description Create a tag. Tags are also created when content is tagged with a new name.
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tag body models.TagRequest true "Tag data"
+// @Success 201 {object} models.APIResponse{data=models.Tag}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /tags [post]
+func (h *TaxonomyHandler) CreateTag(c *gin.Context) {
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	tag := models.Tag{ID: uuid.New(), Name: strings.TrimSpace(req.Name)}
+	if !h.checkTag(c, &tag, req.Slug) {
+		return
+	}
+
+	if err := h.db.Create(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to create tag",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Tag created successfully",
+		Data:    tag,
+	})
+}
+
+// @Summary Update tag
+// @Description Rename a tag
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Param tag body models.TagRequest true "Tag data"
+// @Success 200 {object} models.APIResponse{data=models.Tag}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse
+// @Router /tags/{id} [put]
+func (h *TaxonomyHandler) UpdateTag(c *gin.Context) {
+	var tag models.Tag
+	if err := h.db.Where("id = ?", c.Param("id")).First(&tag).Error; err != nil {
+		respondTaxonomyNotFound(c, "Tag not found")
+		return
+	}
+
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	tag.Name = strings.TrimSpace(req.Name)
+	if !h.checkTag(c, &tag, req.Slug) {
+		return
+	}
+
+	if err := h.db.Save(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to update tag",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Tag updated successfully",
+		Data:    tag,
+	})
+}
+
+// @Summary Delete tag
+// @Description Delete a tag and remove it from all content
+// @Tags taxonomy
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /tags/{id} [delete]
+func (h *TaxonomyHandler) DeleteTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondTaxonomyNotFound(c, "Tag not found")
+		return
+	}
+
+	if err := taxonomy.DeleteTag(h.db, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondTaxonomyNotFound(c, "Tag not found")
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to delete tag",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Tag deleted successfully",
+	})
+}
+
+func (h *TaxonomyHandler) checkTag(c *gin.Context, tag *models.Tag, requestedSlug string) bool {
+	var ok bool
+	tag.Slug, ok = h.resolveSlug(c, "tags", tag.ID, requestedSlug, tag.Name)
+	return ok
+}
+
+// resolveSlug picks the slug of a category or tag. A slug derived from the name gets a
+// numeric suffix when it is taken; an explicitly requested one is refused with a free
+// alternative instead.
+func (h *TaxonomyHandler) resolveSlug(c *gin.Context, table string, id uuid.UUID, requested, name string) (string, bool) {
+	base := slugBase(requested, name)
+	if base == "" {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Name or slug must contain letters or digits",
+		})
+		return "", false
+	}
+
+	free, err := slug.Unique(base, slug.InTable(h.db, table, id.String()))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to check slug",
+		})
+		return "", false
+	}
+	if free != base && slug.Make(requested) != "" {
+		c.JSON(http.StatusConflict, models.APIResponse{
+			Success: false,
+			Message: "Slug already exists",
+			Data:    gin.H{"suggested_slug": free},
+		})
+		return "", false
+	}
+	return free, true
+}
+
+// @Summary Get content taxonomy
+// @Description Get the categories and tags of a content item (courses, posts, programs, projects, allblogposts)
+// @Tags taxonomy
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param id path string true "Item ID"
+// @Success 200 {object} models.APIResponse{data=models.Taxonomy}
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /taxonomy/{type}/{id} [get]
+func (h *TaxonomyHandler) GetContentTaxonomy(c *gin.Context) {
+	t, id, ok := lookupTaxonomyTarget(c)
+	if !ok {
+		return
+	}
+
+	taxonomies, err := taxonomy.Load(h.db, t, []string{id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch taxonomy",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    taxonomies[id],
+	})
+}
+
+// @Summary Set content taxonomy
+// @Description Replace the categories and tags of a content item. Unknown tag names are created; the first category is the primary one.
+// @Tags taxonomy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param id path string true "Item ID"
+// @Param taxonomy body models.SetTaxonomyRequest true "Category IDs and tag names"
+// @Success 200 {object} models.APIResponse{data=models.Taxonomy}
+// @Failure 400 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /taxonomy/{type}/{id} [put]
+func (h *TaxonomyHandler) SetContentTaxonomy(c *gin.Context) {
+	t, id, ok := lookupTaxonomyTarget(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetTaxonomyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	if err := taxonomy.Set(h.db, t, id, req); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			respondTaxonomyNotFound(c, "Item not found")
+		case errors.Is(err, taxonomy.ErrUnknownCategory):
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Unknown category",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Failed to update taxonomy",
+			})
+		}
+		return
+	}
+
+	// The public blog mirrors the categories and tags of published posts
+	if t.EntityType == models.EntityTypePost {
+		if err := blog.SyncPost(h.db, uuid.MustParse(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Failed to update taxonomy",
+			})
+			return
+		}
+	}
+
+	taxonomies, err := taxonomy.Load(h.db, t, []string{id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch taxonomy",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Taxonomy updated successfully",
+		Data:    taxonomies[id],
+	})
+}
+
+func lookupTaxonomyTarget(c *gin.Context) (taxonomy.Target, string, bool) {
+	t, err := taxonomy.Lookup(c.Param("type"))
+	if err != nil {
+		respondTaxonomyNotFound(c, "Unknown content type")
+		return taxonomy.Target{}, "", false
+	}
+	id := c.Param("id")
+	if !t.ValidID(id) {
+		respondTaxonomyNotFound(c, "Item not found")
+		return taxonomy.Target{}, "", false
+	}
+	return t, id, true
+}
+
+func respondTaxonomyNotFound(c *gin.Context, message string) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: message,
+	})
+}
+
+// filterTaxonomy applies the ?category= (slug or name, includes subcategories) and
+// ?tag= (slug) filters of list endpoints
+func filterTaxonomy(c *gin.Context, query *gorm.DB, t taxonomy.Target) *gorm.DB {
+	return taxonomy.Filter(query, t, c.Query("category"), c.Query("tag"))
+}
+
+// listFacets counts a filtered list per category and tag. A failure only costs the
+// facets, not the list.
+func listFacets(db *gorm.DB, query *gorm.DB, t taxonomy.Target) *models.Facets {
+	facets, err := taxonomy.Facets(db, query, t)
+	if err != nil {
+		log.Printf("Cannot count facets for %s: %v", t.Table, err)
+		return nil
+	}
+	return facets
+}
+
+// loadTaxonomy returns the categories and tags of a page of items keyed by ID
+func loadTaxonomy(db *gorm.DB, t taxonomy.Target, ids []string) map[string]models.Taxonomy {
+	taxonomies, err := taxonomy.Load(db, t, ids)
+	if err != nil {
+		log.Printf("Cannot load taxonomy for %s: %v", t.Table, err)
+	}
+	return taxonomies
+}
+
+// itemTaxonomy returns the categories and tags of one item for detail endpoints
+func itemTaxonomy(db *gorm.DB, t taxonomy.Target, id string) ([]models.Category, []models.Tag) {
+	tax := loadTaxonomy(db, t, []string{id})[id]
+	return tax.Categories, tax.Tags
+}
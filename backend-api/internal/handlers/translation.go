@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"msc-backend-api/internal/i18n"
+	"msc-backend-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TranslationHandler struct {
+	db *gorm.DB
+}
+
+func NewTranslationHandler(db *gorm.DB) *TranslationHandler {
+	return &TranslationHandler{db: db}
+}
+
+// contentLocale returns the locale to serve content in. Public endpoints follow ?lang=
+// and then Accept-Language; /api/v1 endpoints only translate on an explicit ?lang= so
+// editors always load the Vietnamese source for editing.
+func contentLocale(c *gin.Context) string {
+	if strings.HasPrefix(c.FullPath(), "/api/v1") {
+		return i18n.Negotiate(c.Query("lang"), "")
+	}
+	c.Header("Vary", "Accept-Language")
+	return i18n.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+}
+
+// loadTranslations returns the translations of items into the request's locale by ID
+func loadTranslations(c *gin.Context, db *gorm.DB, t i18n.Type, ids []string) map[string]i18n.Values {
+	locale := contentLocale(c)
+	c.Header("Content-Language", locale)
+	translations, err := i18n.Load(db, t, locale, ids)
+	if err != nil {
+		log.Printf("Cannot load %s translations for %s: %v", locale, t.Table, err)
+	}
+	return translations
+}
+
+// itemTranslations returns the translation of one item for detail endpoints
+func itemTranslations(c *gin.Context, db *gorm.DB, t i18n.Type, id string) i18n.Values {
+	return loadTranslations(c, db, t, []string{id})[id]
+}
+
+// @Summary Get translations
+// @Description Get the Vietnamese source and every translation of a content item (programs, projects, allblogposts, mentors, courses)
+// @Tags translations
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param id path string true "Item ID"
+// @Success 200 {object} models.APIResponse{data=models.ItemTranslations}
+// @Failure 404 {object} models.APIResponse
+// @Router /translations/{type}/{id} [get]
+func (h *TranslationHandler) GetTranslations(c *gin.Context) {
+	t, id, ok := h.translatableItem(c)
+	if !ok {
+		return
+	}
+
+	source, err := i18n.Source(h.db, t, id)
+	if err != nil {
+		respondTranslationLookupError(c, err)
+		return
+	}
+	translations, err := i18n.All(h.db, t, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch translations",
+		})
+		return
+	}
+
+	locales := map[string]map[string]string{}
+	for _, locale := range i18n.Locales[1:] {
+		locales[locale] = map[string]string{}
+		for field, value := range translations[locale] {
+			locales[locale][field] = value
+		}
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.ItemTranslations{
+			EntityType: t.EntityType,
+			EntityID:   id,
+			Fields:     t.Fields,
+			Source:     source,
+			Locales:    locales,
+		},
+	})
+}
+
+// @Summary Set translation
+// @Description Set the translation of a content item into a locale. Empty values remove a field's translation; fields left out are kept.
+// @Tags translations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param id path string true "Item ID"
+// @Param locale path string true "Locale, e.g. en"
+// @Param translation body models.TranslationsRequest true "Translated fields"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /translations/{type}/{id}/{locale} [put]
+func (h *TranslationHandler) SetTranslations(c *gin.Context) {
+	t, id, ok := h.translatableItem(c)
+	if !ok {
+		return
+	}
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	var req models.TranslationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Invalid request data",
+		})
+		return
+	}
+
+	if _, err := i18n.Source(h.db, t, id); err != nil {
+		respondTranslationLookupError(c, err)
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		return i18n.Set(tx, t, id, locale, req.Fields, currentUserID(c))
+	}); err != nil {
+		if errors.Is(err, i18n.ErrUnknownField) {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Translatable fields are: " + strings.Join(t.Fields, ", "),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to save translation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Translation saved successfully",
+	})
+}
+
+// @Summary Delete translation
+// @Description Remove the translation of a content item into a locale
+// @Tags translations
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param id path string true "Item ID"
+// @Param locale path string true "Locale, e.g. en"
+// @Success 200 {object} models.APIResponse
+// @Failure 400 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /translations/{type}/{id}/{locale} [delete]
+func (h *TranslationHandler) DeleteTranslations(c *gin.Context) {
+	t, id, ok := h.translatableItem(c)
+	if !ok {
+		return
+	}
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+
+	if err := i18n.Remove(h.db, t, id, locale); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to delete translation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Translation deleted successfully",
+	})
+}
+
+// @Summary Missing translations
+// @Description Report the content items whose translation into a locale lacks fields that have Vietnamese text
+// @Tags translations
+// @Produce json
+// @Security BearerAuth
+// @Param locale query string false "Locale" default(en)
+// @Param type query string false "Only this content type"
+// @Success 200 {object} models.APIResponse{data=[]models.TranslationReport}
+// @Failure 400 {object} models.APIResponse
+// @Router /translations/missing [get]
+func (h *TranslationHandler) GetMissingTranslations(c *gin.Context) {
+	locale := c.DefaultQuery("locale", models.LocaleEN)
+	if locale == i18n.Default || !i18n.Supported(locale) {
+		respondInvalidLocale(c)
+		return
+	}
+
+	types := []i18n.Type{i18n.Programs, i18n.Projects, i18n.AllBlogPosts, i18n.Mentors, i18n.Courses}
+	if name := c.Query("type"); name != "" {
+		t, err := i18n.Lookup(name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Success: false,
+				Message: "Unknown content type",
+			})
+			return
+		}
+		types = []i18n.Type{t}
+	}
+
+	reports := make([]models.TranslationReport, 0, len(types))
+	for _, t := range types {
+		report, err := i18n.Missing(h.db, t, locale)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Success: false,
+				Message: "Failed to build translation report",
+			})
+			return
+		}
+		reports = append(reports, report)
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    reports,
+	})
+}
+
+func (h *TranslationHandler) translatableItem(c *gin.Context) (i18n.Type, string, bool) {
+	t, err := i18n.Lookup(c.Param("type"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Unknown content type",
+		})
+		return i18n.Type{}, "", false
+	}
+	id := c.Param("id")
+	if !t.ValidID(id) {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Item not found",
+		})
+		return i18n.Type{}, "", false
+	}
+	return t, id, true
+}
+
+// translationLocale returns the locale path parameter of the editor endpoints; the
+// default locale is the source text and cannot be translated
+func translationLocale(c *gin.Context) (string, bool) {
+	locale := c.Param("locale")
+	if locale == i18n.Default || !i18n.Supported(locale) {
+		respondInvalidLocale(c)
+		return "", false
+	}
+	return locale, true
+}
+
+func respondInvalidLocale(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, models.APIResponse{
+		Success: false,
+		Message: "Locale must be one of: " + strings.Join(i18n.Locales[1:], ", "),
+	})
+}
+
+func respondTranslationLookupError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Item not found",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.APIResponse{
+		Success: false,
+		Message: "Failed to fetch item",
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/trash"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TrashHandler struct {
+	db *gorm.DB
+}
+
+func NewTrashHandler(db *gorm.DB) *TrashHandler {
+	return &TrashHandler{db: db}
+}
+
+// @Summary List trash
+// @Description List soft-deleted items of a content type (courses, posts, mentors, projects, programs, allblogposts)
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.PaginatedResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /trash/{type} [get]
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	t, ok := lookupTrashType(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	items, total, err := trash.List(h.db, t, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch trash",
+		})
+		return
+	}
+
+	totalPages := (int(total) + limit - 1) / limit
+
+	c.JSON(http.StatusOK, models.PaginatedResponse{
+		Data:       items,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	})
+}
+
+// @Summary Restore from trash
+// @Description Restore a soft-deleted item
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param id path string true "Item ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Failure 409 {object} models.APIResponse "Blog entry of a post; restore or republish the post"
+// @Router /trash/{type}/{id}/restore [post]
+func (h *TrashHandler) RestoreFromTrash(c *gin.Context) {
+	t, ok := lookupTrashType(c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("id")
+	if !t.ValidID(id) {
+		respondNotInTrash(c)
+		return
+	}
+
+	if err := trash.Restore(h.db, t, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondNotInTrash(c)
+			return
+		}
+		if errors.Is(err, trash.ErrPostEntry) {
+			c.JSON(http.StatusConflict, models.APIResponse{
+				Success: false,
+				Message: "This blog entry is published from a post. Restore or republish the post instead",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to restore item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Item restored successfully",
+	})
+}
+
+// @Summary Purge from trash
+// @Description Permanently delete a soft-deleted item (admin only)
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Content type"
+// @Param id path string true "Item ID"
+// @Success 200 {object} models.APIResponse
+// @Failure 401 {object} models.APIResponse
+// @Failure 403 {object} models.APIResponse
+// @Failure 404 {object} models.APIResponse
+// @Router /trash/{type}/{id} [delete]
+func (h *TrashHandler) PurgeFromTrash(c *gin.Context) {
+	t, ok := lookupTrashType(c)
+	if !ok {
+		return
+	}
+
+	id := c.Param("id")
+	if !t.ValidID(id) {
+		respondNotInTrash(c)
+		return
+	}
+
+	if err := trash.Purge(h.db, t, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondNotInTrash(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to purge item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Item permanently deleted",
+	})
+}
+
+func lookupTrashType(c *gin.Context) (trash.Type, bool) {
+	t, err := trash.Lookup(c.Param("type"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Success: false,
+			Message: "Unknown content type",
+		})
+		return trash.Type{}, false
+	}
+	return t, true
+}
+
+func respondNotInTrash(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIResponse{
+		Success: false,
+		Message: "Item not found in trash",
+	})
+}
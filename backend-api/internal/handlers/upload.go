@@ -3,23 +3,21 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"path/filepath"
-	"strings"
-	"time"
 
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/uploads"
 	"msc-backend-api/pkg/config"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type UploadHandler struct {
 	config *config.Config
+	store  *uploads.Store
 }
 
-func NewUploadHandler(cfg *config.Config) *UploadHandler {
-	return &UploadHandler{config: cfg}
+func NewUploadHandler(cfg *config.Config, store *uploads.Store) *UploadHandler {
+	return &UploadHandler{config: cfg, store: store}
 }
 
 // @Summary Upload file
@@ -65,7 +63,7 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 	}
 
 	// Validate file type
-	if !isValidFileType(fileType, header.Filename) {
+	if !uploads.ValidType(fileType, header.Filename) {
 		c.JSON(http.StatusBadRequest, models.APIResponse{
 			Success: false,
 			Message: "Invalid file type",
@@ -74,7 +72,7 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 	}
 
 	// Validate file size based on type
-	maxSize := getMaxFileSize(fileType)
+	maxSize := uploads.MaxSize(fileType)
 	if header.Size > maxSize {
 		c.JSON(http.StatusRequestEntityTooLarge, models.APIResponse{
 			Success: false,
@@ -83,13 +81,9 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 		return
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	filename := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
-
-	// For now, we'll implement a simple local file storage
+	// Files are stored locally and served under /uploads
 	// In production, this should be replaced with AWS S3, Cloudinary, etc.
-	uploadURL, err := h.saveFileLocally(filename, fileType)
+	uploadURL, filename, err := h.store.Save(file, fileType, header.Filename)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Success: false,
@@ -109,53 +103,6 @@ func (h *UploadHandler) UploadFile(c *gin.Context) {
 	})
 }
 
-func isValidFileType(fileType, filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-
-	switch fileType {
-	case "image":
-		return contains([]string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg"}, ext)
-	case "video":
-		return contains([]string{".mp4", ".avi", ".mov", ".wmv", ".flv", ".webm"}, ext)
-	case "document":
-		return contains([]string{".pdf", ".doc", ".docx", ".txt", ".rtf"}, ext)
-	default:
-		return false
-	}
-}
-
-func getMaxFileSize(fileType string) int64 {
-	switch fileType {
-	case "image":
-		return 5 * 1024 * 1024 // 5MB
-	case "video":
-		return 100 * 1024 * 1024 // 100MB
-	case "document":
-		return 10 * 1024 * 1024 // 10MB
-	default:
-		return 1 * 1024 * 1024 // 1MB
-	}
-}
-
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
-
-func (h *UploadHandler) saveFileLocally(filename, fileType string) (string, error) {
-	// This is a simple implementation for development
-	// In production, implement proper cloud storage (AWS S3, Cloudinary, etc.)
-
-	// For now, return a placeholder URL
-	// The actual implementation would save the file to cloud storage and return the URL
-	baseURL := "https://api.msc.edu.vn/uploads"
-	return fmt.Sprintf("%s/%s/%s", baseURL, fileType, filename), nil
-}
-
 // TODO: Implement actual cloud storage integration
 // Example implementations:
 
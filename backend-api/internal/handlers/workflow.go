@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"msc-backend-api/internal/blog"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/workflow"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var errStatusConflict = errors.New("status changed concurrently")
+
+// changeStatus runs a workflow action against a post or course and records it in the
+// transition history. model must be a pointer to the loaded entity. It writes the error
+// response itself and returns the new status on success.
+func changeStatus(c *gin.Context, db *gorm.DB, model interface{}, entityType string, entityID uuid.UUID, from, action, reason string) (string, bool) {
+	to, err := workflow.Apply(from, action, currentUserRoles(c), reason)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, workflow.ErrForbidden) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, models.APIResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return "", false
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Guard on the old status so two reviewers can't act on the same item at once
+		result := tx.Model(model).Where("status = ?", from).Update("status", to)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errStatusConflict
+		}
+
+		if err := tx.Create(&models.StatusTransition{
+			EntityType: entityType,
+			EntityID:   entityID,
+			Action:     action,
+			FromStatus: from,
+			ToStatus:   to,
+			ActorID:    currentUserID(c),
+			Reason:     reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		if entityType == models.EntityTypePost {
+			return blog.SyncPost(tx, entityID)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errStatusConflict) {
+			c.JSON(http.StatusConflict, models.APIResponse{
+				Success: false,
+				Message: "Status was changed by someone else, reload and try again",
+			})
+			return "", false
+		}
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to update status",
+		})
+		return "", false
+	}
+
+	return to, true
+}
+
+// resubmitEdited moves content the current user edited to the status workflow.AfterEdit
+// gives it, recording the move in the transition history. It must run in the
+// transaction that saves the edit, before the save, and updates *status in place.
+func resubmitEdited(c *gin.Context, tx *gorm.DB, entityType string, entityID uuid.UUID, status *string) error {
+	from := *status
+	to := workflow.AfterEdit(from, currentUserRoles(c))
+	if to == from {
+		return nil
+	}
+	*status = to
+	return tx.Create(&models.StatusTransition{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     workflow.ActionEdit,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    currentUserID(c),
+		Reason:     "Edited after review",
+	}).Error
+}
+
+// respondStatusHistory writes the transition history of an entity together with the
+// actions the current user may take next
+func respondStatusHistory(c *gin.Context, db *gorm.DB, entityType string, entityID uuid.UUID, status string) {
+	var history []models.StatusTransition
+	if err := db.Preload("Actor").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at DESC").
+		Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Success: false,
+			Message: "Failed to fetch status history",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: gin.H{
+			"status":            status,
+			"available_actions": workflow.Available(status, currentUserRoles(c)),
+			"history":           history,
+		},
+	})
+}
+
+// checkInitialStatus resolves the status of new content and rejects attempts to skip review
+func checkInitialStatus(c *gin.Context, requested string) (string, bool) {
+	status, err := workflow.InitialStatus(requested, currentUserRoles(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "New content must start as draft or pending_review",
+		})
+		return "", false
+	}
+	return status, true
+}
+
+// checkUnchangedStatus rejects updates that try to change the status outside the workflow
+func checkUnchangedStatus(c *gin.Context, current, requested string) bool {
+	if requested != "" && requested != current {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Status can only be changed through workflow actions",
+		})
+		return false
+	}
+	return true
+}
+
+// checkScheduleWindow validates the publish_at/unpublish_at pair of a create or update request
+func checkScheduleWindow(c *gin.Context, status string, publishAt, unpublishAt *time.Time) bool {
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "unpublish_at must be after publish_at",
+		})
+		return false
+	}
+	if status == workflow.StatusScheduled && publishAt == nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Scheduled content needs a publish_at; unschedule it before clearing it",
+		})
+		return false
+	}
+	return true
+}
+
+// checkSchedulable makes sure content has a future publish_at before it is scheduled
+func checkSchedulable(c *gin.Context, action string, publishAt *time.Time) bool {
+	if action == workflow.ActionSchedule && (publishAt == nil || !publishAt.After(time.Now())) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Success: false,
+			Message: "Set a future publish_at before scheduling",
+		})
+		return false
+	}
+	return true
+}
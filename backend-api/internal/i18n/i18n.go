@@ -0,0 +1,325 @@
+// Package i18n serves content in the reader's language. Content is written in
+// Vietnamese; translations into other locales are stored per item and field, and any
+// field without a translation falls back to the Vietnamese text.
+package i18n
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"msc-backend-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Default is the locale content is written in
+const Default = models.LocaleVI
+
+// Locales are the supported locales, the default first
+var Locales = []string{models.LocaleVI, models.LocaleEN}
+
+var (
+	// ErrUnknownType is returned for content types that cannot be translated
+	ErrUnknownType = errors.New("unknown content type")
+	// ErrUnknownField is returned for fields that are not translatable
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// Type is a content table with translatable fields
+type Type struct {
+	Name       string
+	Table      string
+	EntityType string
+	UUIDKey    bool
+	Label      string   // column that names an item in reports
+	Fields     []string // translatable fields, which are also the column names
+}
+
+var (
+	Programs     = newType("programs", "programs", models.EntityTypeProgram, true, "title", &models.Program{})
+	Projects     = newType("projects", "projects", models.EntityTypeProject, true, "title", &models.Project{})
+	AllBlogPosts = newType("allblogposts", "allblogposts", models.EntityTypeBlogPost, false, "title", &models.AllBlogPost{})
+	Mentors      = newType("mentors", "mentors", models.EntityTypeMentor, true, "name", &models.Mentor{})
+	Courses      = newType("courses", "courses", models.EntityTypeCourse, true, "title", &models.Course{})
+)
+
+// Types are the translatable content types by their URL name
+var Types = map[string]Type{
+	Programs.Name:     Programs,
+	Projects.Name:     Projects,
+	AllBlogPosts.Name: AllBlogPosts,
+	Mentors.Name:      Mentors,
+	Courses.Name:      Courses,
+}
+
+func newType(name, table, entityType string, uuidKey bool, label string, item models.Translatable) Type {
+	var fields []string
+	for field := range item.TranslatableFields() {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return Type{Name: name, Table: table, EntityType: entityType, UUIDKey: uuidKey, Label: label, Fields: fields}
+}
+
+func Lookup(name string) (Type, error) {
+	t, ok := Types[name]
+	if !ok {
+		return Type{}, ErrUnknownType
+	}
+	return t, nil
+}
+
+// ValidID reports whether id has the key format of the type
+func (t Type) ValidID(id string) bool {
+	if t.UUIDKey {
+		_, err := uuid.Parse(id)
+		return err == nil
+	}
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
+}
+
+func (t Type) hasField(field string) bool {
+	for _, f := range t.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Supported reports whether locale is one of Locales
+func Supported(locale string) bool {
+	for _, l := range Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the locale of a request: lang (the ?lang= parameter) when it is
+// supported, else the best supported language of the Accept-Language header, else
+// Default. Region subtags are ignored, so "en-US" selects "en".
+func Negotiate(lang, acceptLanguage string) string {
+	if locale := baseLanguage(lang); Supported(locale) {
+		return locale
+	}
+
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q, ok := quality(params)
+		if !ok {
+			continue
+		}
+		if locale := baseLanguage(tag); Supported(locale) && q > bestQ {
+			best, bestQ = locale, q
+		}
+	}
+	return best
+}
+
+// quality returns the q-value among the parameters of an Accept-Language entry, 1 when
+// there is none. Malformed or out-of-range values report false.
+func quality(params string) (float64, bool) {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || q < 0 || q > 1 {
+			return 0, false
+		}
+		return q, true
+	}
+	return 1, true
+}
+
+func baseLanguage(tag string) string {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	return base
+}
+
+// Values are the translated fields of one item by field name
+type Values map[string]string
+
+// Load returns the translations of the given items into locale by item ID. Nothing is
+// loaded for the default locale.
+func Load(db *gorm.DB, t Type, locale string, ids []string) (map[string]Values, error) {
+	result := map[string]Values{}
+	if locale == Default || len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []models.Translation
+	if err := db.Where("entity_type = ? AND locale = ? AND entity_id IN ?", t.EntityType, locale, ids).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if result[row.EntityID] == nil {
+			result[row.EntityID] = Values{}
+		}
+		result[row.EntityID][row.Field] = row.Value
+	}
+	return result, nil
+}
+
+// Apply overwrites the fields of item that have a translation
+func Apply(item models.Translatable, values Values) {
+	for field, target := range item.TranslatableFields() {
+		if value := values[field]; value != "" {
+			*target = value
+		}
+	}
+}
+
+// All returns every translation of an item by locale
+func All(db *gorm.DB, t Type, id string) (map[string]Values, error) {
+	var rows []models.Translation
+	if err := db.Where("entity_type = ? AND entity_id = ?", t.EntityType, id).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := map[string]Values{}
+	for _, row := range rows {
+		if result[row.Locale] == nil {
+			result[row.Locale] = Values{}
+		}
+		result[row.Locale][row.Field] = row.Value
+	}
+	return result, nil
+}
+
+// Set stores the translation of an item into locale. Fields with an empty value lose
+// their translation; fields that are not mentioned are kept.
+func Set(tx *gorm.DB, t Type, id, locale string, fields map[string]string, userID uuid.UUID) error {
+	var upserts []models.Translation
+	var cleared []string
+	for field, value := range fields {
+		if !t.hasField(field) {
+			return ErrUnknownField
+		}
+		if strings.TrimSpace(value) == "" {
+			cleared = append(cleared, field)
+			continue
+		}
+		upserts = append(upserts, models.Translation{
+			EntityType: t.EntityType,
+			EntityID:   id,
+			Locale:     locale,
+			Field:      field,
+			Value:      value,
+			UpdatedBy:  &userID,
+		})
+	}
+
+	if len(cleared) > 0 {
+		if err := tx.Where("entity_type = ? AND entity_id = ? AND locale = ? AND field IN ?", t.EntityType, id, locale, cleared).
+			Delete(&models.Translation{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(upserts) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "locale"}, {Name: "field"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+	}).Create(&upserts).Error
+}
+
+// Remove deletes the translation of an item into locale
+func Remove(tx *gorm.DB, t Type, id, locale string) error {
+	return tx.Where("entity_type = ? AND entity_id = ? AND locale = ?", t.EntityType, id, locale).
+		Delete(&models.Translation{}).Error
+}
+
+// Source returns the Vietnamese text of the translatable fields of an item. It returns
+// gorm.ErrRecordNotFound when the item does not exist or is trashed.
+func Source(db *gorm.DB, t Type, id string) (Values, error) {
+	var rows []map[string]interface{}
+	if err := db.Table(t.Table).
+		Select(t.Fields).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Limit(1).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return textValues(rows[0], t.Fields), nil
+}
+
+// Missing reports the items of a type whose translation into locale lacks a field that
+// has Vietnamese text. Trashed items are left out.
+func Missing(db *gorm.DB, t Type, locale string) (models.TranslationReport, error) {
+	report := models.TranslationReport{Type: t.Name, Locale: locale, Missing: []models.MissingTranslation{}}
+
+	var rows []map[string]interface{}
+	if err := db.Table(t.Table).
+		Select(append([]string{"id::text AS id", t.Label + " AS label"}, t.Fields...)).
+		Where("deleted_at IS NULL").
+		Order("label").
+		Find(&rows).Error; err != nil {
+		return report, err
+	}
+
+	var translations []models.Translation
+	if err := db.Where("entity_type = ? AND locale = ?", t.EntityType, locale).Find(&translations).Error; err != nil {
+		return report, err
+	}
+	translated := map[string]bool{}
+	for _, tr := range translations {
+		translated[tr.EntityID+"/"+tr.Field] = true
+	}
+
+	for _, row := range rows {
+		id, _ := row["id"].(string)
+		label, _ := row["label"].(string)
+		source := textValues(row, t.Fields)
+
+		var missing []string
+		for _, field := range t.Fields {
+			if strings.TrimSpace(source[field]) != "" && !translated[id+"/"+field] {
+				missing = append(missing, field)
+			}
+		}
+		report.Total++
+		if len(missing) == 0 {
+			report.Complete++
+			continue
+		}
+		report.Missing = append(report.Missing, models.MissingTranslation{
+			EntityType: t.EntityType,
+			EntityID:   id,
+			Label:      label,
+			Fields:     missing,
+		})
+	}
+	return report, nil
+}
+
+// Unlink removes the translations of items that are deleted for good
+func Unlink(tx *gorm.DB, entityType string, ids []string) error {
+	return tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Delete(&models.Translation{}).Error
+}
+
+func textValues(row map[string]interface{}, fields []string) Values {
+	values := Values{}
+	for _, field := range fields {
+		switch v := row[field].(type) {
+		case string:
+			values[field] = v
+		case []byte:
+			values[field] = string(v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,42 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		want           string
+	}{
+		{"nothing given", "", "", "vi"},
+		{"lang parameter", "en", "", "en"},
+		{"lang parameter wins over header", "vi", "en", "vi"},
+		{"lang parameter with region", "en-GB", "", "en"},
+		{"lang parameter is case-insensitive", "EN", "", "en"},
+		{"unsupported lang falls back to header", "fr", "en", "en"},
+		{"unsupported lang and no header", "fr", "", "vi"},
+		{"header region subtag", "", "en-US", "en"},
+		{"header order without q", "", "en, vi", "en"},
+		{"header q-values", "", "en;q=0.5, vi;q=0.8", "vi"},
+		{"browser style header", "", "vi-VN,vi;q=0.9,en-US;q=0.8,en;q=0.7", "vi"},
+		{"unsupported languages skipped", "", "fr-FR, de;q=0.9, en;q=0.1", "en"},
+		{"only unsupported languages", "", "fr, de", "vi"},
+		{"q=0 means not acceptable", "", "en;q=0", "vi"},
+		{"spaces around parameters", "", "vi ; q=0.2 , en ; q = 0.3", "en"},
+		{"uppercase q", "", "vi;Q=0.2, en;Q=0.3", "en"},
+		{"other parameters before q", "", "en;level=1;q=0.2, vi;q=0.5", "vi"},
+		{"malformed q skipped", "", "en;q=high, vi;q=0.1", "vi"},
+		{"out of range q skipped", "", "en;q=2, vi;q=0.1", "vi"},
+		{"tie keeps the first", "", "en;q=0.5, vi;q=0.5", "en"},
+		{"wildcard", "", "*", "vi"},
+		{"empty entries", "", ",, en;q=0.4,", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Negotiate(tt.lang, tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate(%q, %q) = %q, want %q", tt.lang, tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package mentors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/workflow"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidAssignment is returned for an assignment with an unknown mentor or role
+var ErrInvalidAssignment = errors.New("invalid assignment")
+
+// Assignment is one mentor of a course or program
+type Assignment struct {
+	MentorID uuid.UUID
+	Role     string
+}
+
+// assignable are the tables mentors can be assigned to, by entity type
+var assignable = map[string]string{
+	models.EntityTypeCourse:  "courses",
+	models.EntityTypeProgram: "programs",
+}
+
+// LoadAssigned returns the mentors assigned to a course or program, in order. Deleted
+// mentors are left out, and with publicOnly so are inactive ones.
+func LoadAssigned(db *gorm.DB, entityType string, entityID uuid.UUID, publicOnly bool) ([]models.AssignedMentor, error) {
+	assigned := []models.AssignedMentor{}
+	query := db.Table("mentor_assignments AS a").
+		Select("m.id, m.name, m.slug, m.title, m.avatar_url, a.role").
+		Joins("JOIN mentors m ON m.id = a.mentor_id AND m.deleted_at IS NULL").
+		Where("a.entity_type = ? AND a.entity_id = ?", entityType, entityID)
+	if publicOnly {
+		query = query.Where("m.status = ?", "active")
+	}
+	err := query.Order("a.position").Scan(&assigned).Error
+	return assigned, err
+}
+
+// SetAssignments replaces the mentors of a course or program with the given ones, in
+// order. A mentor listed twice keeps its first place. It returns gorm.ErrRecordNotFound
+// when the item does not exist and ErrInvalidAssignment for an unknown mentor or role.
+func SetAssignments(db *gorm.DB, entityType string, entityID uuid.UUID, assignments []Assignment) error {
+	table, ok := assignable[entityType]
+	if !ok {
+		return fmt.Errorf("%w: mentors cannot be assigned to %s", ErrInvalidAssignment, entityType)
+	}
+
+	var rows []models.MentorAssignment
+	seen := map[uuid.UUID]bool{}
+	for i, a := range assignments {
+		role := strings.TrimSpace(a.Role)
+		if role == "" {
+			role = models.AssignmentRoleAssistant
+		}
+		if role != models.AssignmentRoleLead && role != models.AssignmentRoleAssistant {
+			return fmt.Errorf("%w: entry %d: role must be %s or %s", ErrInvalidAssignment, i+1, models.AssignmentRoleLead, models.AssignmentRoleAssistant)
+		}
+		if seen[a.MentorID] {
+			continue
+		}
+		seen[a.MentorID] = true
+		rows = append(rows, models.MentorAssignment{
+			ID:         uuid.New(),
+			MentorID:   a.MentorID,
+			EntityType: entityType,
+			EntityID:   entityID,
+			Role:       role,
+			Position:   len(rows),
+		})
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Table(table).Where("id = ? AND deleted_at IS NULL", entityID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if len(rows) > 0 {
+			ids := make([]uuid.UUID, 0, len(rows))
+			for _, row := range rows {
+				ids = append(ids, row.MentorID)
+			}
+			var found int64
+			if err := tx.Model(&models.Mentor{}).Where("id IN ?", ids).Count(&found).Error; err != nil {
+				return err
+			}
+			if int(found) != len(ids) {
+				return fmt.Errorf("%w: unknown mentor", ErrInvalidAssignment)
+			}
+		}
+
+		if err := tx.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Delete(&models.MentorAssignment{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// LoadTaught returns the courses and programs a mentor teaches, by title. Deleted items
+// are left out, and with publicOnly so are courses that are not published.
+func LoadTaught(db *gorm.DB, mentorID uuid.UUID, publicOnly bool) (courses, programs []models.TaughtItem, err error) {
+	courses = []models.TaughtItem{}
+	query := db.Table("mentor_assignments AS a").
+		Select("c.id, c.title, c.slug, a.role").
+		Joins("JOIN courses c ON c.id = a.entity_id AND c.deleted_at IS NULL").
+		Where("a.entity_type = ? AND a.mentor_id = ?", models.EntityTypeCourse, mentorID)
+	if publicOnly {
+		query = query.Where("c.status = ?", workflow.StatusPublished)
+	}
+	if err = query.Order("c.title").Scan(&courses).Error; err != nil {
+		return nil, nil, err
+	}
+
+	programs = []models.TaughtItem{}
+	err = db.Table("mentor_assignments AS a").
+		Select("p.id, p.title, p.slug, a.role").
+		Joins("JOIN programs p ON p.id = a.entity_id AND p.deleted_at IS NULL").
+		Where("a.entity_type = ? AND a.mentor_id = ?", models.EntityTypeProgram, mentorID).
+		Order("p.title").Scan(&programs).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return courses, programs, nil
+}
+
+// UnlinkAssignments removes the mentor assignments of permanently deleted courses or
+// programs
+func UnlinkAssignments(tx *gorm.DB, entityType string, ids []string) error {
+	return tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Delete(&models.MentorAssignment{}).Error
+}
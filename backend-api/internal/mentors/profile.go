@@ -0,0 +1,173 @@
+// Package mentors manages the structured profile of mentors: education, work history,
+// publications, awards and research areas, each kept in its own table and listed in the
+// order editors give. It also links mentors to the projects they take part in and the
+// courses and programs they teach.
+package mentors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"msc-backend-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidProfile is returned for a profile entry without its required field
+var ErrInvalidProfile = errors.New("invalid profile")
+
+// LoadProfile returns the structured profile of a mentor. Sections without entries are
+// empty lists.
+func LoadProfile(db *gorm.DB, mentorID uuid.UUID) (*models.MentorProfile, error) {
+	profile := &models.MentorProfile{
+		Education:     []models.MentorEducation{},
+		WorkHistory:   []models.MentorWork{},
+		Publications:  []models.MentorPublication{},
+		Awards:        []models.MentorAward{},
+		ResearchAreas: []models.MentorResearchArea{},
+	}
+	for _, section := range []interface{}{&profile.Education, &profile.WorkHistory, &profile.Publications, &profile.Awards, &profile.ResearchAreas} {
+		if err := db.Where("mentor_id = ?", mentorID).Order("position").Find(section).Error; err != nil {
+			return nil, err
+		}
+	}
+	return profile, nil
+}
+
+// SetProfile replaces the sections of a mentor's profile present in req. It returns
+// gorm.ErrRecordNotFound when the mentor does not exist and ErrInvalidProfile when an
+// entry misses its required field.
+func SetProfile(db *gorm.DB, mentorID uuid.UUID, req models.SetMentorProfileRequest) error {
+	if err := normalize(&req, mentorID); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Model(&models.Mentor{}).Where("id = ?", mentorID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if req.Education != nil {
+			if err := replace(tx, mentorID, &models.MentorEducation{}, req.Education, len(*req.Education)); err != nil {
+				return err
+			}
+		}
+		if req.WorkHistory != nil {
+			if err := replace(tx, mentorID, &models.MentorWork{}, req.WorkHistory, len(*req.WorkHistory)); err != nil {
+				return err
+			}
+		}
+		if req.Publications != nil {
+			if err := replace(tx, mentorID, &models.MentorPublication{}, req.Publications, len(*req.Publications)); err != nil {
+				return err
+			}
+		}
+		if req.Awards != nil {
+			if err := replace(tx, mentorID, &models.MentorAward{}, req.Awards, len(*req.Awards)); err != nil {
+				return err
+			}
+		}
+		if req.ResearchAreas != nil {
+			if err := replace(tx, mentorID, &models.MentorResearchArea{}, req.ResearchAreas, len(*req.ResearchAreas)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// replace deletes the entries of one section and inserts the given ones
+func replace(tx *gorm.DB, mentorID uuid.UUID, model interface{}, entries interface{}, count int) error {
+	if err := tx.Where("mentor_id = ?", mentorID).Delete(model).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+	return tx.Create(entries).Error
+}
+
+// normalize trims the entries of req, numbers them in order and checks required fields
+func normalize(req *models.SetMentorProfileRequest, mentorID uuid.UUID) error {
+	if req.Education != nil {
+		for i := range *req.Education {
+			e := &(*req.Education)[i]
+			e.MentorProfileItem = entry(mentorID, i)
+			e.Degree, e.School, e.Year, e.Thesis = trim(e.Degree), trim(e.School), trim(e.Year), trim(e.Thesis)
+			if e.Degree == "" {
+				return missing("education", i, "degree")
+			}
+		}
+	}
+	if req.WorkHistory != nil {
+		for i := range *req.WorkHistory {
+			w := &(*req.WorkHistory)[i]
+			w.MentorProfileItem = entry(mentorID, i)
+			w.Period, w.Role, w.Organization = trim(w.Period), trim(w.Role), trim(w.Organization)
+			if w.Role == "" {
+				return missing("work_history", i, "role")
+			}
+		}
+	}
+	if req.Publications != nil {
+		for i := range *req.Publications {
+			p := &(*req.Publications)[i]
+			p.MentorProfileItem = entry(mentorID, i)
+			p.Title, p.Venue, p.Year, p.URL = trim(p.Title), trim(p.Venue), trim(p.Year), trim(p.URL)
+			if p.Title == "" {
+				return missing("publications", i, "title")
+			}
+		}
+	}
+	if req.Awards != nil {
+		for i := range *req.Awards {
+			a := &(*req.Awards)[i]
+			a.MentorProfileItem = entry(mentorID, i)
+			a.Title, a.Issuer, a.Year = trim(a.Title), trim(a.Issuer), trim(a.Year)
+			if a.Title == "" {
+				return missing("awards", i, "title")
+			}
+		}
+	}
+	if req.ResearchAreas != nil {
+		for i := range *req.ResearchAreas {
+			r := &(*req.ResearchAreas)[i]
+			r.MentorProfileItem = entry(mentorID, i)
+			r.Name = trim(r.Name)
+			if r.Name == "" {
+				return missing("research_areas", i, "name")
+			}
+		}
+	}
+	return nil
+}
+
+// entry returns the stored part of a new profile entry; IDs sent by clients are ignored
+func entry(mentorID uuid.UUID, position int) models.MentorProfileItem {
+	return models.MentorProfileItem{ID: uuid.New(), MentorID: mentorID, Position: position}
+}
+
+func missing(section string, index int, field string) error {
+	return fmt.Errorf("%w: %s entry %d needs a %s", ErrInvalidProfile, section, index+1, field)
+}
+
+func trim(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// Unlink removes the profiles, project links and teaching assignments of permanently
+// deleted mentors
+func Unlink(tx *gorm.DB, ids []string) error {
+	for _, model := range []interface{}{&models.MentorEducation{}, &models.MentorWork{}, &models.MentorPublication{}, &models.MentorAward{}, &models.MentorResearchArea{}, &models.ProjectMentor{}, &models.MentorAssignment{}} {
+		if err := tx.Where("mentor_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package mentors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/slug"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultProjectRole is the role of a project mentor given without one
+const DefaultProjectRole = "mentor"
+
+// ResolveProjectMentors matches the mentors written on a project to mentor records:
+// by id, including mentors in the trash, otherwise by name, ignoring case and accents.
+// Entries that match no mentor are kept as written, without an id; entries with neither
+// a name nor a known id are dropped. Linked entries are filled from the mentor.
+func ResolveProjectMentors(tx *gorm.DB, entries []models.MentorInfo) ([]models.MentorInfo, error) {
+	var byName map[string]models.Mentor
+	resolved := make([]models.MentorInfo, 0, len(entries))
+	linked := map[uuid.UUID]bool{}
+
+	for _, entry := range entries {
+		entry.Name = strings.TrimSpace(entry.Name)
+		entry.Role = strings.TrimSpace(entry.Role)
+
+		var mentor *models.Mentor
+		if entry.ID != nil {
+			var found models.Mentor
+			err := tx.Unscoped().Where("id = ?", *entry.ID).First(&found).Error
+			switch {
+			case err == nil:
+				mentor = &found
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return nil, err
+			}
+			entry.ID = nil
+		}
+		if mentor == nil && entry.Name != "" {
+			if byName == nil {
+				var err error
+				if byName, err = mentorsByName(tx); err != nil {
+					return nil, err
+				}
+			}
+			if found, ok := byName[slug.Make(entry.Name)]; ok {
+				mentor = &found
+			}
+		}
+
+		if mentor == nil {
+			if entry.Name == "" {
+				continue
+			}
+			entry.Slug, entry.Title = "", ""
+			resolved = append(resolved, entry)
+			continue
+		}
+		// A mentor listed twice keeps its first place
+		if linked[mentor.ID] {
+			continue
+		}
+		linked[mentor.ID] = true
+		if entry.Role == "" {
+			entry.Role = DefaultProjectRole
+		}
+		resolved = append(resolved, linkedInfo(*mentor, entry.Role))
+	}
+	return resolved, nil
+}
+
+// LinkProjectMentors replaces the mentor records linked to a project with the linked
+// entries of a resolved list, in order
+func LinkProjectMentors(tx *gorm.DB, projectID uuid.UUID, resolved []models.MentorInfo) error {
+	if err := tx.Where("project_id = ?", projectID).Delete(&models.ProjectMentor{}).Error; err != nil {
+		return err
+	}
+	var links []models.ProjectMentor
+	for _, entry := range resolved {
+		if entry.ID == nil {
+			continue
+		}
+		links = append(links, models.ProjectMentor{
+			ID:        uuid.New(),
+			ProjectID: projectID,
+			MentorID:  *entry.ID,
+			Role:      entry.Role,
+			Position:  len(links),
+		})
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return tx.Create(&links).Error
+}
+
+// SetProjectMentors resolves the mentors of a project, links the mentor records and
+// stores the resolved list as the project's mentors snapshot
+func SetProjectMentors(tx *gorm.DB, project *models.Project, entries []models.MentorInfo) error {
+	resolved, err := ResolveProjectMentors(tx, entries)
+	if err != nil {
+		return err
+	}
+	if err := LinkProjectMentors(tx, project.ID, resolved); err != nil {
+		return err
+	}
+	snapshot, err := json.Marshal(resolved)
+	if err != nil {
+		return err
+	}
+	project.MentorsJSON, project.Mentors = resolved, string(snapshot)
+	return tx.Model(project).UpdateColumn("mentors", project.Mentors).Error
+}
+
+// FillProjectMentors sets the mentors of projects from the live mentor records they
+// link, in order, followed by the entries that match no mentor record. Deleted mentors
+// are left out, and with publicOnly so are inactive ones.
+func FillProjectMentors(db *gorm.DB, projects []models.Project, publicOnly bool) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+
+	var links []models.ProjectMentor
+	if err := db.Where("project_id IN ?", ids).Order("position").Find(&links).Error; err != nil {
+		return err
+	}
+	mentorIDs := make([]uuid.UUID, 0, len(links))
+	for _, link := range links {
+		mentorIDs = append(mentorIDs, link.MentorID)
+	}
+	mentorsByID := map[uuid.UUID]models.Mentor{}
+	if len(mentorIDs) > 0 {
+		var mentors []models.Mentor
+		query := db.Where("id IN ?", mentorIDs)
+		if publicOnly {
+			query = query.Where("status = ?", "active")
+		}
+		if err := query.Find(&mentors).Error; err != nil {
+			return err
+		}
+		for _, mentor := range mentors {
+			mentorsByID[mentor.ID] = mentor
+		}
+	}
+
+	live := map[uuid.UUID][]models.MentorInfo{}
+	for _, link := range links {
+		if mentor, ok := mentorsByID[link.MentorID]; ok {
+			live[link.ProjectID] = append(live[link.ProjectID], linkedInfo(mentor, link.Role))
+		}
+	}
+
+	for i := range projects {
+		list := append([]models.MentorInfo{}, live[projects[i].ID]...)
+		for _, entry := range projects[i].MentorsJSON {
+			if entry.ID == nil {
+				list = append(list, entry)
+			}
+		}
+		projects[i].MentorsJSON = list
+	}
+	return nil
+}
+
+// BackfillProjectMentors links the mentors written on projects to mentor records by
+// name. Projects that already link a mentor are left alone; the others are matched
+// again on every start, so a mentor added later gets linked.
+func BackfillProjectMentors(db *gorm.DB) error {
+	var projects []models.Project
+	if err := db.Where("mentors IS NOT NULL AND mentors::text NOT IN ('null', '[]', '\"\"')").
+		Where("NOT EXISTS (SELECT 1 FROM project_mentors pm WHERE pm.project_id = projects.id)").
+		Find(&projects).Error; err != nil {
+		return err
+	}
+
+	linked := 0
+	for i := range projects {
+		project := &projects[i]
+		if len(project.MentorsJSON) == 0 {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			resolved, err := ResolveProjectMentors(tx, project.MentorsJSON)
+			if err != nil {
+				return err
+			}
+			found := 0
+			for _, entry := range resolved {
+				if entry.ID != nil {
+					found++
+				}
+			}
+			if found == 0 {
+				return nil
+			}
+			linked += found
+			return SetProjectMentors(tx, project, resolved)
+		})
+		if err != nil {
+			return fmt.Errorf("project %s: %w", project.Slug, err)
+		}
+	}
+	if linked > 0 {
+		log.Printf("Linked %d project mentors to mentor records", linked)
+	}
+	return nil
+}
+
+// UnlinkProjects removes the mentor links of permanently deleted projects
+func UnlinkProjects(tx *gorm.DB, projectIDs []string) error {
+	return tx.Where("project_id IN ?", projectIDs).Delete(&models.ProjectMentor{}).Error
+}
+
+// mentorsByName indexes mentors by their name without case and accents. Names shared by
+// several mentors are left out, since they cannot be told apart.
+func mentorsByName(tx *gorm.DB) (map[string]models.Mentor, error) {
+	var mentors []models.Mentor
+	if err := tx.Select("id", "name", "slug", "title", "avatar_url").Find(&mentors).Error; err != nil {
+		return nil, err
+	}
+	byName := map[string]models.Mentor{}
+	shared := map[string]bool{}
+	for _, mentor := range mentors {
+		key := slug.Make(mentor.Name)
+		if key == "" {
+			continue
+		}
+		if _, ok := byName[key]; ok {
+			shared[key] = true
+		}
+		byName[key] = mentor
+	}
+	for key := range shared {
+		delete(byName, key)
+	}
+	return byName, nil
+}
+
+func linkedInfo(mentor models.Mentor, role string) models.MentorInfo {
+	id := mentor.ID
+	return models.MentorInfo{
+		ID:     &id,
+		Name:   mentor.Name,
+		Avatar: mentor.AvatarURL,
+		Slug:   mentor.Slug,
+		Title:  mentor.Title,
+		Role:   role,
+	}
+}
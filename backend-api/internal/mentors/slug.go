@@ -0,0 +1,41 @@
+package mentors
+
+import (
+	"msc-backend-api/pkg/slug"
+
+	"gorm.io/gorm"
+)
+
+// fallbackSlug is the slug base of a mentor whose name has no letters or digits
+const fallbackSlug = "mentor"
+
+// BackfillSlugs gives every mentor without a slug one derived from the name, trashed
+// mentors included. It is idempotent and runs on startup.
+func BackfillSlugs(db *gorm.DB) error {
+	var missing []struct {
+		ID   string
+		Name string
+	}
+	if err := db.Table("mentors").
+		Select("id::text AS id, name").
+		Where("slug IS NULL OR slug = ''").
+		Order("created_at").
+		Scan(&missing).Error; err != nil {
+		return err
+	}
+
+	for _, m := range missing {
+		base := slug.Make(m.Name)
+		if base == "" {
+			base = fallbackSlug
+		}
+		s, err := slug.Unique(base, slug.InTable(db, "mentors", m.ID))
+		if err != nil {
+			return err
+		}
+		if err := db.Table("mentors").Where("id::text = ?", m.ID).Update("slug", s).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Booking statuses
+const (
+	BookingStatusConfirmed = "confirmed"
+	BookingStatusCancelled = "cancelled"
+)
+
+// MentorAvailability is a weekly window in which a mentor offers sessions, e.g. every
+// Tuesday 09:00–12:00 Asia/Ho_Chi_Minh in slots of 60 minutes. Times are wall-clock
+// times in Timezone, so sessions keep their local time across daylight saving changes.
+type MentorAvailability struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MentorID    uuid.UUID `gorm:"type:uuid;not null;index" json:"mentor_id"`
+	Weekday     int       `gorm:"not null" json:"weekday"`                 // 0 = Sunday … 6 = Saturday
+	StartTime   string    `gorm:"not null" json:"start_time"`              // "09:00"
+	EndTime     string    `gorm:"not null" json:"end_time"`                // "12:00"
+	Timezone    string    `gorm:"not null" json:"timezone"`                // IANA name such as "Asia/Ho_Chi_Minh"
+	SlotMinutes int       `gorm:"not null;default:60" json:"slot_minutes"` // length of one session
+	CreatedAt   time.Time `json:"-"`
+	UpdatedAt   time.Time `json:"-"`
+}
+
+func (MentorAvailability) TableName() string {
+	return "mentor_availability"
+}
+
+// SetAvailabilityRequest replaces a mentor's weekly availability; an empty list stops
+// new bookings
+type SetAvailabilityRequest struct {
+	Availability []MentorAvailability `json:"availability" binding:"required"`
+}
+
+// Slot is a bookable session of a mentor
+type Slot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Timezone string    `json:"timezone"` // time zone of the availability the slot comes from
+}
+
+// MentorCalendarFeed holds the secret token of a mentor's calendar feed URL. Replacing
+// the token revokes every earlier subscription URL.
+type MentorCalendarFeed struct {
+	MentorID  uuid.UUID `gorm:"type:uuid;primary_key" json:"-"`
+	Token     string    `gorm:"not null;uniqueIndex" json:"-"`
+	CreatedAt time.Time `json:"-"`
+}
+
+// Booking is a session a user booked with a mentor. Times are stored in UTC; Timezone is
+// the booker's time zone, used when showing the session to them. Sequence grows with
+// every reschedule or cancellation so calendar apps replace the earlier invitation.
+type Booking struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MentorID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_bookings_mentor_time" json:"mentor_id"`
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	StartsAt     time.Time  `gorm:"not null;index:idx_bookings_mentor_time" json:"starts_at"`
+	EndsAt       time.Time  `gorm:"not null" json:"ends_at"`
+	Timezone     string     `gorm:"not null" json:"timezone"`
+	Status       string     `gorm:"not null;default:'confirmed';index" json:"status"`
+	Note         string     `gorm:"type:text" json:"note,omitempty"`
+	CancelReason string     `json:"cancel_reason,omitempty"`
+	CancelledBy  *uuid.UUID `gorm:"type:uuid" json:"cancelled_by,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	Sequence     int        `gorm:"not null;default:0" json:"sequence"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Relationships
+	Mentor *Mentor        `gorm:"foreignKey:MentorID" json:"mentor,omitempty"`
+	User   *CommentAuthor `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+type CreateBookingRequest struct {
+	MentorID uuid.UUID `json:"mentor_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	Timezone string    `json:"timezone,omitempty"` // the booker's time zone; defaults to the mentor's
+	Note     string    `json:"note,omitempty" binding:"max=2000"`
+}
+
+type RescheduleBookingRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+}
+
+type CancelBookingRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Comment moderation statuses
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// Comment is a reader comment on a public blog post. Replies point at their parent;
+// only approved comments are shown on the blog.
+type Comment struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BlogPostID  uint       `gorm:"not null;index:idx_comments_blog_post" json:"blog_post_id"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Content     string     `gorm:"type:text;not null" json:"content"`
+	Status      string     `gorm:"not null;default:'pending';index:idx_comments_blog_post" json:"status"`
+	ModeratedBy *uuid.UUID `gorm:"type:uuid" json:"moderated_by,omitempty"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationships
+	User     *CommentAuthor `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	BlogPost *AllBlogPost   `gorm:"foreignKey:BlogPostID" json:"blog_post,omitempty"`
+
+	// Approved replies, filled when comments are returned as a thread
+	Replies []Comment `gorm:"-" json:"replies,omitempty"`
+}
+
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// CommentAuthor is the public view of the user who wrote a comment
+type CommentAuthor struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func (CommentAuthor) TableName() string {
+	return "users"
+}
+
+type CreateCommentRequest struct {
+	Content  string     `json:"content" binding:"required,max=2000"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// Notification is a message for one user, e.g. a new comment on a post they wrote
+type Notification struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type      string     `gorm:"not null" json:"type"`
+	Title     string     `gorm:"not null" json:"title"`
+	Message   string     `json:"message,omitempty"`
+	Link      string     `json:"link,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// NotificationTypeComment is sent to a post's author when a comment on it is approved
+const NotificationTypeComment = "comment"
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ContentLike records that a visitor likes a content item. Visitor is "user:<id>" for
+// signed-in users and "anon:<fingerprint>" otherwise, so a visitor likes an item once.
+type ContentLike struct {
+	EntityType string    `gorm:"primaryKey" json:"entity_type"`
+	EntityID   string    `gorm:"primaryKey" json:"entity_id"`
+	Visitor    string    `gorm:"primaryKey" json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Engagement is the view and like state of a content item for the current visitor
+type Engagement struct {
+	Views int  `json:"views"`
+	Likes int  `json:"likes"`
+	Liked bool `json:"liked"`
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HidePrivate clears the contact details that only staff may see, for public responses
+func (m *Mentor) HidePrivate() {
+	m.Email = ""
+	m.Phone = ""
+	m.UserID = nil
+}
+
+// MentorProfileItem is the part shared by the entries of a mentor's structured profile.
+// Entries are listed by Position.
+type MentorProfileItem struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MentorID  uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"-"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// MentorEducation is a degree or certification of a mentor
+type MentorEducation struct {
+	MentorProfileItem
+	Degree string `gorm:"not null" json:"degree"`
+	School string `json:"school,omitempty"`
+	Year   string `json:"year,omitempty"` // free text such as "2019" or "2015 - nay"
+	Thesis string `json:"thesis,omitempty"`
+}
+
+func (MentorEducation) TableName() string {
+	return "mentor_education"
+}
+
+// MentorWork is a position in a mentor's work history
+type MentorWork struct {
+	MentorProfileItem
+	Period       string `json:"period,omitempty"` // free text such as "2019–nay"
+	Role         string `gorm:"not null" json:"role"`
+	Organization string `json:"organization,omitempty"`
+}
+
+func (MentorWork) TableName() string {
+	return "mentor_work_history"
+}
+
+// MentorPublication is a paper, book or article by a mentor
+type MentorPublication struct {
+	MentorProfileItem
+	Title string `gorm:"not null" json:"title"`
+	Venue string `json:"venue,omitempty"` // journal, conference or publisher
+	Year  string `json:"year,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+func (MentorPublication) TableName() string {
+	return "mentor_publications"
+}
+
+// MentorAward is an award or recognition received by a mentor
+type MentorAward struct {
+	MentorProfileItem
+	Title  string `gorm:"not null" json:"title"`
+	Issuer string `json:"issuer,omitempty"`
+	Year   string `json:"year,omitempty"`
+}
+
+func (MentorAward) TableName() string {
+	return "mentor_awards"
+}
+
+// MentorResearchArea is a field a mentor researches or teaches
+type MentorResearchArea struct {
+	MentorProfileItem
+	Name string `gorm:"not null" json:"name"`
+}
+
+func (MentorResearchArea) TableName() string {
+	return "mentor_research_areas"
+}
+
+// MentorProfile is the structured profile shown on a mentor's detail page
+type MentorProfile struct {
+	Education     []MentorEducation    `json:"education"`
+	WorkHistory   []MentorWork         `json:"work_history"`
+	Publications  []MentorPublication  `json:"publications"`
+	Awards        []MentorAward        `json:"awards"`
+	ResearchAreas []MentorResearchArea `json:"research_areas"`
+}
+
+// SetMentorProfileRequest replaces sections of a mentor's profile. A section that is
+// left out is kept; an empty list clears it. Entries are stored in the order given.
+type SetMentorProfileRequest struct {
+	Education     *[]MentorEducation    `json:"education,omitempty"`
+	WorkHistory   *[]MentorWork         `json:"work_history,omitempty"`
+	Publications  *[]MentorPublication  `json:"publications,omitempty"`
+	Awards        *[]MentorAward        `json:"awards,omitempty"`
+	ResearchAreas *[]MentorResearchArea `json:"research_areas,omitempty"`
+}
+
+// ProjectMentor links a mentor to a project with the mentor's role in it. Mentors are
+// listed by Position.
+type ProjectMentor struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_project_mentor" json:"project_id"`
+	MentorID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_project_mentor;index" json:"mentor_id"`
+	Role      string    `gorm:"not null;default:'mentor'" json:"role"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Roles of a mentor assigned to a course or program
+const (
+	AssignmentRoleLead      = "lead_instructor"
+	AssignmentRoleAssistant = "assistant"
+)
+
+// MentorAssignment assigns a mentor to a course or program they teach. EntityType is
+// EntityTypeCourse or EntityTypeProgram; the mentors of one item are listed by Position.
+type MentorAssignment struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MentorID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_mentor_assignment;index" json:"mentor_id"`
+	EntityType string    `gorm:"not null;uniqueIndex:idx_mentor_assignment;index:idx_mentor_assignment_entity" json:"entity_type"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_mentor_assignment;index:idx_mentor_assignment_entity" json:"entity_id"`
+	Role       string    `gorm:"not null" json:"role"`
+	Position   int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AssignedMentor is a mentor as listed on a course or program
+type AssignedMentor struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Title     string    `json:"title,omitempty"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
+	Role      string    `json:"role"`
+}
+
+// TaughtItem is a course or program as listed on the mentor who teaches it
+type TaughtItem struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	Slug  string    `json:"slug"`
+	Role  string    `json:"role"`
+}
+
+// SetAssignmentsRequest replaces the mentors of a course or program, in display order
+type SetAssignmentsRequest struct {
+	Mentors []struct {
+		MentorID uuid.UUID `json:"mentor_id" binding:"required"`
+		Role     string    `json:"role,omitempty"` // lead_instructor or assistant (default)
+	} `json:"mentors" binding:"required,dive"`
+}
@@ -11,12 +11,23 @@ import (
 
 // Base model with UUID
 type BaseModel struct {
-	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `gorm:"index" json:"-"`
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// Entity types for tables that reference several kinds of content
+const (
+	EntityTypePost     = "post"
+	EntityTypeCourse   = "course"
+	EntityTypeProgram  = "program"
+	EntityTypeProject  = "project"
+	EntityTypeMentor   = "mentor"
+	EntityTypeMSCer    = "mscer"
+	EntityTypeBlogPost = "allblogpost"
+)
+
 // Role model
 type Role struct {
 	ID   uint   `gorm:"primaryKey" json:"id"`
@@ -26,12 +37,28 @@ type Role struct {
 // Course model
 type Course struct {
 	BaseModel
-	Title        string    `gorm:"not null" json:"title"`
-	Slug         string    `gorm:"unique;not null" json:"slug"`
-	Description  string    `json:"description,omitempty"`
-	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
-	Status       string    `gorm:"default:'pending_review'" json:"status"` // draft, pending_review, published, archived
-	AuthorID     uuid.UUID `gorm:"not null" json:"author_id"`
+	Title        string     `gorm:"not null" json:"title"`
+	Slug         string     `gorm:"unique;not null" json:"slug"`
+	Description  string     `json:"description,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	Status       string     `gorm:"default:'pending_review'" json:"status"` // draft, pending_review, scheduled, published, archived
+	PublishAt    *time.Time `gorm:"index" json:"publish_at,omitempty"`      // when a scheduled item goes live
+	UnpublishAt  *time.Time `gorm:"index" json:"unpublish_at,omitempty"`    // when a published item is unpublished automatically
+	AuthorID     uuid.UUID  `gorm:"not null" json:"author_id"`
+
+	// Average and number of approved reviews, kept up to date by the reviews package
+	RatingAverage float64 `gorm:"<-:false;type:numeric(3,2);not null;default:0" json:"rating_average"`
+	RatingCount   int     `gorm:"<-:false;not null;default:0" json:"rating_count"`
+
+	// Highlighted match, filled only for ?search= results
+	Snippet string `gorm:"-" json:"snippet,omitempty"`
+
+	// Categories and tags, filled by list and detail endpoints
+	Categories []Category `gorm:"-" json:"categories,omitempty"`
+	Tags       []Tag      `gorm:"-" json:"tags,omitempty"`
+
+	// Assigned mentors, filled by the detail endpoint
+	Mentors []AssignedMentor `gorm:"-" json:"mentors,omitempty"`
 
 	// Relationships
 	Author      User         `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
@@ -48,6 +75,10 @@ type Lesson struct {
 	VideoURL string    `json:"video_url,omitempty"`
 	Order    int       `gorm:"not null" json:"order"`
 
+	// Rendered content, filled only when requested with ?render=html|text
+	ContentHTML string `gorm:"-" json:"content_html,omitempty"`
+	ContentText string `gorm:"-" json:"content_text,omitempty"`
+
 	// Relationships
 	Course Course `gorm:"foreignKey:CourseID" json:"course,omitempty"`
 }
@@ -55,13 +86,26 @@ type Lesson struct {
 // Post model
 type Post struct {
 	BaseModel
-	Title        string    `gorm:"not null" json:"title"`
-	Slug         string    `gorm:"unique;not null" json:"slug"`
-	Content      string    `gorm:"type:jsonb" json:"content,omitempty"`
-	Excerpt      string    `json:"excerpt,omitempty"`
-	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
-	Status       string    `gorm:"default:'pending_review'" json:"status"` // draft, pending_review, published, archived
-	AuthorID     uuid.UUID `gorm:"not null" json:"author_id"`
+	Title        string     `gorm:"not null" json:"title"`
+	Slug         string     `gorm:"unique;not null" json:"slug"`
+	Content      string     `gorm:"type:jsonb" json:"content,omitempty"`
+	Excerpt      string     `json:"excerpt,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	Status       string     `gorm:"default:'pending_review'" json:"status"` // draft, pending_review, scheduled, published, archived
+	PublishAt    *time.Time `gorm:"index" json:"publish_at,omitempty"`      // when a scheduled item goes live
+	UnpublishAt  *time.Time `gorm:"index" json:"unpublish_at,omitempty"`    // when a published item is unpublished automatically
+	AuthorID     uuid.UUID  `gorm:"not null" json:"author_id"`
+
+	// Rendered content, filled only when requested with ?render=html|text
+	ContentHTML string `gorm:"-" json:"content_html,omitempty"`
+	ContentText string `gorm:"-" json:"content_text,omitempty"`
+
+	// Highlighted match, filled only for ?search= results
+	Snippet string `gorm:"-" json:"snippet,omitempty"`
+
+	// Categories and tags, filled by list and detail endpoints
+	Categories []Category `gorm:"-" json:"categories,omitempty"`
+	Tags       []Tag      `gorm:"-" json:"tags,omitempty"`
 
 	// Relationships
 	Author User `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
@@ -71,6 +115,7 @@ type Post struct {
 type Mentor struct {
 	BaseModel
 	Name        string   `gorm:"not null" json:"name"`
+	Slug        string   `gorm:"uniqueIndex" json:"slug"`
 	Title       string   `json:"title,omitempty"`
 	Bio         string   `json:"bio,omitempty"`
 	AvatarURL   string   `json:"avatar_url,omitempty"`
@@ -79,6 +124,28 @@ type Mentor struct {
 	LinkedinURL string   `json:"linkedin_url,omitempty"`
 	Specialties []string `gorm:"type:text[]" json:"specialties,omitempty"`
 	Status      string   `gorm:"default:'active'" json:"status"` // active, inactive
+
+	// Account of the mentor, who may then manage their own availability and bookings
+	UserID *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"user_id,omitempty"`
+
+	// Average and number of approved reviews, kept up to date by the reviews package
+	RatingAverage float64 `gorm:"<-:false;type:numeric(3,2);not null;default:0" json:"rating_average"`
+	RatingCount   int     `gorm:"<-:false;not null;default:0" json:"rating_count"`
+
+	// Education, work history, publications, awards and research areas, filled by the
+	// detail endpoint
+	Profile *MentorProfile `gorm:"-" json:"profile,omitempty"`
+
+	// Courses and programs the mentor teaches, filled by the detail endpoint
+	Courses  []TaughtItem `gorm:"-" json:"courses,omitempty"`
+	Programs []TaughtItem `gorm:"-" json:"programs,omitempty"`
+
+	// Highlighted match, filled only for ?search= results
+	Snippet string `gorm:"-" json:"snippet,omitempty"`
+
+	// Categories and tags, filled by list and detail endpoints
+	Categories []Category `gorm:"-" json:"categories,omitempty"`
+	Tags       []Tag      `gorm:"-" json:"tags,omitempty"`
 }
 
 // Enrollment model
@@ -103,14 +170,29 @@ type Project struct {
 	Image       string       `json:"image"`
 	Category    string       `json:"category"`
 	Status      string       `gorm:"default:'active'" json:"status"`
-	Mentors     string       `gorm:"type:jsonb" json:"-"`
+	Mentors     string       `gorm:"type:jsonb" json:"-"` // snapshot of MentorsJSON; mentor records are linked through project_mentors
 	MentorsJSON []MentorInfo `gorm:"-" json:"mentors"`
+	Views       int          `gorm:"default:0" json:"views"`
+	Likes       int          `gorm:"default:0" json:"likes"`
+
+	// Highlighted match, filled only for ?search= results
+	Snippet string `gorm:"-" json:"snippet,omitempty"`
+
+	// Categories and tags, filled by list and detail endpoints
+	Categories []Category `gorm:"-" json:"categories,omitempty"`
+	Tags       []Tag      `gorm:"-" json:"tags,omitempty"`
 }
 
-// MentorInfo represents mentor information in projects
+// MentorInfo represents mentor information in projects. Entries linked to a Mentor
+// carry its ID and are filled from the live mentor; entries without one are kept as
+// written, for people who have no mentor record.
 type MentorInfo struct {
-	Name   string `json:"name"`
-	Avatar string `json:"avatar"`
+	ID     *uuid.UUID `json:"id,omitempty"`
+	Name   string     `json:"name"`
+	Avatar string     `json:"avatar"`
+	Slug   string     `json:"slug,omitempty"`
+	Title  string     `json:"title,omitempty"`
+	Role   string     `json:"role,omitempty"` // role in the project, e.g. "lead" or "advisor"
 }
 
 // BeforeCreate hook for Project
@@ -145,19 +227,32 @@ func (p *Project) BeforeSave(tx *gorm.DB) error {
 
 // AllBlogPost model
 type AllBlogPost struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	Slug         string    `gorm:"unique;not null" json:"slug"`
-	Title        string    `gorm:"not null" json:"title"`
-	Excerpt      string    `json:"excerpt,omitempty"`
-	Image        string    `json:"image,omitempty"`
-	Author       string    `json:"author,omitempty"`
-	AuthorAvatar string    `json:"author_avatar,omitempty"`
-	PublishDate  time.Time `json:"publish_date"`
-	Category     string    `json:"category,omitempty"`
-	DetailsBlog  string    `json:"details_blog,omitempty"`
-	ReadTime     string    `json:"read_time,omitempty"`
-	Views        int       `gorm:"default:0" json:"views"`
-	Likes        int       `gorm:"default:0" json:"likes"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Slug         string         `gorm:"unique;not null" json:"slug"`
+	Title        string         `gorm:"not null" json:"title"`
+	Excerpt      string         `json:"excerpt,omitempty"`
+	Image        string         `json:"image,omitempty"`
+	Author       string         `json:"author,omitempty"`
+	AuthorAvatar string         `json:"author_avatar,omitempty"`
+	PublishDate  time.Time      `json:"publish_date"`
+	Category     string         `json:"category,omitempty"`
+	DetailsBlog  string         `json:"details_blog,omitempty"`
+	ReadTime     string         `json:"read_time,omitempty"`
+	Views        int            `gorm:"default:0" json:"views"`
+	Likes        int            `gorm:"default:0" json:"likes"`
+	UpdatedAt    time.Time      `gorm:"not null;default:now()" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	PostID       *uuid.UUID     `gorm:"type:uuid;uniqueIndex" json:"post_id,omitempty"` // source post; nil for legacy rows not imported yet
+
+	// Highlighted match, filled only for ?search= results
+	Snippet string `gorm:"-" json:"snippet,omitempty"`
+
+	// Categories and tags, filled by list and detail endpoints
+	Categories []Category `gorm:"-" json:"categories,omitempty"`
+	Tags       []Tag      `gorm:"-" json:"tags,omitempty"`
+
+	// Number of approved comments, filled by list and detail endpoints
+	CommentsCount int64 `gorm:"-" json:"comments_count"`
 }
 
 // TableName specifies the table name for AllBlogPost
@@ -230,24 +325,29 @@ type CreateUserRequest struct {
 }
 
 type CreateCourseRequest struct {
-	Title        string `json:"title" binding:"required"`
-	Slug         string `json:"slug" binding:"required"`
-	Description  string `json:"description,omitempty"`
-	ThumbnailURL string `json:"thumbnail_url,omitempty"`
-	Status       string `json:"status,omitempty"`
+	Title        string     `json:"title" binding:"required"`
+	Slug         string     `json:"slug,omitempty"` // derived from the title when empty
+	Description  string     `json:"description,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	Status       string     `json:"status,omitempty"` // draft or pending_review on create; unchanged on update
+	PublishAt    *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt  *time.Time `json:"unpublish_at,omitempty"`
 }
 
 type CreatePostRequest struct {
-	Title        string `json:"title" binding:"required"`
-	Slug         string `json:"slug" binding:"required"`
-	Content      string `json:"content,omitempty"`
-	Excerpt      string `json:"excerpt,omitempty"`
-	ThumbnailURL string `json:"thumbnail_url,omitempty"`
-	Status       string `json:"status,omitempty"`
+	Title        string     `json:"title" binding:"required"`
+	Slug         string     `json:"slug,omitempty"` // derived from the title when empty
+	Content      string     `json:"content,omitempty"`
+	Excerpt      string     `json:"excerpt,omitempty"`
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"`
+	Status       string     `json:"status,omitempty"` // draft or pending_review on create; unchanged on update
+	PublishAt    *time.Time `json:"publish_at,omitempty"`
+	UnpublishAt  *time.Time `json:"unpublish_at,omitempty"`
 }
 
 type CreateMentorRequest struct {
 	Name        string   `json:"name" binding:"required"`
+	Slug        string   `json:"slug,omitempty"` // derived from the name when empty
 	Title       string   `json:"title,omitempty"`
 	Bio         string   `json:"bio,omitempty"`
 	AvatarURL   string   `json:"avatar_url,omitempty"`
@@ -256,16 +356,18 @@ type CreateMentorRequest struct {
 	LinkedinURL string   `json:"linkedin_url,omitempty"`
 	Specialties []string `json:"specialties,omitempty"`
 	Status      string   `json:"status,omitempty"`
+
+	UserID *uuid.UUID `json:"user_id,omitempty"` // account of the mentor
 }
 
 type CreateProjectRequest struct {
-	Slug        string       `json:"slug" binding:"required"`
+	Slug        string       `json:"slug,omitempty"` // derived from the title when empty
 	Title       string       `json:"title" binding:"required"`
 	Description string       `json:"description"`
 	Image       string       `json:"image"`
 	Category    string       `json:"category"`
 	Status      string       `json:"status"`
-	Mentors     []MentorInfo `json:"mentors"`
+	Mentors     []MentorInfo `json:"mentors"` // mentors by id, or by name to match a mentor record
 }
 
 type AuthResponse struct {
@@ -285,6 +387,7 @@ type PaginatedResponse struct {
 	Page       int         `json:"page"`
 	Limit      int         `json:"limit"`
 	TotalPages int         `json:"total_pages"`
+	Facets     *Facets     `json:"facets,omitempty"`
 }
 
 type DashboardStats struct {
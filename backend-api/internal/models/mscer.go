@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MSCer statuses
+const (
+	MSCerStatusDraft     = "draft"
+	MSCerStatusPublished = "published"
+)
+
+// MSCer is an alumni profile for the MSCer showcase: where a former learner works now,
+// what they achieved and what they say about their studies. A profile is shown on the
+// public site only once it is published, and it can only be published with the
+// alumnus' consent.
+type MSCer struct {
+	BaseModel
+	Slug           string          `gorm:"uniqueIndex;not null" json:"slug"`
+	Name           string          `gorm:"not null" json:"name"`
+	Company        string          `json:"company,omitempty"`
+	Position       string          `json:"position,omitempty"`
+	AvatarURL      string          `json:"avatar_url,omitempty"`
+	Achievement    string          `json:"achievement,omitempty"` // headline achievement
+	Testimonial    string          `gorm:"type:text" json:"testimonial,omitempty"`
+	GraduationYear int             `gorm:"index" json:"graduation_year,omitempty"`
+	Promotion      string          `json:"promotion,omitempty"` // career path, e.g. "Analyst → Manager"
+	SocialImpact   string          `gorm:"type:text" json:"social_impact,omitempty"`
+	Course         string          `gorm:"index" json:"course,omitempty"` // course taken, as shown on the site
+	Skills         StringArray     `gorm:"type:text[]" json:"skills"`
+	Achievements   StringArray     `gorm:"type:text[]" json:"achievements"`
+	Mentoring      string          `gorm:"type:text" json:"mentoring,omitempty"`
+	Background     MSCerBackground `gorm:"embedded;embeddedPrefix:background_" json:"background"`
+	Status         string          `gorm:"not null;default:'draft';index" json:"status"` // draft, published
+
+	// Consent of the alumnus to show the profile publicly, required to publish it
+	Consent   bool       `gorm:"not null;default:false" json:"consent"`
+	ConsentAt *time.Time `json:"consent_at,omitempty"`
+
+	// Account of the alumnus and the program they took, both optional
+	UserID    *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"user_id,omitempty"`
+	ProgramID *uuid.UUID `gorm:"type:uuid;index" json:"program_id,omitempty"`
+
+	// Relationships
+	Program *MSCerProgram `gorm:"foreignKey:ProgramID" json:"program,omitempty"`
+}
+
+func (MSCer) TableName() string {
+	return "mscers"
+}
+
+func (m *MSCer) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// HidePrivate clears the fields that are not shown on the public site
+func (m *MSCer) HidePrivate() {
+	m.UserID = nil
+	m.ConsentAt = nil
+}
+
+// MSCerBackground is the education and career of an alumnus before and after MSC
+type MSCerBackground struct {
+	Education    string `gorm:"type:text" json:"education,omitempty"`
+	PreviousRole string `json:"previous_role,omitempty"`
+	Experience   string `gorm:"type:text" json:"experience,omitempty"`
+}
+
+// MSCerProgram is the public view of the program an alumnus took
+type MSCerProgram struct {
+	ID    uuid.UUID `json:"id"`
+	Slug  string    `json:"slug"`
+	Title string    `json:"title"`
+}
+
+func (MSCerProgram) TableName() string {
+	return "programs"
+}
+
+type CreateMSCerRequest struct {
+	Name           string          `json:"name" binding:"required"`
+	Slug           string          `json:"slug,omitempty"` // derived from the name when empty
+	Company        string          `json:"company,omitempty"`
+	Position       string          `json:"position,omitempty"`
+	AvatarURL      string          `json:"avatar_url,omitempty"`
+	Achievement    string          `json:"achievement,omitempty"`
+	Testimonial    string          `json:"testimonial,omitempty"`
+	GraduationYear int             `json:"graduation_year,omitempty" binding:"omitempty,min=1900,max=2100"`
+	Promotion      string          `json:"promotion,omitempty"`
+	SocialImpact   string          `json:"social_impact,omitempty"`
+	Course         string          `json:"course,omitempty"`
+	Skills         []string        `json:"skills,omitempty"`
+	Achievements   []string        `json:"achievements,omitempty"`
+	Mentoring      string          `json:"mentoring,omitempty"`
+	Background     MSCerBackground `json:"background"`
+	Status         string          `json:"status,omitempty" binding:"omitempty,oneof=draft published"`
+	Consent        bool            `json:"consent"`
+
+	UserID    *uuid.UUID `json:"user_id,omitempty"`    // account of the alumnus
+	ProgramID *uuid.UUID `json:"program_id,omitempty"` // program the alumnus took
+}
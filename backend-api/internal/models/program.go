@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
 // StringArray is a custom type to handle PostgreSQL text[] arrays
@@ -89,18 +90,31 @@ func (s StringArray) Value() (driver.Value, error) {
 }
 
 type Program struct {
-	ID              uuid.UUID   `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	Slug            string      `json:"slug" gorm:"uniqueIndex;not null"`
-	Title           string      `json:"title" gorm:"not null"`
-	Description     string      `json:"description"`
-	DetailedContent string      `json:"detailed_content"`
-	Duration        string      `json:"duration"`
-	Students        string      `json:"students"`
-	Level           string      `json:"level"`
-	Price           string      `json:"price"`
-	Image           string      `json:"image"`
-	Highlights      StringArray `json:"highlights" gorm:"type:text[]"`
-	CreatedAt       time.Time   `json:"created_at"`
-	UpdatedAt       time.Time   `json:"updated_at"`
-	Category        string      `json:"category" gorm:"type:varchar(100)"`
+	ID              uuid.UUID      `json:"id" gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Slug            string         `json:"slug" gorm:"uniqueIndex;not null"`
+	Title           string         `json:"title" gorm:"not null"`
+	Description     string         `json:"description"`
+	DetailedContent string         `json:"detailed_content"`
+	Duration        string         `json:"duration"`
+	Students        string         `json:"students"`
+	Level           string         `json:"level"`
+	Price           string         `json:"price"`
+	Image           string         `json:"image"`
+	Highlights      StringArray    `json:"highlights" gorm:"type:text[]"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	Category        string         `json:"category" gorm:"type:varchar(100)"`
+	Views           int            `json:"views" gorm:"default:0"`
+	Likes           int            `json:"likes" gorm:"default:0"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Highlighted match, filled only for ?search= results
+	Snippet string `json:"snippet,omitempty" gorm:"-"`
+
+	// Categories and tags, filled by list and detail endpoints
+	Categories []Category `json:"categories,omitempty" gorm:"-"`
+	Tags       []Tag      `json:"tags,omitempty" gorm:"-"`
+
+	// Assigned mentors, filled by the detail endpoint
+	Mentors []AssignedMentor `json:"mentors,omitempty" gorm:"-"`
 }
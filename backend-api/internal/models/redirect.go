@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// SlugHistory records a slug that a content item used before, so links to it can be
+// redirected. An old slug points at the last item that gave it up.
+type SlugHistory struct {
+	EntityType string    `gorm:"primaryKey" json:"entity_type"`
+	Slug       string    `gorm:"primaryKey" json:"slug"`
+	EntityID   string    `gorm:"not null;index" json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (SlugHistory) TableName() string {
+	return "slug_history"
+}
+
+// Redirect is returned instead of a content item requested by one of its old slugs
+type Redirect struct {
+	Slug string `json:"slug"` // the current slug
+	Path string `json:"path"` // the item's page on the public site
+}
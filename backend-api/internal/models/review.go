@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Review moderation statuses
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Review is a learner's rating of a course or mentor, from 1 to 5 stars with an
+// optional text. A learner has one review per course or mentor and edits it in place;
+// only approved reviews are shown and counted in the ratings.
+type Review struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EntityType  string     `gorm:"not null;uniqueIndex:idx_review_author;index:idx_review_target" json:"entity_type"` // course or mentor
+	EntityID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_review_author;index:idx_review_target" json:"entity_id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_review_author" json:"user_id"`
+	Rating      int        `gorm:"not null;check:rating BETWEEN 1 AND 5" json:"rating"`
+	Content     string     `gorm:"type:text" json:"content,omitempty"`
+	Status      string     `gorm:"not null;default:'pending';index:idx_review_target" json:"status"`
+	ModeratedBy *uuid.UUID `gorm:"type:uuid" json:"moderated_by,omitempty"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationships
+	User *CommentAuthor `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (r *Review) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// SubmitReviewRequest creates a learner's review or replaces the one they wrote before
+type SubmitReviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Content string `json:"content" binding:"max=2000"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Revision is an immutable snapshot of a post or course, written on every create, update and restore
+type Revision struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EntityType   string    `gorm:"not null;uniqueIndex:idx_revisions_entity_version" json:"entity_type"`
+	EntityID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_revisions_entity_version" json:"entity_id"`
+	Version      int       `gorm:"not null;uniqueIndex:idx_revisions_entity_version" json:"version"`
+	AuthorID     uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
+	Snapshot     string    `gorm:"type:jsonb;not null" json:"snapshot"`
+	RestoredFrom *int      `json:"restored_from,omitempty"` // version this revision was restored from
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relationships
+	Author User `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+}
+
+func (r *Revision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeUpdate keeps revisions immutable once written
+func (r *Revision) BeforeUpdate(tx *gorm.DB) error {
+	return errors.New("revisions are immutable")
+}
+
+// MarshalJSON embeds the snapshot as a JSON object instead of an escaped string
+func (r Revision) MarshalJSON() ([]byte, error) {
+	type alias Revision
+	return json.Marshal(struct {
+		alias
+		Snapshot json.RawMessage `json:"snapshot"`
+	}{alias: alias(r), Snapshot: rawJSON(r.Snapshot)})
+}
+
+// PostSnapshot is the versioned content of a Post
+type PostSnapshot struct {
+	Title        string          `json:"title"`
+	Slug         string          `json:"slug"`
+	Content      json.RawMessage `json:"content"`
+	Excerpt      string          `json:"excerpt"`
+	ThumbnailURL string          `json:"thumbnail_url"`
+	Status       string          `json:"status"`
+}
+
+// CourseSnapshot is the versioned content of a Course
+type CourseSnapshot struct {
+	Title        string `json:"title"`
+	Slug         string `json:"slug"`
+	Description  string `json:"description"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	Status       string `json:"status"`
+}
+
+// Snapshot captures the versioned fields of the post
+func (p *Post) Snapshot() PostSnapshot {
+	return PostSnapshot{
+		Title:        p.Title,
+		Slug:         p.Slug,
+		Content:      rawJSON(p.Content),
+		Excerpt:      p.Excerpt,
+		ThumbnailURL: p.ThumbnailURL,
+		Status:       p.Status,
+	}
+}
+
+// ApplySnapshot copies the content fields of a snapshot back onto the post.
+// Status is left untouched: restoring content never changes the review state.
+func (p *Post) ApplySnapshot(s PostSnapshot) {
+	p.Title = s.Title
+	p.Slug = s.Slug
+	p.Content = ""
+	if len(s.Content) > 0 && string(s.Content) != "null" {
+		p.Content = string(s.Content)
+	}
+	p.Excerpt = s.Excerpt
+	p.ThumbnailURL = s.ThumbnailURL
+}
+
+// Snapshot captures the versioned fields of the course
+func (c *Course) Snapshot() CourseSnapshot {
+	return CourseSnapshot{
+		Title:        c.Title,
+		Slug:         c.Slug,
+		Description:  c.Description,
+		ThumbnailURL: c.ThumbnailURL,
+		Status:       c.Status,
+	}
+}
+
+// ApplySnapshot copies the content fields of a snapshot back onto the course.
+// Status is left untouched: restoring content never changes the review state.
+func (c *Course) ApplySnapshot(s CourseSnapshot) {
+	c.Title = s.Title
+	c.Slug = s.Slug
+	c.Description = s.Description
+	c.ThumbnailURL = s.ThumbnailURL
+}
+
+// rawJSON returns s as raw JSON, falling back to a JSON string (or null) when s is not valid JSON
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return json.RawMessage("null")
+	}
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	b, _ := json.Marshal(s)
+	return b
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category is a managed, hierarchical content category
+type Category struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`
+	Slug        string     `gorm:"uniqueIndex;not null" json:"slug"`
+	Description string     `json:"description,omitempty"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Position    int        `gorm:"default:0" json:"position"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Filled when the categories are returned as a tree
+	Children []Category `gorm:"-" json:"children,omitempty"`
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// Tag is a free-form label; tags are created on the fly when content is tagged
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Slug      string    `gorm:"uniqueIndex;not null" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// ContentCategory links any content row to a category. EntityID is text because
+// allblogposts use serial keys while every other table uses UUIDs.
+type ContentCategory struct {
+	EntityType string    `gorm:"primaryKey" json:"entity_type"`
+	EntityID   string    `gorm:"primaryKey" json:"entity_id"`
+	CategoryID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"category_id"`
+}
+
+// ContentTag links any content row to a tag
+type ContentTag struct {
+	EntityType string    `gorm:"primaryKey" json:"entity_type"`
+	EntityID   string    `gorm:"primaryKey" json:"entity_id"`
+	TagID      uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"tag_id"`
+}
+
+// Facet is the number of matching items in one category or tag
+type Facet struct {
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// Facets are the category and tag counts of a filtered list, ignoring pagination
+type Facets struct {
+	Categories []Facet `json:"categories"`
+	Tags       []Facet `json:"tags"`
+}
+
+// Taxonomy is the categories and tags of one content item
+type Taxonomy struct {
+	Categories []Category `json:"categories"`
+	Tags       []Tag      `json:"tags"`
+}
+
+type CategoryRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Slug        string     `json:"slug"` // derived from name when empty
+	Description string     `json:"description"`
+	ParentID    *uuid.UUID `json:"parent_id"`
+	Position    int        `json:"position"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required"`
+	Slug string `json:"slug"` // derived from name when empty
+}
+
+// SetTaxonomyRequest replaces the categories and tags of a content item. Tags are
+// names; unknown ones are created.
+type SetTaxonomyRequest struct {
+	CategoryIDs []uuid.UUID `json:"category_ids"`
+	Tags        []string    `json:"tags"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Supported content locales. Content is written in LocaleVI; other locales are
+// translations that fall back to it field by field.
+const (
+	LocaleVI = "vi"
+	LocaleEN = "en"
+)
+
+// Translation is the text of one field of a content item in a locale other than
+// Vietnamese. EntityID is text because content tables use both UUID and numeric keys.
+type Translation struct {
+	EntityType string     `gorm:"primaryKey" json:"entity_type"`
+	EntityID   string     `gorm:"primaryKey" json:"entity_id"`
+	Locale     string     `gorm:"primaryKey;index" json:"locale"`
+	Field      string     `gorm:"primaryKey" json:"field"`
+	Value      string     `gorm:"type:text;not null" json:"value"`
+	UpdatedBy  *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Translatable is content with text fields that can be translated. TranslatableFields
+// returns the fields by their JSON name.
+type Translatable interface {
+	TranslatableFields() map[string]*string
+}
+
+func (c *Course) TranslatableFields() map[string]*string {
+	return map[string]*string{"title": &c.Title, "description": &c.Description}
+}
+
+func (m *Mentor) TranslatableFields() map[string]*string {
+	return map[string]*string{"title": &m.Title, "bio": &m.Bio}
+}
+
+func (p *Project) TranslatableFields() map[string]*string {
+	return map[string]*string{"title": &p.Title, "description": &p.Description}
+}
+
+func (p *Program) TranslatableFields() map[string]*string {
+	return map[string]*string{
+		"title":            &p.Title,
+		"description":      &p.Description,
+		"detailed_content": &p.DetailedContent,
+		"duration":         &p.Duration,
+		"level":            &p.Level,
+	}
+}
+
+func (p *AllBlogPost) TranslatableFields() map[string]*string {
+	return map[string]*string{"title": &p.Title, "excerpt": &p.Excerpt, "details_blog": &p.DetailsBlog}
+}
+
+// TranslationsRequest sets the translation of an item into one locale. Empty values
+// remove the field's translation.
+type TranslationsRequest struct {
+	Fields map[string]string `json:"fields" binding:"required"`
+}
+
+// ItemTranslations is every translation of a content item by locale and field
+type ItemTranslations struct {
+	EntityType string                       `json:"entity_type"`
+	EntityID   string                       `json:"entity_id"`
+	Fields     []string                     `json:"fields"`
+	Source     map[string]string            `json:"source"` // the Vietnamese text
+	Locales    map[string]map[string]string `json:"locales"`
+}
+
+// MissingTranslation lists the translatable fields of an item that have source text but
+// no translation
+type MissingTranslation struct {
+	EntityType string   `json:"entity_type"`
+	EntityID   string   `json:"entity_id"`
+	Label      string   `json:"label"`
+	Fields     []string `json:"fields"`
+}
+
+// TranslationReport is the translation coverage of one content type in one locale
+type TranslationReport struct {
+	Type     string               `json:"type"`
+	Locale   string               `json:"locale"`
+	Total    int                  `json:"total"`
+	Complete int                  `json:"complete"`
+	Missing  []MissingTranslation `json:"missing"`
+}
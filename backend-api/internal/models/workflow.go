@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StatusTransition records one workflow step of a post or course
+type StatusTransition struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	EntityType string    `gorm:"not null;index:idx_status_transitions_entity" json:"entity_type"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index:idx_status_transitions_entity" json:"entity_id"`
+	Action     string    `gorm:"not null" json:"action"`
+	FromStatus string    `gorm:"not null" json:"from_status"`
+	ToStatus   string    `gorm:"not null" json:"to_status"`
+	ActorID    uuid.UUID `gorm:"type:uuid;not null" json:"actor_id"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relationships
+	Actor User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+func (t *StatusTransition) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TransitionRequest triggers a workflow action; Reason is required when rejecting
+type TransitionRequest struct {
+	Action string `json:"action" binding:"required"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReviewRequest carries the optional review note of approve/reject/archive calls
+type ReviewRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,122 @@
+// Package redirects keeps the slugs content items had before, so links shared with an
+// old slug lead to the item under its current one.
+//
+// A slug that is live always wins: history is only consulted when no item has the
+// slug today, and an item that takes a slug back removes it from the history.
+package redirects
+
+import (
+	"errors"
+
+	"msc-backend-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnknownType is returned for content types without slug history
+var ErrUnknownType = errors.New("unknown content type")
+
+// Type is a content table whose items have a public page by slug
+type Type struct {
+	Name       string
+	Table      string
+	EntityType string
+	// Public restricts rows to what the public page of the type shows
+	Public string
+	Path   func(slug string) string
+}
+
+var (
+	Projects = Type{
+		Name:       "projects",
+		Table:      "projects",
+		EntityType: models.EntityTypeProject,
+		Public:     "deleted_at IS NULL",
+		Path:       func(slug string) string { return "/du-an/" + slug },
+	}
+	AllBlogPosts = Type{
+		Name:       "blog",
+		Table:      "allblogposts",
+		EntityType: models.EntityTypeBlogPost,
+		Public:     "deleted_at IS NULL",
+		Path:       func(slug string) string { return "/chia-se/" + slug },
+	}
+	Mentors = Type{
+		Name:       "mentors",
+		Table:      "mentors",
+		EntityType: models.EntityTypeMentor,
+		Public:     "deleted_at IS NULL AND status = 'active'",
+		Path:       func(slug string) string { return "/mentors/" + slug },
+	}
+	MSCers = Type{
+		Name:       "mscers",
+		Table:      "mscers",
+		EntityType: models.EntityTypeMSCer,
+		Public:     "deleted_at IS NULL AND status = 'published' AND consent",
+		Path:       func(slug string) string { return "/mscer/" + slug },
+	}
+)
+
+// Types are the content types with slug history by their URL name
+var Types = map[string]Type{
+	Projects.Name:     Projects,
+	AllBlogPosts.Name: AllBlogPosts,
+	Mentors.Name:      Mentors,
+	MSCers.Name:       MSCers,
+}
+
+func Lookup(name string) (Type, error) {
+	t, ok := Types[name]
+	if !ok {
+		return Type{}, ErrUnknownType
+	}
+	return t, nil
+}
+
+// Record notes that an item changed its slug from oldSlug to newSlug. It is a no-op
+// when the slug did not change.
+func Record(tx *gorm.DB, entityType, entityID, oldSlug, newSlug string) error {
+	if oldSlug == "" || oldSlug == newSlug {
+		return nil
+	}
+	// The new slug is live again and must not redirect anywhere
+	if err := tx.Where("entity_type = ? AND slug = ?", entityType, newSlug).Delete(&models.SlugHistory{}).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"entity_id", "created_at"}),
+	}).Create(&models.SlugHistory{
+		EntityType: entityType,
+		Slug:       oldSlug,
+		EntityID:   entityID,
+	}).Error
+}
+
+// Current returns the slug of the public item that used to have slug. It returns
+// gorm.ErrRecordNotFound when the slug was never used or its item is gone or not public,
+// so old slugs of hidden items do not reveal their new one.
+func Current(db *gorm.DB, t Type, slug string) (string, error) {
+	var current string
+	err := db.Table(t.Table).
+		Select("slug").
+		Where("id::text IN (?)", db.Model(&models.SlugHistory{}).
+			Select("entity_id").
+			Where("entity_type = ? AND slug = ?", t.EntityType, slug)).
+		Where(t.Public).
+		Limit(1).
+		Scan(&current).Error
+	if err != nil {
+		return "", err
+	}
+	if current == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+	return current, nil
+}
+
+// Unlink removes the slug history of items that are deleted for good
+func Unlink(tx *gorm.DB, entityType string, ids []string) error {
+	return tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Delete(&models.SlugHistory{}).Error
+}
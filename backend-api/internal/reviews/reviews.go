@@ -0,0 +1,94 @@
+// Package reviews holds the rules of learner reviews of courses and mentors: who may
+// review what, and the rating averages and counts kept on the reviewed rows.
+package reviews
+
+import (
+	"errors"
+	"time"
+
+	"msc-backend-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnknownType is returned for entity types that cannot be reviewed
+var ErrUnknownType = errors.New("unknown review type")
+
+// tables are the tables of the entity types that can be reviewed
+var tables = map[string]string{
+	models.EntityTypeCourse: "courses",
+	models.EntityTypeMentor: "mentors",
+}
+
+// Valid reports whether entityType can be reviewed
+func Valid(entityType string) bool {
+	_, ok := tables[entityType]
+	return ok
+}
+
+// CanReview reports whether a user is a learner of a course or mentor: enrolled in the
+// course, or for a mentor, enrolled in a course the mentor teaches or past the start
+// of a confirmed session with them
+func CanReview(db *gorm.DB, entityType string, entityID, userID uuid.UUID) (bool, error) {
+	var count int64
+	switch entityType {
+	case models.EntityTypeCourse:
+		err := db.Model(&models.Enrollment{}).
+			Where("course_id = ? AND user_id = ?", entityID, userID).
+			Count(&count).Error
+		return count > 0, err
+	case models.EntityTypeMentor:
+		err := db.Model(&models.Enrollment{}).
+			Joins("JOIN mentor_assignments a ON a.entity_type = ? AND a.entity_id = enrollments.course_id", models.EntityTypeCourse).
+			Where("a.mentor_id = ? AND enrollments.user_id = ?", entityID, userID).
+			Count(&count).Error
+		if err != nil || count > 0 {
+			return count > 0, err
+		}
+		err = db.Model(&models.Booking{}).
+			Where("mentor_id = ? AND user_id = ? AND status = ? AND starts_at <= ?", entityID, userID, models.BookingStatusConfirmed, time.Now()).
+			Count(&count).Error
+		return count > 0, err
+	}
+	return false, ErrUnknownType
+}
+
+// Lock takes the row lock of a course or mentor. Every transaction that changes its
+// reviews takes it before touching them, so those transactions run one after the other.
+func Lock(tx *gorm.DB, entityType string, entityID uuid.UUID) error {
+	table, ok := tables[entityType]
+	if !ok {
+		return ErrUnknownType
+	}
+	var locked []uuid.UUID
+	return tx.Table(table).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", entityID).
+		Pluck("id", &locked).Error
+}
+
+// Refresh recomputes the rating average and count of a course or mentor from its
+// approved reviews. The transaction must hold Lock for the item: a refresh only sees
+// reviews committed before its statement started, and the lock guarantees no other
+// change to the item's reviews is in flight at that point.
+func Refresh(tx *gorm.DB, entityType string, entityID uuid.UUID) error {
+	table, ok := tables[entityType]
+	if !ok {
+		return ErrUnknownType
+	}
+	return tx.Exec(`UPDATE `+table+` SET
+		rating_average = COALESCE((SELECT ROUND(AVG(rating)::numeric, 2) FROM reviews r WHERE r.entity_type = @type AND r.entity_id = @id AND r.status = @status), 0),
+		rating_count = (SELECT COUNT(*) FROM reviews r WHERE r.entity_type = @type AND r.entity_id = @id AND r.status = @status)
+		WHERE id = @id`,
+		map[string]interface{}{"type": entityType, "id": entityID, "status": models.ReviewStatusApproved}).Error
+}
+
+// Unlink removes the reviews of courses or mentors that are deleted for good
+func Unlink(tx *gorm.DB, entityType string, ids []string) error {
+	if !Valid(entityType) {
+		return nil
+	}
+	return tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Delete(&models.Review{}).Error
+}
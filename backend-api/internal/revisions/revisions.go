@@ -0,0 +1,57 @@
+// Package revisions stores the immutable revision history of posts and courses
+package revisions
+
+import (
+	"encoding/json"
+
+	"msc-backend-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tables are the tables of revisioned entities, by entity type
+var tables = map[string]string{
+	models.EntityTypePost:   "posts",
+	models.EntityTypeCourse: "courses",
+}
+
+// Save stores the next immutable revision of an entity. It must run inside the
+// transaction that writes the entity so that content and history never diverge. The
+// entity row is locked first so concurrent saves number their revisions one after the other.
+func Save(tx *gorm.DB, entityType string, entityID, authorID uuid.UUID, snapshot interface{}, restoredFrom *int) (*models.Revision, error) {
+	var locked []uuid.UUID
+	if err := tx.Table(tables[entityType]).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", entityID).
+		Pluck("id", &locked).Error; err != nil {
+		return nil, err
+	}
+
+	var latest int
+	if err := tx.Model(&models.Revision{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error; err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := models.Revision{
+		EntityType:   entityType,
+		EntityID:     entityID,
+		Version:      latest + 1,
+		AuthorID:     authorID,
+		Snapshot:     string(data),
+		RestoredFrom: restoredFrom,
+	}
+	if err := tx.Create(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"msc-backend-api/internal/blog"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/workflow"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// batchSize limits how many rows one replica claims per job and tick
+const batchSize = 100
+
+// job moves content whose timestamp column has passed from one status to the next.
+// With clearColumn the timestamp is reset once applied, so republishing unpublished
+// content later does not take it straight down again.
+type job struct {
+	entityType  string
+	newModel    func() interface{}
+	column      string
+	clearColumn bool
+	from        string
+	action      string
+	reason      string
+}
+
+var jobs = []job{
+	{models.EntityTypePost, func() interface{} { return &models.Post{} }, "publish_at", false, workflow.StatusScheduled, workflow.ActionPublish, "Published automatically at publish_at"},
+	{models.EntityTypePost, func() interface{} { return &models.Post{} }, "unpublish_at", true, workflow.StatusPublished, workflow.ActionUnpublish, "Unpublished automatically at unpublish_at"},
+	{models.EntityTypeCourse, func() interface{} { return &models.Course{} }, "publish_at", false, workflow.StatusScheduled, workflow.ActionPublish, "Published automatically at publish_at"},
+	{models.EntityTypeCourse, func() interface{} { return &models.Course{} }, "unpublish_at", true, workflow.StatusPublished, workflow.ActionUnpublish, "Unpublished automatically at unpublish_at"},
+}
+
+// Scheduler publishes scheduled posts and courses at their publish_at and unpublishes
+// published ones back to draft at their unpublish_at. Rows are claimed with FOR UPDATE
+// SKIP LOCKED, so several API replicas can run it side by side without applying a step twice.
+type Scheduler struct {
+	db       *gorm.DB
+	interval time.Duration
+}
+
+func New(db *gorm.DB, interval time.Duration) *Scheduler {
+	return &Scheduler{db: db, interval: interval}
+}
+
+// Run checks the schedule every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	log.Printf("Publishing scheduler started (interval %s)", s.interval)
+	for {
+		s.tick(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	for _, j := range jobs {
+		n, err := s.run(ctx, j, now)
+		if err != nil {
+			log.Printf("Scheduler %s %s failed: %v", j.entityType, j.action, err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("Scheduler: %s %d %s(s)", j.action, n, j.entityType)
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j job, now time.Time) (int, error) {
+	to, err := workflow.Apply(j.from, j.action, []string{workflow.RoleSystem}, j.reason)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []uuid.UUID
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Claim due rows; rows locked by another replica are skipped, not waited on
+		if err := tx.Model(j.newModel()).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND "+j.column+" <= ?", j.from, now).
+			Limit(batchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		updates := map[string]interface{}{"status": to}
+		if j.clearColumn {
+			updates[j.column] = nil
+		}
+		if err := tx.Model(j.newModel()).Where("id IN ?", ids).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		history := make([]models.StatusTransition, len(ids))
+		for i, id := range ids {
+			history[i] = models.StatusTransition{
+				EntityType: j.entityType,
+				EntityID:   id,
+				Action:     j.action,
+				FromStatus: j.from,
+				ToStatus:   to,
+				ActorID:    uuid.Nil, // system
+				Reason:     j.reason,
+			}
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+
+		if j.entityType == models.EntityTypePost {
+			for _, id := range ids {
+				if err := blog.SyncPost(tx, id); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
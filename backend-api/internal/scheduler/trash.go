@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"msc-backend-api/internal/trash"
+
+	"gorm.io/gorm"
+)
+
+// trashPurgeInterval is how often expired trash is looked for
+const trashPurgeInterval = time.Hour
+
+// TrashPurger permanently deletes content that has been in the trash for longer than
+// the retention period. Deleting is idempotent, so every replica may run it.
+type TrashPurger struct {
+	db        *gorm.DB
+	retention time.Duration
+}
+
+func NewTrashPurger(db *gorm.DB, retention time.Duration) *TrashPurger {
+	return &TrashPurger{db: db, retention: retention}
+}
+
+// Run purges expired trash every hour until ctx is cancelled
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(trashPurgeInterval)
+	defer ticker.Stop()
+
+	log.Printf("Trash purger started (retention %s)", p.retention)
+	for {
+		n, err := trash.PurgeExpired(p.db.WithContext(ctx), time.Now().Add(-p.retention))
+		if err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Trash purge: removed %d item(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
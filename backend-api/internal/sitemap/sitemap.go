@@ -0,0 +1,251 @@
+// Package sitemap builds the XML sitemaps of the public site from the database.
+//
+// Every content type gets its own sitemap, split into files of at most MaxURLs URLs,
+// and a sitemap index lists them all. Rendered files are cached in memory together with
+// a fingerprint of the rows they were built from; a cached file is rebuilt as soon as a
+// row is added, edited, published or removed, so no write path has to invalidate it.
+package sitemap
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxURLs is the sitemap protocol's limit of URLs per file
+const MaxURLs = 50000
+
+// ErrNotFound is returned for sitemap files that do not exist
+var ErrNotFound = errors.New("sitemap not found")
+
+// Source is a content table with a public page per row
+type Source struct {
+	Name  string // file name prefix, e.g. programs-1.xml
+	Table string
+	Slug  string // select expression of the slug
+	// Public restricts rows to what the public site shows
+	Public string
+	Path   func(id, slug string) string
+}
+
+// Sources are the content types listed in the sitemap index, in order. Projects and
+// courses are left out until the frontend has pages for them (/du-an/<slug>,
+// /khoa-hoc/<slug>); listing them would submit 404s to search engines.
+var Sources = []Source{
+	{
+		Name:   "programs",
+		Table:  "programs",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL",
+		Path:   func(id, slug string) string { return "/dao-tao/" + slug },
+	},
+	{
+		Name:   "blog",
+		Table:  "allblogposts",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL AND publish_date <= NOW()",
+		Path:   func(id, slug string) string { return "/chia-se/" + slug },
+	},
+	{
+		Name:   "mentors",
+		Table:  "mentors",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL AND status = 'active'",
+		Path:   func(id, slug string) string { return "/mentors/" + slug },
+	},
+	{
+		Name:   "mscers",
+		Table:  "mscers",
+		Slug:   "slug",
+		Public: "deleted_at IS NULL AND status = 'published' AND consent",
+		Path:   func(id, slug string) string { return "/mscer/" + slug },
+	},
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []entry  `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// stats fingerprints the public rows of a source
+type stats struct {
+	Count  int64
+	Latest *time.Time
+}
+
+func (s stats) version() string {
+	if s.Latest == nil {
+		return fmt.Sprintf("%d", s.Count)
+	}
+	return fmt.Sprintf("%d/%d", s.Count, s.Latest.UnixNano())
+}
+
+func (s stats) pages() int {
+	return int((s.Count + MaxURLs - 1) / MaxURLs)
+}
+
+type cached struct {
+	version      string
+	body         []byte
+	lastModified time.Time
+}
+
+// Generator renders sitemaps and keeps the rendered files until their rows change
+type Generator struct {
+	db      *gorm.DB
+	siteURL string
+
+	mu    sync.Mutex
+	cache map[string]cached
+}
+
+// New returns a generator for pages under siteURL, the public site's base URL
+func New(db *gorm.DB, siteURL string) *Generator {
+	return &Generator{
+		db:      db,
+		siteURL: strings.TrimSuffix(siteURL, "/"),
+		cache:   map[string]cached{},
+	}
+}
+
+// Index renders the sitemap index. baseURL is where the sitemap files are served.
+func (g *Generator) Index(baseURL string) ([]byte, time.Time, error) {
+	all := make([]stats, len(Sources))
+	versions := []string{baseURL}
+	for i, source := range Sources {
+		s, err := g.stats(source)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		all[i] = s
+		versions = append(versions, s.version())
+	}
+
+	return g.cached("index", strings.Join(versions, ","), func() ([]byte, time.Time, error) {
+		index := sitemapIndex{Sitemaps: []entry{}}
+		var lastModified time.Time
+		for i, source := range Sources {
+			lastMod := ""
+			if latest := all[i].Latest; latest != nil {
+				lastMod = latest.UTC().Format(time.RFC3339)
+				if latest.After(lastModified) {
+					lastModified = *latest
+				}
+			}
+			for page := 1; page <= all[i].pages(); page++ {
+				index.Sitemaps = append(index.Sitemaps, entry{
+					Loc:     fmt.Sprintf("%s/sitemaps/%s-%d.xml", baseURL, source.Name, page),
+					LastMod: lastMod,
+				})
+			}
+		}
+		body, err := encode(index)
+		return body, lastModified, err
+	})
+}
+
+// Sitemap renders page (from 1) of the sitemap of the named source
+func (g *Generator) Sitemap(name string, page int) ([]byte, time.Time, error) {
+	var source *Source
+	for i := range Sources {
+		if Sources[i].Name == name {
+			source = &Sources[i]
+		}
+	}
+	if source == nil || page < 1 {
+		return nil, time.Time{}, ErrNotFound
+	}
+
+	s, err := g.stats(*source)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if page > s.pages() {
+		return nil, time.Time{}, ErrNotFound
+	}
+
+	return g.cached(fmt.Sprintf("%s-%d", name, page), s.version(), func() ([]byte, time.Time, error) {
+		var rows []struct {
+			ID        string
+			Slug      string
+			UpdatedAt *time.Time
+		}
+		if err := g.db.Table(source.Table).
+			Select("id::text AS id, " + source.Slug + " AS slug, updated_at").
+			Where(source.Public).
+			Order("id").
+			Limit(MaxURLs).
+			Offset((page - 1) * MaxURLs).
+			Scan(&rows).Error; err != nil {
+			return nil, time.Time{}, err
+		}
+
+		set := urlSet{URLs: make([]entry, 0, len(rows))}
+		var lastModified time.Time
+		for _, row := range rows {
+			url := entry{Loc: g.siteURL + source.Path(row.ID, row.Slug)}
+			if row.UpdatedAt != nil {
+				url.LastMod = row.UpdatedAt.UTC().Format(time.RFC3339)
+				if row.UpdatedAt.After(lastModified) {
+					lastModified = *row.UpdatedAt
+				}
+			}
+			set.URLs = append(set.URLs, url)
+		}
+		body, err := encode(set)
+		return body, lastModified, err
+	})
+}
+
+func (g *Generator) stats(source Source) (stats, error) {
+	var s stats
+	err := g.db.Table(source.Table).
+		Select("COUNT(*) AS count, MAX(updated_at) AS latest").
+		Where(source.Public).
+		Scan(&s).Error
+	return s, err
+}
+
+// cached returns the file stored under key if it was built from the same version of
+// the data, and builds and stores it otherwise
+func (g *Generator) cached(key, version string, build func() ([]byte, time.Time, error)) ([]byte, time.Time, error) {
+	g.mu.Lock()
+	hit, ok := g.cache[key]
+	g.mu.Unlock()
+	if ok && hit.version == version {
+		return hit.body, hit.lastModified, nil
+	}
+
+	body, lastModified, err := build()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	g.mu.Lock()
+	g.cache[key] = cached{version: version, body: body, lastModified: lastModified}
+	g.mu.Unlock()
+	return body, lastModified, nil
+}
+
+func encode(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,394 @@
+package taxonomy
+
+import (
+	"errors"
+	"strings"
+
+	"msc-backend-api/internal/models"
+	"msc-backend-api/pkg/slug"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownType     = errors.New("unknown content type")
+	ErrUnknownCategory = errors.New("unknown category")
+	ErrCategoryCycle   = errors.New("a category cannot be its own ancestor")
+)
+
+// Target is a content type that can be categorized and tagged
+type Target struct {
+	Name       string
+	Table      string
+	EntityType string
+	UUIDKey    bool // false for serial primary keys
+	// LegacyColumn is the free-text category column of older tables. It is kept in sync
+	// with the item's first category and still matched by the category filter.
+	LegacyColumn string
+}
+
+var (
+	Courses      = Target{Name: "courses", Table: "courses", EntityType: models.EntityTypeCourse, UUIDKey: true}
+	Posts        = Target{Name: "posts", Table: "posts", EntityType: models.EntityTypePost, UUIDKey: true}
+	Programs     = Target{Name: "programs", Table: "programs", EntityType: models.EntityTypeProgram, UUIDKey: true, LegacyColumn: "category"}
+	Mentors      = Target{Name: "mentors", Table: "mentors", EntityType: models.EntityTypeMentor, UUIDKey: true}
+	Projects     = Target{Name: "projects", Table: "projects", EntityType: models.EntityTypeProject, UUIDKey: true, LegacyColumn: "category"}
+	AllBlogPosts = Target{Name: "allblogposts", Table: "allblogposts", EntityType: models.EntityTypeBlogPost, LegacyColumn: "category"}
+
+	// Targets is keyed by URL name
+	Targets = map[string]Target{
+		Courses.Name:      Courses,
+		Posts.Name:        Posts,
+		Programs.Name:     Programs,
+		Projects.Name:     Projects,
+		Mentors.Name:      Mentors,
+		AllBlogPosts.Name: AllBlogPosts,
+	}
+)
+
+// Lookup returns the target registered under a URL name
+func Lookup(name string) (Target, error) {
+	t, ok := Targets[name]
+	if !ok {
+		return Target{}, ErrUnknownType
+	}
+	return t, nil
+}
+
+// ValidID reports whether id has the key format of the target
+func (t Target) ValidID(id string) bool {
+	if t.UUIDKey {
+		_, err := uuid.Parse(id)
+		return err == nil
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return id != ""
+}
+
+// categoryTree selects the IDs of the category matching ? (slug or name) and all its descendants
+const categoryTree = `WITH RECURSIVE tree AS (
+	SELECT id FROM categories WHERE slug = ? OR lower(name) = lower(?)
+	UNION
+	SELECT c.id FROM categories c JOIN tree ON c.parent_id = tree.id
+) SELECT id FROM tree`
+
+// Filter restricts a list query to items in the category, or any of its descendants, and
+// with the tag. Empty values don't filter. category matches a slug or a name.
+func Filter(query *gorm.DB, t Target, category, tag string) *gorm.DB {
+	idColumn := t.Table + ".id::text"
+	if category != "" {
+		cond := idColumn + ` IN (SELECT entity_id FROM content_categories
+			WHERE entity_type = ? AND category_id IN (` + categoryTree + `))`
+		args := []interface{}{t.EntityType, category, category}
+		if t.LegacyColumn != "" {
+			cond = "(" + cond + " OR " + t.Table + "." + t.LegacyColumn + " = ?)"
+			args = append(args, category)
+		}
+		query = query.Where(cond, args...)
+	}
+	if tag != "" {
+		query = query.Where(idColumn+` IN (SELECT ct.entity_id FROM content_tags ct
+			JOIN tags ON tags.id = ct.tag_id
+			WHERE ct.entity_type = ? AND tags.slug = ?)`, t.EntityType, slug.Make(tag))
+	}
+	return query
+}
+
+// Facets counts the items of a filtered list per category and tag. query is the list
+// query before pagination; it is not modified.
+func Facets(db *gorm.DB, query *gorm.DB, t Target) (*models.Facets, error) {
+	ids := query.Session(&gorm.Session{}).Select(t.Table + ".id::text")
+
+	facets := &models.Facets{Categories: []models.Facet{}, Tags: []models.Facet{}}
+	if err := db.Table("content_categories cc").
+		Select("c.slug, c.name, COUNT(*) AS count").
+		Joins("JOIN categories c ON c.id = cc.category_id").
+		Where("cc.entity_type = ? AND cc.entity_id IN (?)", t.EntityType, ids).
+		Group("c.slug, c.name").
+		Order("count DESC, c.name").
+		Scan(&facets.Categories).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Table("content_tags ct").
+		Select("tags.slug, tags.name, COUNT(*) AS count").
+		Joins("JOIN tags ON tags.id = ct.tag_id").
+		Where("ct.entity_type = ? AND ct.entity_id IN (?)", t.EntityType, ids).
+		Group("tags.slug, tags.name").
+		Order("count DESC, tags.name").
+		Limit(50).
+		Scan(&facets.Tags).Error; err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// Load returns the categories and tags of the given items keyed by ID
+func Load(db *gorm.DB, t Target, ids []string) (map[string]models.Taxonomy, error) {
+	result := make(map[string]models.Taxonomy, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	var categories []struct {
+		EntityID string
+		models.Category
+	}
+	if err := db.Table("content_categories cc").
+		Select("cc.entity_id, c.*").
+		Joins("JOIN categories c ON c.id = cc.category_id").
+		Where("cc.entity_type = ? AND cc.entity_id IN ?", t.EntityType, ids).
+		Order("c.position, c.name").
+		Scan(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	var tags []struct {
+		EntityID string
+		models.Tag
+	}
+	if err := db.Table("content_tags ct").
+		Select("ct.entity_id, tags.*").
+		Joins("JOIN tags ON tags.id = ct.tag_id").
+		Where("ct.entity_type = ? AND ct.entity_id IN ?", t.EntityType, ids).
+		Order("tags.name").
+		Scan(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		result[id] = models.Taxonomy{Categories: []models.Category{}, Tags: []models.Tag{}}
+	}
+	for _, row := range categories {
+		tax := result[row.EntityID]
+		tax.Categories = append(tax.Categories, row.Category)
+		result[row.EntityID] = tax
+	}
+	for _, row := range tags {
+		tax := result[row.EntityID]
+		tax.Tags = append(tax.Tags, row.Tag)
+		result[row.EntityID] = tax
+	}
+	return result, nil
+}
+
+// Set replaces the categories and tags of an item. Unknown tag names are created. It
+// returns gorm.ErrRecordNotFound when the item does not exist and ErrUnknownCategory
+// when a category ID does not exist.
+func Set(db *gorm.DB, t Target, id string, req models.SetTaxonomyRequest) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Table(t.Table).Where("id = ? AND deleted_at IS NULL", id).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		categoryIDs := uniqueIDs(req.CategoryIDs)
+		var categories []models.Category
+		if len(categoryIDs) > 0 {
+			if err := tx.Where("id IN ?", categoryIDs).Find(&categories).Error; err != nil {
+				return err
+			}
+			if len(categories) != len(categoryIDs) {
+				return ErrUnknownCategory
+			}
+		}
+
+		if err := tx.Where("entity_type = ? AND entity_id = ?", t.EntityType, id).Delete(&models.ContentCategory{}).Error; err != nil {
+			return err
+		}
+		for _, categoryID := range categoryIDs {
+			link := models.ContentCategory{EntityType: t.EntityType, EntityID: id, CategoryID: categoryID}
+			if err := tx.Create(&link).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("entity_type = ? AND entity_id = ?", t.EntityType, id).Delete(&models.ContentTag{}).Error; err != nil {
+			return err
+		}
+		tags, err := ensureTags(tx, req.Tags)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			link := models.ContentTag{EntityType: t.EntityType, EntityID: id, TagID: tag.ID}
+			if err := tx.Create(&link).Error; err != nil {
+				return err
+			}
+		}
+
+		if t.LegacyColumn == "" {
+			return nil
+		}
+		// The first category is the primary one shown by clients that still read the column
+		legacy := ""
+		if len(categoryIDs) > 0 {
+			for _, c := range categories {
+				if c.ID == categoryIDs[0] {
+					legacy = c.Name
+				}
+			}
+		}
+		return tx.Table(t.Table).Where("id = ?", id).Update(t.LegacyColumn, legacy).Error
+	})
+}
+
+// ensureTags finds or creates tags by name, deduplicated by slug, in input order
+func ensureTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		s := slug.Make(name)
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+
+		tag := models.Tag{Name: name, Slug: s}
+		if err := tx.Where("slug = ?", s).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	var unique []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// Unlink removes the category and tag links of deleted items
+func Unlink(tx *gorm.DB, entityType string, ids []string) error {
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Delete(&models.ContentCategory{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Delete(&models.ContentTag{}).Error
+}
+
+// Tree nests a flat category list under its parents, keeping the input order among siblings
+func Tree(categories []models.Category) []models.Category {
+	children := map[uuid.UUID][]models.Category{}
+	var roots []models.Category
+	known := map[uuid.UUID]bool{}
+	for _, c := range categories {
+		known[c.ID] = true
+	}
+	for _, c := range categories {
+		if c.ParentID != nil && known[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	var attach func(nodes []models.Category) []models.Category
+	attach = func(nodes []models.Category) []models.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+	if roots == nil {
+		return []models.Category{}
+	}
+	return attach(roots)
+}
+
+// CheckParent reports ErrCategoryCycle when parentID is id itself or one of its descendants
+func CheckParent(db *gorm.DB, id uuid.UUID, parentID *uuid.UUID) error {
+	for current := parentID; current != nil; {
+		if *current == id {
+			return ErrCategoryCycle
+		}
+		var parent models.Category
+		if err := db.Select("id, parent_id").Where("id = ?", *current).First(&parent).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUnknownCategory
+			}
+			return err
+		}
+		current = parent.ParentID
+	}
+	return nil
+}
+
+// DeleteCategory removes a category and its links; its children move up to its parent
+func DeleteCategory(db *gorm.DB, id uuid.UUID) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		if err := tx.Where("id = ?", id).First(&category).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", id).Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&models.ContentCategory{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	})
+}
+
+// DeleteTag removes a tag and its links
+func DeleteTag(db *gorm.DB, id uuid.UUID) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var tag models.Tag
+		if err := tx.Where("id = ?", id).First(&tag).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.ContentTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+}
+
+// Backfill turns the free-text categories of older tables into managed categories and
+// links items that have no category yet. It is idempotent and runs on startup.
+func Backfill(db *gorm.DB) error {
+	for _, t := range []Target{Programs, Projects, AllBlogPosts} {
+		var names []string
+		if err := db.Table(t.Table).
+			Where(t.LegacyColumn+" IS NOT NULL AND "+t.LegacyColumn+" <> ''").
+			Distinct().
+			Pluck(t.LegacyColumn, &names).Error; err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			s := slug.Make(name)
+			if s == "" {
+				continue
+			}
+			category := models.Category{Name: strings.TrimSpace(name), Slug: s}
+			if err := db.Where("slug = ?", s).FirstOrCreate(&category).Error; err != nil {
+				return err
+			}
+			if err := db.Exec(`INSERT INTO content_categories (entity_type, entity_id, category_id)
+				SELECT ?, id::text, ? FROM `+t.Table+` WHERE `+t.LegacyColumn+` = ?
+				AND NOT EXISTS (
+					SELECT 1 FROM content_categories cc
+					WHERE cc.entity_type = ? AND cc.entity_id = `+t.Table+`.id::text
+				)`, t.EntityType, category.ID, name, t.EntityType).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
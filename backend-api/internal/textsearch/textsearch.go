@@ -0,0 +1,202 @@
+package textsearch
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Config is the Postgres text search configuration: the simple parser with unaccent,
+// so "khoa hoc" matches "khóa học"
+const Config = "vietnamese"
+
+// Snippet highlight delimiters; control characters can't collide with stored text and
+// are swapped for <mark> tags after escaping
+const (
+	markStart = "\x02"
+	markStop  = "\x03"
+)
+
+// Source is a table with a generated search_vector column
+type Source struct {
+	Table string
+	// Vector is the weighted tsvector expression the search_vector column is generated from
+	Vector string
+	// Snippet is the text expression highlighted in results
+	Snippet string
+	// Suggest is the tsvector expression over titles and tags used for autocomplete
+	Suggest string
+}
+
+var (
+	Courses = Source{
+		Table: "courses",
+		Vector: weighted("A", "title") + " || " +
+			weighted("B", "description"),
+		Snippet: "COALESCE(NULLIF(description, ''), title)",
+		Suggest: suggestVector("title"),
+	}
+	Posts = Source{
+		Table: "posts",
+		Vector: weighted("A", "title") + " || " +
+			weighted("B", "excerpt") + " || " +
+			// Only the text leaves of the Tiptap document, not node type names
+			"setweight(to_tsvector('" + Config + "', COALESCE(jsonb_path_query_array(content, 'strict $.**.text'), '[]'::jsonb)), 'C')",
+		Snippet: "COALESCE(NULLIF(excerpt, ''), title)",
+		Suggest: suggestVector("title"),
+	}
+	Programs = Source{
+		Table: "programs",
+		Vector: weighted("A", "title") + " || " +
+			weighted("B", "description") + " || " +
+			weighted("B", "category") + " || " +
+			weighted("C", "detailed_content"),
+		Snippet: "COALESCE(NULLIF(description, ''), title)",
+		Suggest: suggestVector("title || ' ' || COALESCE(category, '')"),
+	}
+	Projects = Source{
+		Table: "projects",
+		Vector: weighted("A", "title") + " || " +
+			weighted("B", "description") + " || " +
+			weighted("C", "category"),
+		Snippet: "COALESCE(NULLIF(description, ''), title)",
+		Suggest: suggestVector("title || ' ' || COALESCE(category, '')"),
+	}
+	Mentors = Source{
+		Table: "mentors",
+		Vector: weighted("A", "name") + " || " +
+			weighted("B", "title") + " || " +
+			weighted("C", "bio") + " || " +
+			weighted("C", "msc_immutable_array_to_string(specialties)"),
+		Snippet: "COALESCE(NULLIF(bio, ''), title, name)",
+		Suggest: suggestVector("name || ' ' || COALESCE(msc_immutable_array_to_string(specialties), '')"),
+	}
+	AllBlogPosts = Source{
+		Table: "allblogposts",
+		Vector: weighted("A", "title") + " || " +
+			weighted("B", "excerpt") + " || " +
+			weighted("B", "category") + " || " +
+			weighted("C", "details_blog"),
+		Snippet: "COALESCE(NULLIF(excerpt, ''), title)",
+		Suggest: suggestVector("title || ' ' || COALESCE(category, '')"),
+	}
+
+	Sources = []Source{Courses, Posts, Programs, Projects, Mentors, AllBlogPosts}
+)
+
+func weighted(weight, expr string) string {
+	return fmt.Sprintf("setweight(to_tsvector('%s', COALESCE(%s, '')), '%s')", Config, expr, weight)
+}
+
+func suggestVector(expr string) string {
+	return fmt.Sprintf("to_tsvector('%s', %s)", Config, expr)
+}
+
+// Query is the tsquery built from user input; websearch syntax supports quotes, OR and -
+func Query() string {
+	return "websearch_to_tsquery('" + Config + "', ?)"
+}
+
+// Match filters a query on the source's search_vector
+func (s Source) Match(term string) clause.Expr {
+	return gorm.Expr(s.Table+".search_vector @@ "+Query(), term)
+}
+
+// Rank orders results by relevance, best first
+func (s Source) Rank(term string) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:  "ts_rank_cd(" + s.Table + ".search_vector, " + Query() + ") DESC",
+		Vars: []interface{}{term},
+	}}
+}
+
+// PrefixQuery turns user input into a to_tsquery expression where every word must match
+// and the last one may be a prefix, e.g. "khoa h" becomes "khoa & h:*". Punctuation is
+// dropped so input can't inject tsquery operators. It returns "" when no word is left.
+func PrefixQuery(input string) string {
+	words := strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
+
+// SuggestMatch filters a query on the source's title and tag vector with a PrefixQuery
+func (s Source) SuggestMatch(prefix string) clause.Expr {
+	return gorm.Expr(s.Suggest+" @@ to_tsquery('"+Config+"', ?)", prefix)
+}
+
+// HeadlineSQL selects the highlighted snippet of the source; it takes the search term as its only parameter
+func (s Source) HeadlineSQL() string {
+	return "ts_headline('" + Config + "', " + s.Snippet + ", " + Query() +
+		", 'StartSel=" + markStart + ", StopSel=" + markStop + ", MaxWords=35, MinWords=15, MaxFragments=2')"
+}
+
+// Snippets returns highlighted snippets keyed by row ID for the given IDs. It runs
+// ts_headline only for the rows of the current page.
+func (s Source) Snippets(db *gorm.DB, term string, ids interface{}) (map[string]string, error) {
+	var rows []struct {
+		ID      string
+		Snippet string
+	}
+	if err := db.Table(s.Table).
+		Select("id::text AS id, "+s.HeadlineSQL()+" AS snippet", term).
+		Where("id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	snippets := make(map[string]string, len(rows))
+	for _, r := range rows {
+		snippets[r.ID] = Highlight(r.Snippet)
+	}
+	return snippets, nil
+}
+
+// Highlight escapes a raw ts_headline result and turns its delimiters into <mark> tags
+func Highlight(raw string) string {
+	escaped := html.EscapeString(raw)
+	escaped = strings.ReplaceAll(escaped, markStart, "<mark>")
+	return strings.ReplaceAll(escaped, markStop, "</mark>")
+}
+
+// Migrate installs the search configuration and the generated, GIN-indexed search_vector
+// columns. Every statement is idempotent.
+func Migrate(db *gorm.DB) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS unaccent`,
+		`DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = '` + Config + `') THEN
+				CREATE TEXT SEARCH CONFIGURATION ` + Config + ` (COPY = simple);
+				ALTER TEXT SEARCH CONFIGURATION ` + Config + `
+					ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+			END IF;
+		END $$`,
+		// array_to_string is only STABLE, generated columns need an IMMUTABLE expression
+		`CREATE OR REPLACE FUNCTION msc_immutable_array_to_string(text[]) RETURNS text
+			LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$ SELECT array_to_string($1, ' ') $$`,
+	}
+	for _, src := range Sources {
+		statements = append(statements,
+			"ALTER TABLE "+src.Table+" ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS ("+src.Vector+") STORED",
+			"CREATE INDEX IF NOT EXISTS idx_"+src.Table+"_search_vector ON "+src.Table+" USING GIN (search_vector)",
+			// Expression index; SuggestMatch must use the identical expression to hit it
+			"CREATE INDEX IF NOT EXISTS idx_"+src.Table+"_search_suggest ON "+src.Table+" USING GIN (("+src.Suggest+"))",
+		)
+	}
+
+	for _, stmt := range statements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package trash
+
+import (
+	"errors"
+	"time"
+
+	"msc-backend-api/internal/blog"
+	"msc-backend-api/internal/booking"
+	"msc-backend-api/internal/comments"
+	"msc-backend-api/internal/engagement"
+	"msc-backend-api/internal/i18n"
+	"msc-backend-api/internal/mentors"
+	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/redirects"
+	"msc-backend-api/internal/reviews"
+	"msc-backend-api/internal/taxonomy"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrUnknownType = errors.New("unknown content type")
+
+// ErrPostEntry is returned when restoring a blog entry that mirrors a post: it is
+// trashed because its post left the published state, and comes back with the post
+var ErrPostEntry = errors.New("blog entry is managed by its post")
+
+// Type describes a soft-deletable content type
+type Type struct {
+	Name        string
+	Table       string
+	TitleColumn string
+	SlugColumn  string // empty when the type has no slug
+	UUIDKey     bool   // false for serial primary keys
+	EntityType  string // entity type of revisions, status history and taxonomy links
+	newModel    func() interface{}
+}
+
+// Model returns a fresh pointer to the type's model
+func (t Type) Model() interface{} {
+	return t.newModel()
+}
+
+// Types lists every content type that supports trash and restore, keyed by its URL name
+var Types = map[string]Type{
+	"courses":      {Name: "courses", Table: "courses", TitleColumn: "title", SlugColumn: "slug", UUIDKey: true, EntityType: models.EntityTypeCourse, newModel: func() interface{} { return &models.Course{} }},
+	"posts":        {Name: "posts", Table: "posts", TitleColumn: "title", SlugColumn: "slug", UUIDKey: true, EntityType: models.EntityTypePost, newModel: func() interface{} { return &models.Post{} }},
+	"mentors":      {Name: "mentors", Table: "mentors", TitleColumn: "name", SlugColumn: "slug", UUIDKey: true, EntityType: models.EntityTypeMentor, newModel: func() interface{} { return &models.Mentor{} }},
+	"mscers":       {Name: "mscers", Table: "mscers", TitleColumn: "name", SlugColumn: "slug", UUIDKey: true, EntityType: models.EntityTypeMSCer, newModel: func() interface{} { return &models.MSCer{} }},
+	"projects":     {Name: "projects", Table: "projects", TitleColumn: "title", SlugColumn: "slug", UUIDKey: true, EntityType: models.EntityTypeProject, newModel: func() interface{} { return &models.Project{} }},
+	"programs":     {Name: "programs", Table: "programs", TitleColumn: "title", SlugColumn: "slug", UUIDKey: true, EntityType: models.EntityTypeProgram, newModel: func() interface{} { return &models.Program{} }},
+	"allblogposts": {Name: "allblogposts", Table: "allblogposts", TitleColumn: "title", SlugColumn: "slug", EntityType: models.EntityTypeBlogPost, newModel: func() interface{} { return &models.AllBlogPost{} }},
+}
+
+// Lookup returns the registered type for a URL name
+func Lookup(name string) (Type, error) {
+	t, ok := Types[name]
+	if !ok {
+		return Type{}, ErrUnknownType
+	}
+	return t, nil
+}
+
+// Item is the trash listing entry of a deleted row
+type Item struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// List returns a page of trashed rows of a type, most recently deleted first
+func List(db *gorm.DB, t Type, limit, offset int) ([]Item, int64, error) {
+	query := db.Unscoped().Table(t.Table).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	slug := "''"
+	if t.SlugColumn != "" {
+		slug = t.SlugColumn
+	}
+
+	items := []Item{}
+	err := query.
+		Select("id::text AS id, " + t.TitleColumn + " AS title, " + slug + " AS slug, deleted_at").
+		Order("deleted_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&items).Error
+	return items, total, err
+}
+
+// Restore brings a trashed row back. It returns gorm.ErrRecordNotFound when the row
+// does not exist or is not in the trash, and ErrPostEntry for blog entries of posts.
+func Restore(db *gorm.DB, t Type, id string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if t.EntityType == models.EntityTypeBlogPost {
+			var managed int64
+			if err := tx.Unscoped().Model(&models.AllBlogPost{}).
+				Where("id = ? AND post_id IS NOT NULL", id).
+				Count(&managed).Error; err != nil {
+				return err
+			}
+			if managed > 0 {
+				return ErrPostEntry
+			}
+		}
+
+		result := tx.Unscoped().Model(t.Model()).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		// A restored published post is back on the public blog
+		if t.EntityType == models.EntityTypePost {
+			return blog.SyncPost(tx, uuid.MustParse(id))
+		}
+		return nil
+	})
+}
+
+// Purge permanently deletes a trashed row together with its revisions, status history
+// and taxonomy links
+func Purge(db *gorm.DB, t Type, id string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(t.Model())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return purgeHistory(tx, t, []string{id})
+	})
+}
+
+// PurgeExpired permanently deletes every row of every type that has been in the
+// trash since before cutoff and returns the number of rows removed
+func PurgeExpired(db *gorm.DB, cutoff time.Time) (int64, error) {
+	var purged int64
+	for _, t := range Types {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var ids []string
+			if err := tx.Unscoped().Table(t.Table).
+				Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+				Pluck("id::text", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+
+			result := tx.Unscoped().Where("id IN ?", ids).Delete(t.Model())
+			if result.Error != nil {
+				return result.Error
+			}
+			purged += result.RowsAffected
+			return purgeHistory(tx, t, ids)
+		})
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// ValidID reports whether id has the key format of the type
+func (t Type) ValidID(id string) bool {
+	if t.UUIDKey {
+		_, err := uuid.Parse(id)
+		return err == nil
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return id != ""
+}
+
+func purgeHistory(tx *gorm.DB, t Type, ids []string) error {
+	if err := taxonomy.Unlink(tx, t.EntityType, ids); err != nil {
+		return err
+	}
+	if err := engagement.Unlink(tx, t.EntityType, ids); err != nil {
+		return err
+	}
+	if err := redirects.Unlink(tx, t.EntityType, ids); err != nil {
+		return err
+	}
+	if err := i18n.Unlink(tx, t.EntityType, ids); err != nil {
+		return err
+	}
+	if err := reviews.Unlink(tx, t.EntityType, ids); err != nil {
+		return err
+	}
+	if t.EntityType == models.EntityTypeBlogPost {
+		if err := comments.Unlink(tx, ids); err != nil {
+			return err
+		}
+	}
+	if t.EntityType == models.EntityTypeProject {
+		if err := mentors.UnlinkProjects(tx, ids); err != nil {
+			return err
+		}
+	}
+	if t.EntityType == models.EntityTypeCourse || t.EntityType == models.EntityTypeProgram {
+		if err := mentors.UnlinkAssignments(tx, t.EntityType, ids); err != nil {
+			return err
+		}
+	}
+	// Foreign keys are not created at migration, so the lesson cascade never runs
+	if t.EntityType == models.EntityTypeCourse {
+		if err := tx.Unscoped().Where("course_id IN ?", ids).Delete(&models.Lesson{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("course_id IN ?", ids).Delete(&models.Enrollment{}).Error; err != nil {
+			return err
+		}
+	}
+	// Alumni keep their profile when the program they took is purged
+	if t.EntityType == models.EntityTypeProgram {
+		if err := tx.Unscoped().Model(&models.MSCer{}).Where("program_id IN ?", ids).Update("program_id", nil).Error; err != nil {
+			return err
+		}
+	}
+	if t.EntityType == models.EntityTypeMentor {
+		if err := mentors.Unlink(tx, ids); err != nil {
+			return err
+		}
+		if err := booking.Unlink(tx, ids); err != nil {
+			return err
+		}
+	}
+	// Purged posts leave the public blog for good
+	if t.EntityType == models.EntityTypePost {
+		for _, id := range ids {
+			if err := blog.SyncPost(tx, uuid.MustParse(id)); err != nil {
+				return err
+			}
+		}
+	}
+	// Revisions and status history are keyed by UUID
+	if !t.UUIDKey {
+		return nil
+	}
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", t.EntityType, ids).Delete(&models.Revision{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("entity_type = ? AND entity_id IN ?", t.EntityType, ids).Delete(&models.StatusTransition{}).Error
+}
@@ -0,0 +1,111 @@
+// Package uploads validates and stores uploaded files. The upload endpoint and the
+// article importer share it so imported images are stored like any other upload.
+package uploads
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"msc-backend-api/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidType is returned for a file whose extension does not match its type
+	ErrInvalidType = errors.New("invalid file type")
+	// ErrTooLarge is returned for a file larger than MaxSize allows
+	ErrTooLarge = errors.New("file too large")
+)
+
+var extensions = map[string][]string{
+	"image":    {".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg"},
+	"video":    {".mp4", ".avi", ".mov", ".wmv", ".flv", ".webm"},
+	"document": {".pdf", ".doc", ".docx", ".txt", ".rtf"},
+}
+
+// ValidType reports whether filename has an extension allowed for fileType
+// (image, video or document)
+func ValidType(fileType, filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, allowed := range extensions[fileType] {
+		if allowed == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSize returns the largest file accepted for fileType, in bytes
+func MaxSize(fileType string) int64 {
+	switch fileType {
+	case "image":
+		return 5 * 1024 * 1024 // 5MB
+	case "video":
+		return 100 * 1024 * 1024 // 100MB
+	case "document":
+		return 10 * 1024 * 1024 // 10MB
+	default:
+		return 1 * 1024 * 1024 // 1MB
+	}
+}
+
+// Store keeps uploaded files in a directory that the API serves under /uploads
+type Store struct {
+	dir     string
+	baseURL string
+}
+
+func NewStore(cfg *config.Config) *Store {
+	return &Store{dir: cfg.UploadDir, baseURL: cfg.UploadURL}
+}
+
+// Dir returns the directory files are stored in
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save validates and stores a file under a new unique name and returns its public URL
+// and stored name. filename is the original name, used for the extension only.
+func (s *Store) Save(r io.Reader, fileType, filename string) (string, string, error) {
+	if !ValidType(fileType, filename) {
+		return "", "", ErrInvalidType
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	name := fmt.Sprintf("%s_%d%s", uuid.New().String(), time.Now().Unix(), ext)
+	dir := filepath.Join(s.dir, fileType)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
+	}
+
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", "", err
+	}
+	// Read one byte past the limit to detect larger files
+	written, err := io.Copy(f, io.LimitReader(r, MaxSize(fileType)+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > MaxSize(fileType) {
+		err = ErrTooLarge
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", "", err
+	}
+
+	return fmt.Sprintf("%s/%s/%s", s.baseURL, fileType, name), name, nil
+}
+
+// Remove deletes a stored file by the name Save returned
+func (s *Store) Remove(fileType, name string) error {
+	return os.Remove(filepath.Join(s.dir, fileType, filepath.Base(name)))
+}
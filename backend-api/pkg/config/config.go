@@ -2,6 +2,9 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -11,10 +14,32 @@ type Config struct {
 	Environment      string
 	AdminURL         string
 	FrontendURL      string
+	APIURL           string // public base URL of this API, used in links the API hands out
 	CloudinaryName   string
 	CloudinaryKey    string
 	CloudinarySecret string
 	Port             string
+
+	UploadDir string // directory uploaded files are stored in
+	UploadURL string // public URL the upload directory is served at
+
+	SchedulerInterval time.Duration // how often scheduled publishing runs
+	TrashRetention    time.Duration // how long soft-deleted content is kept before purging
+	ViewWindow        time.Duration // repeat views by the same visitor within this window count once
+	CounterFlush      time.Duration // how often buffered view and like counts are written
+
+	CommentRateLimit   int           // comments one user may post per CommentRateWindow
+	CommentRateWindow  time.Duration // window of the comment rate limit
+	CommentBannedWords []string      // comments containing these words are marked as spam
+
+	FeedFullContent bool // feeds carry the full article instead of the excerpt only
+	FeedItems       int  // number of articles per feed
+
+	SMTPHost     string // mail server; emails are only logged when empty
+	SMTPPort     int
+	SMTPUser     string
+	SMTPPassword string
+	MailFrom     string // sender address of outgoing email
 }
 
 func Load() *Config {
@@ -25,10 +50,32 @@ func Load() *Config {
 		Environment:      getEnv("ENVIRONMENT", "development"),
 		AdminURL:         getEnv("ADMIN_URL", "http://localhost:3001"),
 		FrontendURL:      getEnv("FRONTEND_URL", "http://localhost:3000"),
+		APIURL:           strings.TrimRight(getEnv("API_URL", "http://localhost:8080"), "/"),
 		CloudinaryName:   getEnv("CLOUDINARY_NAME", ""),
 		CloudinaryKey:    getEnv("CLOUDINARY_KEY", ""),
 		CloudinarySecret: getEnv("CLOUDINARY_SECRET", ""),
 		Port:             getEnv("PORT", "8080"),
+
+		UploadDir: getEnv("UPLOAD_DIR", "uploads"),
+		UploadURL: strings.TrimRight(getEnv("UPLOAD_URL", "https://api.msc.edu.vn/uploads"), "/"),
+
+		SchedulerInterval: getEnvDuration("SCHEDULER_INTERVAL", time.Minute),
+		TrashRetention:    getEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+		ViewWindow:        getEnvDuration("VIEW_WINDOW", 30*time.Minute),
+		CounterFlush:      getEnvDuration("COUNTER_FLUSH_INTERVAL", 10*time.Second),
+
+		CommentRateLimit:   getEnvInt("COMMENT_RATE_LIMIT", 5),
+		CommentRateWindow:  getEnvDuration("COMMENT_RATE_WINDOW", 10*time.Minute),
+		CommentBannedWords: getEnvList("COMMENT_BANNED_WORDS"),
+
+		FeedFullContent: getEnvBool("FEED_FULL_CONTENT", true),
+		FeedItems:       getEnvInt("FEED_ITEMS", 20),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 587),
+		SMTPUser:     getEnv("SMTP_USER", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "MSC.EDU.VN <no-reply@msc.edu.vn>"),
 	}
 }
 
@@ -38,3 +85,41 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated variable, dropping empty entries
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -7,7 +7,10 @@ import (
 	"strings"
 	"time"
 
+	"msc-backend-api/internal/mentors"
 	"msc-backend-api/internal/models"
+	"msc-backend-api/internal/taxonomy"
+	"msc-backend-api/internal/textsearch"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -52,7 +55,7 @@ func Initialize(supabaseURL string) (*gorm.DB, error) {
 	}
 
 	// Auto-migrate tables with error handling
-	models := []interface{}{
+	tables := []interface{}{
 		&models.Role{},
 		&models.User{},
 		&models.Course{},
@@ -60,9 +63,32 @@ func Initialize(supabaseURL string) (*gorm.DB, error) {
 		&models.Mentor{},
 		&models.Program{}, // Add Program model
 		&models.Project{}, // Add Project model
+		&models.Revision{},
+		&models.StatusTransition{},
+		&models.Category{},
+		&models.Tag{},
+		&models.ContentCategory{},
+		&models.ContentTag{},
+		&models.ContentLike{},
+		&models.Comment{},
+		&models.Notification{},
+		&models.SlugHistory{},
+		&models.Translation{},
+		&models.MentorEducation{},
+		&models.MentorWork{},
+		&models.MentorPublication{},
+		&models.MentorAward{},
+		&models.MentorResearchArea{},
+		&models.MentorAvailability{},
+		&models.Booking{},
+		&models.MentorCalendarFeed{},
+		&models.ProjectMentor{},
+		&models.MentorAssignment{},
+		&models.Review{},
+		&models.MSCer{},
 	}
 
-	for _, model := range models {
+	for _, model := range tables {
 		if err := db.AutoMigrate(model); err != nil {
 			// Check if error is about existing table/constraint or non-existent constraint
 			errorMsg := err.Error()
@@ -75,6 +101,32 @@ func Initialize(supabaseURL string) (*gorm.DB, error) {
 		}
 	}
 
+	// allblogposts is created by init.sql; only add the columns the API relies on
+	if err := ensureColumns(db, &models.AllBlogPost{}, "UpdatedAt", "DeletedAt", "PostID"); err != nil {
+		return nil, fmt.Errorf("failed to migrate allblogposts: %w", err)
+	}
+	if !db.Migrator().HasIndex(&models.AllBlogPost{}, "PostID") {
+		if err := db.Migrator().CreateIndex(&models.AllBlogPost{}, "PostID"); err != nil {
+			return nil, fmt.Errorf("failed to migrate allblogposts: %w", err)
+		}
+	}
+
+	if err := textsearch.Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to set up full-text search: %w", err)
+	}
+
+	if err := taxonomy.Backfill(db); err != nil {
+		return nil, fmt.Errorf("failed to backfill categories: %w", err)
+	}
+
+	if err := mentors.BackfillSlugs(db); err != nil {
+		return nil, fmt.Errorf("failed to backfill mentor slugs: %w", err)
+	}
+
+	if err := mentors.BackfillProjectMentors(db); err != nil {
+		return nil, fmt.Errorf("failed to link project mentors: %w", err)
+	}
+
 	// Initialize default roles if they don't exist
 	if err := initializeDefaultRoles(db); err != nil {
 		return nil, fmt.Errorf("failed to initialize default roles: %w", err)
@@ -99,6 +151,20 @@ func GetFreshSession(db *gorm.DB) *gorm.DB {
 	})
 }
 
+// ensureColumns adds the given model fields as columns when the table lacks them
+func ensureColumns(db *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if db.Migrator().HasColumn(model, field) {
+			continue
+		}
+		if err := db.Migrator().AddColumn(model, field); err != nil {
+			return err
+		}
+		log.Printf("Added column %s to %T", field, model)
+	}
+	return nil
+}
+
 func initializeDefaultRoles(db *gorm.DB) error {
 	roles := []models.Role{
 		{Name: "admin"},
@@ -0,0 +1,144 @@
+// Package ics writes iCalendar (RFC 5545) files: meeting invitations sent by email and
+// calendar feeds that calendar apps subscribe to.
+package ics
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Methods of a calendar sent as an invitation (RFC 5546)
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Event statuses
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// maxLineOctets is the longest content line allowed before folding
+const maxLineOctets = 75
+
+const timeFormat = "20060102T150405Z"
+
+// Person is an organizer or attendee of an event
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is a single calendar event. UID must stay the same for every version of the
+// event, and Sequence must grow each time it is rescheduled or cancelled.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Status      string
+	Organizer   *Person
+	Attendees   []Person
+	Updated     time.Time
+}
+
+// Calendar is a set of events. Method is empty for feeds and set for invitations.
+type Calendar struct {
+	Name   string
+	Method string
+	Events []Event
+}
+
+// Bytes renders the calendar as an .ics file
+func (cal Calendar) Bytes() []byte {
+	var b bytes.Buffer
+	line := func(name, value string) {
+		writeLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//MSC.EDU.VN//MSC Backend API//VI")
+	line("CALSCALE", "GREGORIAN")
+	if cal.Method != "" {
+		line("METHOD", cal.Method)
+	}
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+
+	for _, e := range cal.Events {
+		stamp := e.Updated
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("SEQUENCE", strconv.Itoa(e.Sequence))
+		line("DTSTAMP", stamp.UTC().Format(timeFormat))
+		line("DTSTART", e.Start.UTC().Format(timeFormat))
+		line("DTEND", e.End.UTC().Format(timeFormat))
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", escape(e.Location))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		if e.Status != "" {
+			line("STATUS", e.Status)
+		}
+		if e.Organizer != nil {
+			writeLine(&b, "ORGANIZER"+commonName(*e.Organizer)+":mailto:"+e.Organizer.Email)
+		}
+		for _, a := range e.Attendees {
+			writeLine(&b, "ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED"+commonName(a)+":mailto:"+a.Email)
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func commonName(p Person) string {
+	if p.Name == "" {
+		return ""
+	}
+	return `;CN="` + strings.NewReplacer(`"`, "'", "\r", "", "\n", " ").Replace(p.Name) + `"`
+}
+
+// escape escapes a TEXT value
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "").Replace(text)
+}
+
+// writeLine writes a content line, folding it after 75 octets without splitting a
+// UTF-8 sequence
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
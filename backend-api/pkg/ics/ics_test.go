@@ -0,0 +1,125 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"a;b,c", `a\;b\,c`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines", `two\nlines`},
+		{"crlf\r\nline", `crlf\nline`},
+		{"stray\rreturn", "strayreturn"},
+		{`\n literal`, `\\n literal`},
+		{"Tiếng Việt: ổn, đúng", `Tiếng Việt: ổn\, đúng`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Mentoring"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"76 octets", "SUMMARY:" + strings.Repeat("a", 68)},
+		{"several folds", "DESCRIPTION:" + strings.Repeat("abcdefghij", 30)},
+		{"multi-byte at the fold", "SUMMARY:" + strings.Repeat("a", 66) + "ệ" + strings.Repeat("ư", 40)},
+		{"only multi-byte", "SUMMARY:" + strings.Repeat("Việt ", 40)},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			writeLine(&b, tt.line)
+			out := b.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line does not end with CRLF: %q", out)
+			}
+			physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, p := range physical {
+				if len(p) > maxLineOctets {
+					t.Errorf("physical line %d has %d octets", i, len(p))
+				}
+				if i > 0 && !strings.HasPrefix(p, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, p)
+				}
+				if !utf8.ValidString(p) {
+					t.Errorf("physical line %d splits a UTF-8 sequence: %q", i, p)
+				}
+			}
+			if len(tt.line) <= maxLineOctets && len(physical) != 1 {
+				t.Errorf("line of %d octets was folded into %d lines", len(tt.line), len(physical))
+			}
+
+			// Unfolding (RFC 5545 3.1) restores the line
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolded line = %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestCalendarBytes(t *testing.T) {
+	start := time.Date(2026, 10, 20, 9, 0, 0, 0, time.FixedZone("ICT", 7*3600))
+	cal := Calendar{
+		Method: MethodRequest,
+		Events: []Event{{
+			UID:       "b1@msc.edu.vn",
+			Sequence:  2,
+			Start:     start,
+			End:       start.Add(time.Hour),
+			Summary:   "Mentoring: An, Bình",
+			Status:    StatusConfirmed,
+			Organizer: &Person{Name: `Dr. "Minh"`, Email: "minh@msc.edu.vn"},
+			Attendees: []Person{{Name: "An", Email: "an@example.com"}},
+			Updated:   start,
+		}},
+	}
+	// Compare unfolded content lines
+	out := strings.ReplaceAll(string(cal.Bytes()), "\r\n ", "")
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"METHOD:REQUEST\r\n",
+		"UID:b1@msc.edu.vn\r\n",
+		"SEQUENCE:2\r\n",
+		"DTSTART:20261020T020000Z\r\n",
+		"DTEND:20261020T030000Z\r\n",
+		"SUMMARY:Mentoring: An\\, Bình\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"ORGANIZER;CN=\"Dr. 'Minh'\":mailto:minh@msc.edu.vn\r\n",
+		"ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;CN=\"An\":mailto:an@example.com\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar is missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+		t.Error("calendar has a bare LF line ending")
+	}
+
+	feed := string(Calendar{Name: "Feed", Events: cal.Events}.Bytes())
+	if strings.Contains(feed, "METHOD:") {
+		t.Error("feed without a method has a METHOD line")
+	}
+	if !strings.Contains(feed, "X-WR-CALNAME:Feed\r\n") {
+		t.Error("feed is missing its name")
+	}
+}
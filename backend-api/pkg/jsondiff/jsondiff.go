@@ -0,0 +1,107 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Change operations
+const (
+	OpAdded   = "added"
+	OpRemoved = "removed"
+	OpChanged = "changed"
+)
+
+// Change describes a single difference between two JSON documents
+type Change struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// Diff compares two JSON documents and returns the changes needed to turn a into b.
+// Objects are compared key by key and arrays index by index, so edits deep inside
+// a Tiptap document are reported at the node that changed, e.g. "content.content[2].content[0].text".
+func Diff(a, b []byte) ([]Change, error) {
+	var av, bv interface{}
+	if err := unmarshal(a, &av); err != nil {
+		return nil, fmt.Errorf("invalid source document: %w", err)
+	}
+	if err := unmarshal(b, &bv); err != nil {
+		return nil, fmt.Errorf("invalid target document: %w", err)
+	}
+
+	changes := []Change{}
+	walk("", av, bv, &changes)
+	return changes, nil
+}
+
+func unmarshal(data []byte, v *interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+func walk(path string, a, b interface{}, changes *[]Change) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(av)+len(bv))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		for k := range bv {
+			if _, seen := av[k]; !seen {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			oldVal, inA := av[k]
+			newVal, inB := bv[k]
+			switch {
+			case !inA:
+				*changes = append(*changes, Change{Path: child, Op: OpAdded, New: newVal})
+			case !inB:
+				*changes = append(*changes, Change{Path: child, Op: OpRemoved, Old: oldVal})
+			default:
+				walk(child, oldVal, newVal, changes)
+			}
+		}
+		return
+
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(av):
+				*changes = append(*changes, Change{Path: child, Op: OpAdded, New: bv[i]})
+			case i >= len(bv):
+				*changes = append(*changes, Change{Path: child, Op: OpRemoved, Old: av[i]})
+			default:
+				walk(child, av[i], bv[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Path: path, Op: OpChanged, Old: a, New: b})
+	}
+}
@@ -0,0 +1,223 @@
+// Package mailer sends email through an SMTP server. Without a configured server,
+// messages are written to the log instead so development setups need no mail server.
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"msc-backend-api/pkg/config"
+)
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Filename    string
+	ContentType string // e.g. "text/calendar; charset=utf-8; method=REQUEST"
+	Data        []byte
+}
+
+// Message is a plain-text email with optional attachments
+type Message struct {
+	To          []string
+	Subject     string
+	Text        string
+	Attachments []Attachment
+}
+
+type Mailer struct {
+	host     string
+	port     int
+	user     string
+	password string
+	from     string
+
+	pending sync.WaitGroup // messages handed to SendAsync that are still being sent
+}
+
+func New(cfg *config.Config) *Mailer {
+	return &Mailer{
+		host:     cfg.SMTPHost,
+		port:     cfg.SMTPPort,
+		user:     cfg.SMTPUser,
+		password: cfg.SMTPPassword,
+		from:     cfg.MailFrom,
+	}
+}
+
+// Send delivers msg. Recipients without an address are skipped.
+func (m *Mailer) Send(msg Message) error {
+	var to []string
+	for _, addr := range msg.To {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	if len(to) == 0 {
+		return nil
+	}
+	msg.To = to
+
+	if m.host == "" {
+		log.Printf("Mail not sent (SMTP_HOST not set): %q to %s", msg.Subject, strings.Join(to, ", "))
+		return nil
+	}
+
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid MAIL_FROM: %w", err)
+	}
+	body, err := m.compose(msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.password, m.host)
+	}
+	if m.port != 465 {
+		// SendMail upgrades the connection with STARTTLS when the server offers it
+		return smtp.SendMail(addr, auth, from.Address, to, body)
+	}
+
+	// Port 465 expects TLS from the start
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: m.host})
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// SendAsync sends msg in the background, logging failures. Requests use it so a slow
+// or unreachable mail server never delays the response.
+func (m *Mailer) SendAsync(msg Message) {
+	m.pending.Add(1)
+	go func() {
+		defer m.pending.Done()
+		if err := m.Send(msg); err != nil {
+			log.Printf("Failed to send mail %q: %v", msg.Subject, err)
+		}
+	}()
+}
+
+// Wait blocks until every message handed to SendAsync has been sent or has failed.
+// Call it on shutdown, after the HTTP server has stopped accepting requests.
+func (m *Mailer) Wait() {
+	m.pending.Wait()
+}
+
+// compose builds the MIME message: a quoted-printable text part followed by the
+// base64-encoded attachments
+func (m *Mailer) compose(msg Message) ([]byte, error) {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+
+	from := m.from
+	if addr, err := mail.ParseAddress(m.from); err == nil {
+		from = addr.String() // encodes a non-ASCII display name
+	}
+	header("From", from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomID()+"@"+domain(m.from)+">")
+	header("MIME-Version", "1.0")
+
+	boundary := "msc-" + randomID()
+	if len(msg.Attachments) > 0 {
+		header("Content-Type", `multipart/mixed; boundary="`+boundary+`"`)
+		b.WriteString("\r\n--" + boundary + "\r\n")
+	}
+
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(&b)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(msg.Text, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	b.WriteString("\r\n")
+
+	for _, a := range msg.Attachments {
+		b.WriteString("--" + boundary + "\r\n")
+		header("Content-Type", a.ContentType+`; name="`+a.Filename+`"`)
+		header("Content-Disposition", `attachment; filename="`+a.Filename+`"`)
+		header("Content-Transfer-Encoding", "base64")
+		b.WriteString("\r\n")
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			b.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		b.WriteString(encoded + "\r\n")
+	}
+	if len(msg.Attachments) > 0 {
+		b.WriteString("--" + boundary + "--\r\n")
+	}
+	return b.Bytes(), nil
+}
+
+func randomID() string {
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// domain returns the domain of the sender address, used in Message-IDs
+func domain(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			return addr.Address[at+1:]
+		}
+	}
+	return "localhost"
+}
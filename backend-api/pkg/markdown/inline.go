@@ -0,0 +1,249 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"msc-backend-api/pkg/tiptap"
+)
+
+var (
+	// [text](href "title") and ![alt](src "title"); the title is ignored for links
+	linkTail     = regexp.MustCompile(`^\(\s*<?([^\s()<>]*(?:\([^\s()]*\)[^\s()<>]*)*)>?(?:\s+["'(](.*?)["')])?\s*\)`)
+	autolink     = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+	escapable    = "\\`*_{}[]()#+-.!~<>|\""
+	emphasisRuns = []struct {
+		delim string
+		mark  string
+	}{
+		{"**", "bold"}, {"__", "bold"}, {"~~", "strike"}, {"*", "italic"}, {"_", "italic"},
+	}
+)
+
+// parseInline converts inline Markdown into text nodes carrying marks, plus hard breaks
+// and images
+func parseInline(text string, marks []tiptap.Mark) []tiptap.Node {
+	var nodes []tiptap.Node
+	var plain strings.Builder
+
+	flush := func() {
+		if plain.Len() > 0 {
+			nodes = appendText(nodes, plain.String(), marks)
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			flush()
+			nodes = append(nodes, tiptap.Node{Type: "hardBreak"})
+			i += 2
+			continue
+
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(escapable, text[i+1]) >= 0:
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '\n':
+			// Two trailing spaces make a hard break; other line ends are spaces
+			if strings.HasSuffix(plain.String(), "  ") {
+				trimmed := strings.TrimRight(plain.String(), " ")
+				plain.Reset()
+				plain.WriteString(trimmed)
+				flush()
+				nodes = append(nodes, tiptap.Node{Type: "hardBreak"})
+			} else {
+				trimmed := strings.TrimRight(plain.String(), " ")
+				plain.Reset()
+				plain.WriteString(trimmed + " ")
+			}
+			i++
+			for i < len(text) && text[i] == ' ' {
+				i++
+			}
+			continue
+
+		case c == '`':
+			run := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:run]
+			if end := strings.Index(rest[run:], fence); end >= 0 {
+				flush()
+				code := strings.TrimSpace(strings.ReplaceAll(rest[run:run+end], "\n", " "))
+				nodes = appendText(nodes, code, withMark(marks, tiptap.Mark{Type: "code"}))
+				i += run + end + run
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if alt, tail, ok := bracketed(rest[1:]); ok {
+				if m := linkTail.FindStringSubmatch(tail); m != nil {
+					flush()
+					attrs := map[string]interface{}{"src": m[1]}
+					if alt != "" {
+						attrs["alt"] = alt
+					}
+					if m[2] != "" {
+						attrs["title"] = m[2]
+					}
+					nodes = append(nodes, tiptap.Node{Type: "image", Attrs: attrs})
+					i += len(rest) - len(tail) + len(m[0])
+					continue
+				}
+			}
+
+		case c == '[':
+			if label, tail, ok := bracketed(rest); ok {
+				if m := linkTail.FindStringSubmatch(tail); m != nil {
+					flush()
+					link := tiptap.Mark{Type: "link", Attrs: map[string]interface{}{"href": m[1]}}
+					nodes = append(nodes, parseInline(label, withMark(marks, link))...)
+					i += len(rest) - len(tail) + len(m[0])
+					continue
+				}
+			}
+
+		case c == '<':
+			if m := autolink.FindStringSubmatch(rest); m != nil {
+				flush()
+				link := tiptap.Mark{Type: "link", Attrs: map[string]interface{}{"href": m[1]}}
+				nodes = appendText(nodes, m[1], withMark(marks, link))
+				i += len(m[0])
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if inner, length, mark, ok := emphasis(text, i); ok {
+				flush()
+				nodes = append(nodes, parseInline(inner, withMark(marks, tiptap.Mark{Type: mark}))...)
+				i += length
+				continue
+			}
+		}
+
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+
+	// Drop the space a trailing line end left behind
+	if n := len(nodes); n > 0 && nodes[n-1].Type == "text" {
+		nodes[n-1].Text = strings.TrimRight(nodes[n-1].Text, " ")
+		if nodes[n-1].Text == "" {
+			nodes = nodes[:n-1]
+		}
+	}
+	return nodes
+}
+
+// bracketed reads "[...]" at the start of s, allowing nested brackets, and returns the
+// text inside and what follows
+func bracketed(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", "", false
+	}
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return s[1:i], s[i+1:], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// emphasis matches a delimited run such as **bold** at text[i]. Underscores only count
+// at word boundaries, so snake_case words stay intact.
+func emphasis(text string, i int) (string, int, string, bool) {
+	rest := text[i:]
+	for _, run := range emphasisRuns {
+		if !strings.HasPrefix(rest, run.delim) {
+			continue
+		}
+		open := len(run.delim)
+		if open >= len(rest) || rest[open] == ' ' || rest[open] == '\n' {
+			continue
+		}
+		if run.delim[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+			continue
+		}
+
+		for from := open; ; {
+			end := strings.Index(rest[from:], run.delim)
+			if end < 0 {
+				break
+			}
+			end += from
+			after := end + len(run.delim)
+			closes := rest[end-1] != ' ' && rest[end-1] != '\n'
+			// A single * or _ must not be half of a double delimiter
+			if len(run.delim) == 1 && after < len(rest) && rest[after] == run.delim[0] {
+				closes = false
+				after++
+			}
+			// In "**a *b***" the single delimiter closes the inner emphasis first, so the
+			// double one is the last two characters of the run
+			if len(run.delim) == 2 && after < len(rest) && rest[after] == run.delim[0] &&
+				strings.Count(rest[open:end], run.delim[:1])%2 == 1 {
+				end++
+				after++
+			}
+			if run.delim[0] == '_' && after < len(rest) && isWordByte(rest[after]) {
+				closes = false
+			}
+			if closes && end > open {
+				return rest[open:end], end + len(run.delim), run.mark, true
+			}
+			from = after
+		}
+	}
+	return "", 0, "", false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+func withMark(marks []tiptap.Mark, mark tiptap.Mark) []tiptap.Mark {
+	combined := make([]tiptap.Mark, 0, len(marks)+1)
+	combined = append(combined, marks...)
+	return append(combined, mark)
+}
+
+// appendText adds a text node, merging it into the previous one when the marks match
+func appendText(nodes []tiptap.Node, text string, marks []tiptap.Mark) []tiptap.Node {
+	if text == "" {
+		return nodes
+	}
+	if n := len(nodes); n > 0 && nodes[n-1].Type == "text" && sameMarks(nodes[n-1].Marks, marks) {
+		nodes[n-1].Text += text
+		return nodes
+	}
+	return append(nodes, tiptap.Node{Type: "text", Text: text, Marks: marks})
+}
+
+func sameMarks(a, b []tiptap.Mark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type {
+			return false
+		}
+		if href, _ := a[i].Attrs["href"].(string); a[i].Type == "link" && href != b[i].Attrs["href"] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package markdown
+
+import "testing"
+
+func TestParseInline(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"bold", "**a** and __b__", "<p><strong>a</strong> and <strong>b</strong></p>"},
+		{"italic", "*a* and _b_", "<p><em>a</em> and <em>b</em></p>"},
+		{"strike", "~~gone~~", "<p><s>gone</s></p>"},
+		{"nested marks", "**bold *both***", "<p><strong>bold </strong><strong><em>both</em></strong></p>"},
+		{"adjacent marks", "**a***b*", "<p><strong>a</strong><em>b</em></p>"},
+		{"snake case kept", "a snake_case_name here", "<p>a snake_case_name here</p>"},
+		{"unclosed emphasis", "2 * 3 and *open", "<p>2 * 3 and *open</p>"},
+		{"space after delimiter", "** not bold**", "<p>** not bold**</p>"},
+		{"inline code", "use `a < b` here", "<p>use <code>a &lt; b</code> here</p>"},
+		{"code keeps delimiters", "`**x**`", "<p><code>**x**</code></p>"},
+		{"double backtick code", "``a ` b``", "<p><code>a ` b</code></p>"},
+		{"unclosed backtick", "a ` b", "<p>a ` b</p>"},
+		{"link", "[site](https://msc.edu.vn)", `<p><a href="https://msc.edu.vn" rel="noopener noreferrer nofollow">site</a></p>`},
+		{"link with title", `[a](/x "Title")`, `<p><a href="/x" rel="noopener noreferrer nofollow">a</a></p>`},
+		{"link with parens", "[w](https://x.y/a_(b))", `<p><a href="https://x.y/a_(b)" rel="noopener noreferrer nofollow">w</a></p>`},
+		{"bold link text", "[**b**](/x)", `<p><a href="/x" rel="noopener noreferrer nofollow"><strong>b</strong></a></p>`},
+		{"nested brackets in label", "[a [b]](/x)", `<p><a href="/x" rel="noopener noreferrer nofollow">a [b]</a></p>`},
+		{"brackets without link", "[a] b", "<p>[a] b</p>"},
+		{"autolink", "<https://x.y>", `<p><a href="https://x.y" rel="noopener noreferrer nofollow">https://x.y</a></p>`},
+		{"unsafe link dropped by renderer", "[x](javascript:alert(1))", "<p>x</p>"},
+		{"escapes", `\*not\* \[x\] \# \\`, `<p>*not* [x] # \</p>`},
+		{"two space hard break", "a  \nb", "<p>a<br>b</p>"},
+		{"backslash hard break", "a\\\nb", "<p>a<br>b</p>"},
+		{"soft break is a space", "a\n   b", "<p>a b</p>"},
+		{"vietnamese", "*Khóa học* **Đầu tư**", "<p><em>Khóa học</em> <strong>Đầu tư</strong></p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := html(tt.src); got != tt.want {
+				t.Errorf("Parse(%q)\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+// Package markdown converts Markdown, including the Markdown of MDX files, into Tiptap
+// documents so imported articles are stored like content written in the admin editor.
+//
+// It covers what articles use: headings, paragraphs, emphasis, strikethrough, inline
+// code, links, images, bullet and ordered lists, blockquotes, fenced code blocks, rules
+// and hard breaks. The import/export statements and JSX tags of MDX are dropped, and
+// other raw HTML is kept as text.
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"msc-backend-api/pkg/tiptap"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	rulePattern        = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	bulletPattern      = regexp.MustCompile(`^(\s*)([-*+])(\s+|$)`)
+	orderedPattern     = regexp.MustCompile(`^(\s*)(\d{1,9})[.)](\s+|$)`)
+	fencePattern       = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([\\w+#-]*)")
+	setextPattern      = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	mdxStatement       = regexp.MustCompile(`^(import|export)\s`)
+	jsxTag             = regexp.MustCompile(`^\s*</?[A-Z][\w.]*(\s[^>]*)?/?>\s*$`)
+	jsxComment         = regexp.MustCompile(`^\s*\{/\*.*\*/\}\s*$`)
+	frontMatterPattern = regexp.MustCompile(`(?s)\A(?:\x{FEFF})?---\r?\n(.*?)\r?\n---[ \t]*(?:\r?\n|\z)`)
+)
+
+// SplitFrontMatter separates the YAML front matter between --- lines at the top of a
+// file from the body. front is nil when the file has none.
+func SplitFrontMatter(src []byte) (front, body []byte) {
+	match := frontMatterPattern.FindSubmatchIndex(src)
+	if match == nil {
+		return nil, bytes.TrimPrefix(src, []byte("\xef\xbb\xbf"))
+	}
+	return src[match[2]:match[3]], src[match[1]:]
+}
+
+// Parse converts a Markdown body into a Tiptap document
+func Parse(src string) *tiptap.Node {
+	src = strings.ReplaceAll(strings.ReplaceAll(src, "\r\n", "\n"), "\t", "    ")
+	lines := strings.Split(src, "\n")
+
+	// MDX statements only appear at the top level, outside code
+	kept := lines[:0]
+	inFence := false
+	for _, line := range lines {
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+		}
+		if !inFence && (mdxStatement.MatchString(line) || jsxTag.MatchString(line) || jsxComment.MatchString(line)) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return &tiptap.Node{Type: "doc", Content: parseBlocks(kept)}
+}
+
+func parseBlocks(lines []string) []tiptap.Node {
+	var nodes []tiptap.Node
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case fencePattern.MatchString(line):
+			match := fencePattern.FindStringSubmatch(line)
+			fence := match[1]
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence
+			block := tiptap.Node{Type: "codeBlock"}
+			if match[2] != "" {
+				block.Attrs = map[string]interface{}{"language": match[2]}
+			}
+			if text := strings.Join(code, "\n"); text != "" {
+				block.Content = []tiptap.Node{{Type: "text", Text: text}}
+			}
+			nodes = append(nodes, block)
+
+		case headingPattern.MatchString(line):
+			match := headingPattern.FindStringSubmatch(line)
+			nodes = append(nodes, heading(len(match[1]), match[2]))
+			i++
+
+		case rulePattern.MatchString(line):
+			nodes = append(nodes, tiptap.Node{Type: "horizontalRule"})
+			i++
+
+		case strings.HasPrefix(strings.TrimLeft(line, " "), ">"):
+			var quoted []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimLeft(lines[i], " "), ">") {
+				text := strings.TrimPrefix(strings.TrimLeft(lines[i], " "), ">")
+				quoted = append(quoted, strings.TrimPrefix(text, " "))
+				i++
+			}
+			nodes = append(nodes, tiptap.Node{Type: "blockquote", Content: parseBlocks(quoted)})
+
+		case bulletPattern.MatchString(line) || orderedPattern.MatchString(line):
+			var list tiptap.Node
+			list, i = parseList(lines, i)
+			nodes = append(nodes, list)
+
+		default:
+			var paragraph []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(paragraph) == 0 || !startsBlock(lines[i]) && !setextPattern.MatchString(lines[i])) {
+				paragraph = append(paragraph, lines[i])
+				i++
+			}
+			// A paragraph underlined with === or --- is a heading
+			if i < len(lines) && setextPattern.MatchString(lines[i]) {
+				level := 2
+				if strings.Contains(lines[i], "=") {
+					level = 1
+				}
+				nodes = append(nodes, heading(level, strings.Join(paragraph, " ")))
+				i++
+				continue
+			}
+			nodes = append(nodes, paragraphs(strings.Join(paragraph, "\n"))...)
+		}
+	}
+	return nodes
+}
+
+// startsBlock reports whether a line interrupts a paragraph
+func startsBlock(line string) bool {
+	return fencePattern.MatchString(line) ||
+		headingPattern.MatchString(line) ||
+		rulePattern.MatchString(line) ||
+		strings.HasPrefix(strings.TrimLeft(line, " "), ">") ||
+		bulletPattern.MatchString(line) ||
+		orderedPattern.MatchString(line)
+}
+
+// parseList reads the list starting at lines[start] and returns it with the index of
+// the first line after it. Items continue on lines indented past the list marker.
+func parseList(lines []string, start int) (tiptap.Node, int) {
+	ordered := !bulletPattern.MatchString(lines[start])
+	pattern := bulletPattern
+	list := tiptap.Node{Type: "bulletList"}
+	if ordered {
+		pattern = orderedPattern
+		list.Type = "orderedList"
+		if n, _ := strconv.Atoi(orderedPattern.FindStringSubmatch(lines[start])[2]); n != 1 {
+			list.Attrs = map[string]interface{}{"start": n}
+		}
+	}
+	indent := len(pattern.FindStringSubmatch(lines[start])[1])
+
+	i := start
+	for i < len(lines) {
+		match := pattern.FindStringSubmatch(lines[i])
+		if match == nil || len(match[1]) != indent {
+			break
+		}
+		width := len(match[0])
+		item := []string{lines[i][width:]}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line ends the item unless an indented line follows
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) > indent {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) <= indent && startsBlock(line) {
+				break
+			}
+			item = append(item, strings.TrimPrefix(line, strings.Repeat(" ", min(width, leadingSpaces(line)))))
+			i++
+		}
+		list.Content = append(list.Content, tiptap.Node{Type: "listItem", Content: parseBlocks(item)})
+
+		// Blank lines between items keep the list going
+		next := i
+		for next < len(lines) && strings.TrimSpace(lines[next]) == "" {
+			next++
+		}
+		if next < len(lines) {
+			if m := pattern.FindStringSubmatch(lines[next]); m != nil && len(m[1]) == indent {
+				i = next
+			}
+		}
+	}
+	return list, i
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func heading(level int, text string) tiptap.Node {
+	h := tiptap.Node{
+		Type:    "heading",
+		Attrs:   map[string]interface{}{"level": level},
+		Content: withoutImages(parseInline(strings.TrimSpace(text), nil)),
+	}
+	if len(h.Content) == 0 {
+		return h
+	}
+	return trimParagraph(h)
+}
+
+// paragraphs turns paragraph text into paragraph nodes. Images are blocks in the
+// editor, so they split the paragraph they appear in.
+func paragraphs(text string) []tiptap.Node {
+	var nodes []tiptap.Node
+	current := tiptap.Node{Type: "paragraph"}
+	for _, n := range parseInline(text, nil) {
+		if n.Type != "image" {
+			current.Content = append(current.Content, n)
+			continue
+		}
+		if hasText(current.Content) {
+			nodes = append(nodes, trimParagraph(current))
+		}
+		nodes = append(nodes, n)
+		current = tiptap.Node{Type: "paragraph"}
+	}
+	if hasText(current.Content) {
+		nodes = append(nodes, trimParagraph(current))
+	}
+	return nodes
+}
+
+// trimParagraph drops the spaces left at the edges of a paragraph or heading where an
+// image was taken out
+func trimParagraph(p tiptap.Node) tiptap.Node {
+	if first := &p.Content[0]; first.Type == "text" {
+		first.Text = strings.TrimLeft(first.Text, " ")
+	}
+	if last := &p.Content[len(p.Content)-1]; last.Type == "text" {
+		last.Text = strings.TrimRight(last.Text, " ")
+	}
+	return p
+}
+
+func hasText(nodes []tiptap.Node) bool {
+	for _, n := range nodes {
+		if n.Type != "hardBreak" && strings.TrimSpace(n.Text) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutImages(nodes []tiptap.Node) []tiptap.Node {
+	kept := nodes[:0]
+	for _, n := range nodes {
+		if n.Type != "image" {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// Images calls visit with the src attribute of every image in the document, and stores
+// what visit returns as the new src
+func Images(doc *tiptap.Node, visit func(src string) string) {
+	if doc.Type == "image" {
+		if src, _ := doc.Attrs["src"].(string); src != "" {
+			doc.Attrs["src"] = visit(src)
+		}
+	}
+	for i := range doc.Content {
+		Images(&doc.Content[i], visit)
+	}
+}
@@ -0,0 +1,134 @@
+package markdown
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"msc-backend-api/pkg/tiptap"
+)
+
+// html converts Markdown and renders the result, which is easier to compare than the tree
+func html(src string) string {
+	return tiptap.HTML(Parse(src))
+}
+
+func TestParseBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one two</p><p>three</p>"},
+		{"crlf and blank lines", "one\r\n\r\n\r\ntwo\r\n", "<p>one</p><p>two</p>"},
+		{"atx headings", "# One\n### Three ###\n###### Six", "<h1>One</h1><h3>Three</h3><h6>Six</h6>"},
+		{"seven hashes is text", "####### no", "<p>####### no</p>"},
+		{"setext headings", "Title\n=====\n\nSub\n---", "<h1>Title</h1><h2>Sub</h2>"},
+		{"rules", "a\n\n---\n\n* * *\n\n___", "<p>a</p><hr><hr><hr>"},
+		{"heading interrupts paragraph", "text\n## Head", "<p>text</p><h2>Head</h2>"},
+		{"fenced code", "```go\nif a < b {\n}\n```", `<pre><code class="language-go">if a &lt; b {
+}</code></pre>`},
+		{"tilde fence without language", "~~~\n# not a heading\n~~~", "<pre><code># not a heading</code></pre>"},
+		{"unclosed fence runs to the end", "```\ncode", "<pre><code>code</code></pre>"},
+		{"empty fence", "```\n```", "<pre><code></code></pre>"},
+		{"blockquote", "> quoted\n> more\n>\n> # head", "<blockquote><p>quoted more</p><h1>head</h1></blockquote>"},
+		{"nested blockquote", "> a\n>> b", "<blockquote><p>a</p><blockquote><p>b</p></blockquote></blockquote>"},
+		{"bullet list", "- a\n+ b\n* c", "<ul><li><p>a</p></li><li><p>b</p></li><li><p>c</p></li></ul>"},
+		{"ordered list", "1. a\n2. b", "<ol><li><p>a</p></li><li><p>b</p></li></ol>"},
+		{"ordered list start", "3) a\n4) b", `<ol start="3"><li><p>a</p></li><li><p>b</p></li></ol>`},
+		{"loose list", "- a\n\n- b", "<ul><li><p>a</p></li><li><p>b</p></li></ul>"},
+		{"nested list", "- a\n  - b\n  - c\n- d", "<ul><li><p>a</p><ul><li><p>b</p></li><li><p>c</p></li></ul></li><li><p>d</p></li></ul>"},
+		{"item continuation", "- a\n  more\n\n  second\n- b", "<ul><li><p>a more</p><p>second</p></li><li><p>b</p></li></ul>"},
+		{"list ends at paragraph", "- a\n\nafter", "<ul><li><p>a</p></li></ul><p>after</p>"},
+		{"image splits paragraph", "before ![a](/a.png) after", `<p>before</p><img src="/a.png" alt="a" loading="lazy"><p>after</p>`},
+		{"image alone", "![](/a.png \"Title\")", `<img src="/a.png" title="Title" loading="lazy">`},
+		{"image dropped from heading", "# Hi ![a](/a.png)", "<h1>Hi</h1>"},
+		{"raw html kept as text", "<div>x</div>", "<p>&lt;div&gt;x&lt;/div&gt;</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := html(tt.src); got != tt.want {
+				t.Errorf("Parse(%q)\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMDX(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"import", "import Chart from '../components/Chart'\n\ntext", "<p>text</p>"},
+		{"export", "export const meta = { a: 1 }\n\ntext", "<p>text</p>"},
+		{"jsx block", "<Chart data={x} />\n\ntext", "<p>text</p>"},
+		{"jsx open and close", "<Callout type=\"info\">\ninside\n</Callout>", "<p>inside</p>"},
+		{"jsx member tag", "<Tabs.Item>\nx\n</Tabs.Item>", "<p>x</p>"},
+		{"jsx comment", "{/* hidden */}\ntext", "<p>text</p>"},
+		{"lowercase tag is html", "<div>\n", "<p>&lt;div&gt;</p>"},
+		{"import word mid text", "we import goods", "<p>we import goods</p>"},
+		{"kept inside code", "```js\nimport a from 'a'\n<Chart />\n```", "<pre><code class=\"language-js\">import a from &#39;a&#39;\n&lt;Chart /&gt;</code></pre>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := html(tt.src); got != tt.want {
+				t.Errorf("Parse(%q)\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValid(t *testing.T) {
+	doc := Parse("# T\n\n> - **a** [b](https://x.y)\n\n```\nc\n```\n\n![d](/d.png)")
+	content, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tiptap.Validate(string(content)); err != nil {
+		t.Fatalf("converted document is not valid: %v", err)
+	}
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		wantFront string
+		wantBody  string
+		hasFront  bool
+	}{
+		{"front matter", "---\ntitle: A\n---\nbody", "title: A", "body", true},
+		{"crlf", "---\r\ntitle: A\r\n---\r\nbody", "title: A", "body", true},
+		{"bom", "\xef\xbb\xbf---\ntitle: A\n---\nbody", "title: A", "body", true},
+		{"at end of file", "---\ntitle: A\n---", "title: A", "", true},
+		{"none", "# body", "", "# body", false},
+		{"bom without front matter", "\xef\xbb\xbfbody", "", "body", false},
+		{"rule later in file", "text\n---\nmore\n---\n", "", "text\n---\nmore\n---\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			front, body := SplitFrontMatter([]byte(tt.src))
+			if (front != nil) != tt.hasFront || string(front) != tt.wantFront || string(body) != tt.wantBody {
+				t.Errorf("SplitFrontMatter(%q) = %q, %q", tt.src, front, body)
+			}
+		})
+	}
+}
+
+func TestImages(t *testing.T) {
+	doc := Parse("![a](a.png)\n\n> ![b](/b.png)\n\n![c](https://x.y/c.png)")
+	var seen []string
+	Images(doc, func(src string) string {
+		seen = append(seen, src)
+		return "/uploads/" + strings.TrimPrefix(src, "/")
+	})
+	if got := strings.Join(seen, " "); got != "a.png /b.png https://x.y/c.png" {
+		t.Errorf("visited %s", got)
+	}
+	want := `<img src="/uploads/a.png" alt="a" loading="lazy"><blockquote><img src="/uploads/b.png" alt="b" loading="lazy"></blockquote><img src="/uploads/https://x.y/c.png" alt="c" loading="lazy">`
+	if got := tiptap.HTML(doc); got != want {
+		t.Errorf("rewritten to %s", got)
+	}
+}
@@ -0,0 +1,107 @@
+package slug
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// vietnamese maps accented Vietnamese letters to their base letter. NFD decomposition
+// alone is not enough: đ has no decomposition and ơ/ư keep their horn. Text that is
+// already decomposed (as pasted from some macOS apps) is handled by dropping the
+// combining marks.
+var vietnamese = map[rune]string{}
+
+func init() {
+	groups := map[string]string{
+		"a": "àáạảãâầấậẩẫăằắặẳẵ",
+		"e": "èéẹẻẽêềếệểễ",
+		"i": "ìíịỉĩ",
+		"o": "òóọỏõôồốộổỗơờớợởỡ",
+		"u": "ùúụủũưừứựửữ",
+		"y": "ỳýỵỷỹ",
+		"d": "đ",
+	}
+	for base, letters := range groups {
+		for _, r := range letters {
+			vietnamese[r] = base
+		}
+	}
+}
+
+// Make turns text into a lowercase ASCII slug, e.g. "Khóa học Đầu tư" becomes "khoa-hoc-dau-tu"
+func Make(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if base, ok := vietnamese[r]; ok {
+			b.WriteString(base)
+			dash = false
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// Taken reports whether a candidate slug is already in use
+type Taken func(candidate string) (bool, error)
+
+// Unique returns base when no check reports it taken, and otherwise base with the
+// lowest free numeric suffix: "khoa-hoc-2", "khoa-hoc-3", ...
+func Unique(base string, checks ...Taken) (string, error) {
+	candidate := base
+	for n := 2; ; n++ {
+		used, err := anyTaken(candidate, checks)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// InTable checks the slug column of table. Trashed rows count because they keep their
+// slug until purged; the row excludeID (empty on create) is ignored.
+func InTable(tx *gorm.DB, table, excludeID string) Taken {
+	return func(candidate string) (bool, error) {
+		query := tx.Table(table).Where("slug = ?", candidate)
+		if excludeID != "" {
+			query = query.Where("id::text <> ?", excludeID)
+		}
+		var count int64
+		err := query.Count(&count).Error
+		return count > 0, err
+	}
+}
+
+// Lock serialises slug generation for one base slug of a table until the transaction
+// ends, so two concurrent creates can't pick the same suffix
+func Lock(tx *gorm.DB, table, base string) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", table+":"+base).Error
+}
+
+func anyTaken(candidate string, checks []Taken) (bool, error) {
+	for _, taken := range checks {
+		used, err := taken(candidate)
+		if err != nil || used {
+			return used, err
+		}
+	}
+	return false, nil
+}
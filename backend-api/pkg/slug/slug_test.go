@@ -0,0 +1,80 @@
+package slug
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Hello World", "hello-world"},
+		{"vietnamese", "Khóa học Đầu tư", "khoa-hoc-dau-tu"},
+		{"all tones", "à á ạ ả ã", "a-a-a-a-a"},
+		{"horn and breve", "Ơn nghĩa ưu đãi ăn", "on-nghia-uu-dai-an"},
+		{"uppercase d with stroke", "ĐÀ NẴNG", "da-nang"},
+		{"decomposed accents", "Kho\u0301a ho\u0323c u\u031b", "khoa-hoc-u"},
+		{"digits kept", "Khóa 2024 - Đợt 1", "khoa-2024-dot-1"},
+		{"punctuation collapses", "C++ / Go & Rust!!", "c-go-rust"},
+		{"leading and trailing separators", "  --Tin tức--  ", "tin-tuc"},
+		{"non-latin dropped", "Hello 世界", "hello"},
+		{"only symbols", "!!! ???", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.text); got != tt.want {
+				t.Errorf("Make(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	set := func(slugs ...string) Taken {
+		return func(candidate string) (bool, error) {
+			for _, s := range slugs {
+				if s == candidate {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+
+	tests := []struct {
+		name   string
+		base   string
+		checks []Taken
+		want   string
+	}{
+		{"no checks", "khoa-hoc", nil, "khoa-hoc"},
+		{"free", "khoa-hoc", []Taken{set("other")}, "khoa-hoc"},
+		{"taken", "khoa-hoc", []Taken{set("khoa-hoc")}, "khoa-hoc-2"},
+		{"lowest free suffix", "khoa-hoc", []Taken{set("khoa-hoc", "khoa-hoc-2", "khoa-hoc-4")}, "khoa-hoc-3"},
+		{"taken across checks", "khoa-hoc", []Taken{set("khoa-hoc"), set("khoa-hoc-2")}, "khoa-hoc-3"},
+		{"base ending in a number", "khoa-2", []Taken{set("khoa-2")}, "khoa-2-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unique(tt.base, tt.checks...)
+			if err != nil {
+				t.Fatalf("Unique(%q) error: %v", tt.base, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unique(%q) = %q, want %q", tt.base, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueError(t *testing.T) {
+	boom := errors.New("boom")
+	failing := func(string) (bool, error) { return false, boom }
+	if _, err := Unique("khoa-hoc", failing); !errors.Is(err, boom) {
+		t.Errorf("Unique error = %v, want %v", err, boom)
+	}
+}
@@ -0,0 +1,228 @@
+package tiptap
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	colorPattern    = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|rgba?\(\s*\d{1,3}%?\s*,\s*\d{1,3}%?\s*,\s*\d{1,3}%?\s*(,\s*(0|1|0?\.\d+)\s*)?\)|[a-zA-Z]{3,20})$`)
+	languagePattern = regexp.MustCompile(`^[a-zA-Z0-9_+-]{1,32}$`)
+	textAligns      = map[string]bool{"left": true, "center": true, "right": true, "justify": true}
+	linkSchemes     = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+	imageSchemes    = map[string]bool{"http": true, "https": true}
+)
+
+// HTML renders a validated document to sanitized HTML. Only allowlisted nodes, marks and
+// attributes are emitted; all text and attribute values are escaped.
+func HTML(doc *Node) string {
+	var b strings.Builder
+	renderHTML(&b, doc)
+	return b.String()
+}
+
+// Text renders a document to plain text, one line per block
+func Text(doc *Node) string {
+	var b strings.Builder
+	renderText(&b, doc)
+	return strings.TrimSpace(b.String())
+}
+
+// RenderHTML parses stored content and renders it to sanitized HTML
+func RenderHTML(content string) (string, error) {
+	doc, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+	return HTML(doc), nil
+}
+
+// RenderText parses stored content and renders it to plain text
+func RenderText(content string) (string, error) {
+	doc, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+	return Text(doc), nil
+}
+
+func renderHTML(b *strings.Builder, n *Node) {
+	switch n.Type {
+	case "doc":
+		renderChildrenHTML(b, n)
+	case "text":
+		renderTextHTML(b, n)
+	case "paragraph":
+		wrapHTML(b, n, "p", alignStyle(n))
+	case "heading":
+		level := intAttr(n.Attrs, "level", 1)
+		if level < 1 || level > 6 {
+			level = 1
+		}
+		wrapHTML(b, n, fmt.Sprintf("h%d", level), alignStyle(n))
+	case "blockquote":
+		wrapHTML(b, n, "blockquote", "")
+	case "bulletList":
+		wrapHTML(b, n, "ul", "")
+	case "orderedList":
+		attrs := ""
+		if start := intAttr(n.Attrs, "start", 1); start != 1 {
+			attrs = fmt.Sprintf(` start="%d"`, start)
+		}
+		wrapHTML(b, n, "ol", attrs)
+	case "listItem":
+		wrapHTML(b, n, "li", "")
+	case "codeBlock":
+		attrs := ""
+		if lang, _ := n.Attrs["language"].(string); languagePattern.MatchString(lang) {
+			attrs = ` class="language-` + lang + `"`
+		}
+		b.WriteString("<pre><code" + attrs + ">")
+		for i := range n.Content {
+			b.WriteString(html.EscapeString(n.Content[i].Text))
+		}
+		b.WriteString("</code></pre>")
+	case "hardBreak":
+		b.WriteString("<br>")
+	case "horizontalRule":
+		b.WriteString("<hr>")
+	case "image":
+		src, _ := n.Attrs["src"].(string)
+		if !safeURL(src, imageSchemes) {
+			return
+		}
+		b.WriteString(`<img src="` + html.EscapeString(src) + `"`)
+		if alt, _ := n.Attrs["alt"].(string); alt != "" {
+			b.WriteString(` alt="` + html.EscapeString(alt) + `"`)
+		}
+		if title, _ := n.Attrs["title"].(string); title != "" {
+			b.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		b.WriteString(` loading="lazy">`)
+	}
+}
+
+func wrapHTML(b *strings.Builder, n *Node, tag, attrs string) {
+	b.WriteString("<" + tag + attrs + ">")
+	renderChildrenHTML(b, n)
+	b.WriteString("</" + tag + ">")
+}
+
+func renderChildrenHTML(b *strings.Builder, n *Node) {
+	for i := range n.Content {
+		renderHTML(b, &n.Content[i])
+	}
+}
+
+// renderTextHTML writes a text node wrapped in its marks, outermost mark first
+func renderTextHTML(b *strings.Builder, n *Node) {
+	var closing []string
+	for _, m := range n.Marks {
+		openTag, closeTag := markTags(m)
+		if openTag == "" {
+			continue
+		}
+		b.WriteString(openTag)
+		closing = append(closing, closeTag)
+	}
+	b.WriteString(html.EscapeString(n.Text))
+	for i := len(closing) - 1; i >= 0; i-- {
+		b.WriteString(closing[i])
+	}
+}
+
+func markTags(m Mark) (string, string) {
+	switch m.Type {
+	case "bold":
+		return "<strong>", "</strong>"
+	case "italic":
+		return "<em>", "</em>"
+	case "strike":
+		return "<s>", "</s>"
+	case "underline":
+		return "<u>", "</u>"
+	case "code":
+		return "<code>", "</code>"
+	case "link":
+		href, _ := m.Attrs["href"].(string)
+		if !strings.HasPrefix(href, "#") && !safeURL(href, linkSchemes) {
+			return "", ""
+		}
+		attrs := ` href="` + html.EscapeString(href) + `" rel="noopener noreferrer nofollow"`
+		if target, _ := m.Attrs["target"].(string); target == "_blank" {
+			attrs += ` target="_blank"`
+		}
+		return "<a" + attrs + ">", "</a>"
+	case "textStyle":
+		color, _ := m.Attrs["color"].(string)
+		if !colorPattern.MatchString(color) {
+			return "", ""
+		}
+		return `<span style="color: ` + html.EscapeString(color) + `">`, "</span>"
+	}
+	return "", ""
+}
+
+func alignStyle(n *Node) string {
+	if align, _ := n.Attrs["textAlign"].(string); textAligns[align] && align != "left" {
+		return ` style="text-align: ` + align + `"`
+	}
+	return ""
+}
+
+// safeURL accepts site-relative URLs and absolute URLs with an allowlisted scheme.
+// Browsers drop tabs and newlines inside URLs and read \ as /, so "/\evil.com" and
+// "/\t/evil.com" are protocol-relative too: control characters are rejected and
+// backslashes count as slashes.
+func safeURL(raw string, schemes map[string]bool) bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || strings.IndexFunc(raw, unicode.IsControl) >= 0 {
+		return false
+	}
+	if strings.HasPrefix(raw, "/") {
+		return !strings.HasPrefix(strings.ReplaceAll(raw, `\`, "/"), "//")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return schemes[strings.ToLower(u.Scheme)]
+}
+
+func intAttr(attrs map[string]interface{}, key string, def int) int {
+	switch v := attrs[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return def
+}
+
+func renderText(b *strings.Builder, n *Node) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "horizontalRule", "image":
+		return
+	}
+
+	for i := range n.Content {
+		renderText(b, &n.Content[i])
+	}
+
+	switch n.Type {
+	case "paragraph", "heading", "codeBlock", "listItem":
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+	}
+}
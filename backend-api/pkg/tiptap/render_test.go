@@ -0,0 +1,175 @@
+package tiptap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// link renders a paragraph holding one text node with a link mark
+func link(t *testing.T, attrs map[string]interface{}) string {
+	t.Helper()
+	return render(t, Node{Type: "doc", Content: []Node{{
+		Type: "paragraph",
+		Content: []Node{{
+			Type:  "text",
+			Text:  "x",
+			Marks: []Mark{{Type: "link", Attrs: attrs}},
+		}},
+	}}})
+}
+
+// image renders a doc holding one image node
+func image(t *testing.T, attrs map[string]interface{}) string {
+	t.Helper()
+	return render(t, Node{Type: "doc", Content: []Node{{Type: "image", Attrs: attrs}}})
+}
+
+// render goes through the stored JSON form, like content read from the database
+func render(t *testing.T, doc Node) string {
+	t.Helper()
+	content, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := RenderHTML(string(content))
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	return out
+}
+
+func TestLinkHref(t *testing.T) {
+	const rel = `" rel="noopener noreferrer nofollow">x</a></p>`
+	tests := []struct {
+		name string
+		href string
+		want string
+	}{
+		{"https", "https://example.com/a?b=1&c=2", `<p><a href="https://example.com/a?b=1&amp;c=2` + rel},
+		{"mailto", "mailto:hi@example.com", `<p><a href="mailto:hi@example.com` + rel},
+		{"tel", "tel:+84123", `<p><a href="tel:+84123` + rel},
+		{"site relative", "/chia-se/bai-viet", `<p><a href="/chia-se/bai-viet` + rel},
+		{"fragment", "#muc-1", `<p><a href="#muc-1` + rel},
+		{"uppercase scheme", "HTTPS://example.com", `<p><a href="HTTPS://example.com` + rel},
+		{"quotes escaped", `https://example.com/"onmouseover="alert(1)`, `<p><a href="https://example.com/&#34;onmouseover=&#34;alert(1)` + rel},
+		{"javascript", "javascript:alert(1)", "<p>x</p>"},
+		{"javascript mixed case", "JaVaScRiPt:alert(1)", "<p>x</p>"},
+		{"javascript leading space", "  javascript:alert(1)", "<p>x</p>"},
+		{"data", "data:text/html;base64,PHNjcmlwdD4=", "<p>x</p>"},
+		{"vbscript", "vbscript:msgbox", "<p>x</p>"},
+		{"tab in scheme", "java\tscript:alert(1)", "<p>x</p>"},
+		{"newline in scheme", "java\nscript:alert(1)", "<p>x</p>"},
+		{"nul in scheme", "java\x00script:alert(1)", "<p>x</p>"},
+		{"protocol relative", "//evil.com", "<p>x</p>"},
+		{"backslash protocol relative", `/\evil.com`, "<p>x</p>"},
+		{"double backslash", `\\evil.com`, "<p>x</p>"},
+		{"tab protocol relative", "/\t/evil.com", "<p>x</p>"},
+		{"empty", "", "<p>x</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := link(t, map[string]interface{}{"href": tt.href}); got != tt.want {
+				t.Errorf("href %q rendered %s, want %s", tt.href, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"_blank", `<p><a href="/a" rel="noopener noreferrer nofollow" target="_blank">x</a></p>`},
+		{"_top", `<p><a href="/a" rel="noopener noreferrer nofollow">x</a></p>`},
+		{`_blank" onclick="x`, `<p><a href="/a" rel="noopener noreferrer nofollow">x</a></p>`},
+	}
+	for _, tt := range tests {
+		if got := link(t, map[string]interface{}{"href": "/a", "target": tt.target}); got != tt.want {
+			t.Errorf("target %q rendered %s, want %s", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs map[string]interface{}
+		want  string
+	}{
+		{"uploaded", map[string]interface{}{"src": "/uploads/images/a.png"}, `<img src="/uploads/images/a.png" loading="lazy">`},
+		{"alt and title", map[string]interface{}{"src": "https://cdn.example.com/a.png", "alt": "Ảnh", "title": "Tiêu đề"}, `<img src="https://cdn.example.com/a.png" alt="Ảnh" title="Tiêu đề" loading="lazy">`},
+		{"src escaped", map[string]interface{}{"src": `/a.png" onerror="alert(1)`}, `<img src="/a.png&#34; onerror=&#34;alert(1)" loading="lazy">`},
+		{"alt escaped", map[string]interface{}{"src": "/a.png", "alt": `"><script>alert(1)</script>`}, `<img src="/a.png" alt="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" loading="lazy">`},
+		{"title escaped", map[string]interface{}{"src": "/a.png", "title": `' onload='x`}, `<img src="/a.png" title="&#39; onload=&#39;x" loading="lazy">`},
+		{"javascript src", map[string]interface{}{"src": "javascript:alert(1)"}, ""},
+		{"data src", map[string]interface{}{"src": "data:image/svg+xml;base64,PHN2Zz4="}, ""},
+		{"mailto src", map[string]interface{}{"src": "mailto:a@b.c"}, ""},
+		{"backslash src", map[string]interface{}{"src": `/\evil.com/a.png`}, ""},
+		{"src not a string", map[string]interface{}{"src": 1}, ""},
+		{"no src", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := image(t, tt.attrs); got != tt.want {
+				t.Errorf("rendered %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"empty", "", ""},
+		{"text escaped", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"<b>a & b</b>"}]}]}`, "<p>&lt;b&gt;a &amp; b&lt;/b&gt;</p>"},
+		{"marks outermost first", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"x","marks":[{"type":"bold"},{"type":"italic"},{"type":"code"}]}]}]}`, "<p><strong><em><code>x</code></em></strong></p>"},
+		{"heading level", `{"type":"doc","content":[{"type":"heading","attrs":{"level":3},"content":[{"type":"text","text":"h"}]}]}`, "<h3>h</h3>"},
+		{"heading level out of range", `{"type":"doc","content":[{"type":"heading","attrs":{"level":9}}]}`, "<h1></h1>"},
+		{"text align", `{"type":"doc","content":[{"type":"paragraph","attrs":{"textAlign":"center"}}]}`, `<p style="text-align: center"></p>`},
+		{"text align not allowed", `{"type":"doc","content":[{"type":"paragraph","attrs":{"textAlign":"center;background:url(x)"}}]}`, "<p></p>"},
+		{"ordered list start", `{"type":"doc","content":[{"type":"orderedList","attrs":{"start":3},"content":[{"type":"listItem"}]}]}`, `<ol start="3"><li></li></ol>`},
+		{"code block language", `{"type":"doc","content":[{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"a < b"}]}]}`, `<pre><code class="language-go">a &lt; b</code></pre>`},
+		{"code block language not allowed", `{"type":"doc","content":[{"type":"codeBlock","attrs":{"language":"\"><script>"}}]}`, "<pre><code></code></pre>"},
+		{"color", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"x","marks":[{"type":"textStyle","attrs":{"color":"#ff0000"}}]}]}]}`, `<p><span style="color: #ff0000">x</span></p>`},
+		{"color not allowed", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"x","marks":[{"type":"textStyle","attrs":{"color":"red;background:url(x)"}}]}]}]}`, "<p>x</p>"},
+		{"breaks and rules", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"a"},{"type":"hardBreak"},{"type":"text","text":"b"}]},{"type":"horizontalRule"}]}`, "<p>a<br>b</p><hr>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderHTML(tt.content)
+			if err != nil {
+				t.Fatalf("RenderHTML() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderHTML() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRejectsInvalid(t *testing.T) {
+	for _, content := range []string{
+		`{"type":"doc","content":[{"type":"iframe","attrs":{"src":"https://evil.com"}}]}`,
+		`{"type":"doc","content":[{"type":"doc"}]}`,
+		`{"type":"doc","content":[{"type":"paragraph","text":"<script>"}]}`,
+		nested(maxDepth),
+	} {
+		if out, err := RenderHTML(content); err == nil {
+			t.Errorf("RenderHTML(%.60s) = %s, want an error", content, out)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	got, err := RenderText(`{"type":"doc","content":[{"type":"heading","content":[{"type":"text","text":"Title"}]},{"type":"paragraph","content":[{"type":"text","text":"a"},{"type":"hardBreak"},{"type":"text","text":"<b>"}]},{"type":"image","attrs":{"src":"/a.png"}}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Title\na\n<b>"; got != want {
+		t.Errorf("RenderText() = %q, want %q", got, want)
+	}
+}
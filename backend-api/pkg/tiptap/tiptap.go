@@ -0,0 +1,105 @@
+package tiptap
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// maxDepth bounds nesting so a hostile document can't exhaust the stack while rendering
+const maxDepth = 64
+
+// Node is a ProseMirror node as stored by the Tiptap editor
+type Node struct {
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []Node                 `json:"content,omitempty"`
+	Marks   []Mark                 `json:"marks,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+}
+
+// Mark is inline formatting applied to a text node
+type Mark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// Node types accepted in stored documents (StarterKit plus the admin editor's extensions)
+var allowedNodes = map[string]bool{
+	"doc":            true,
+	"paragraph":      true,
+	"text":           true,
+	"heading":        true,
+	"blockquote":     true,
+	"bulletList":     true,
+	"orderedList":    true,
+	"listItem":       true,
+	"codeBlock":      true,
+	"hardBreak":      true,
+	"horizontalRule": true,
+	"image":          true,
+}
+
+// Mark types accepted in stored documents
+var allowedMarks = map[string]bool{
+	"bold":      true,
+	"italic":    true,
+	"strike":    true,
+	"underline": true,
+	"code":      true,
+	"link":      true,
+	"textStyle": true,
+}
+
+// Parse decodes and validates a stored document. An empty string is an empty document.
+func Parse(content string) (*Node, error) {
+	if content == "" || content == "null" {
+		return &Node{Type: "doc"}, nil
+	}
+
+	var doc Node
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, fmt.Errorf("content is not valid JSON: %w", err)
+	}
+	if doc.Type != "doc" {
+		return nil, fmt.Errorf("root node must be doc, got %q", doc.Type)
+	}
+	if err := validate(&doc, 0); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Validate reports whether content is a document the renderer accepts
+func Validate(content string) error {
+	_, err := Parse(content)
+	return err
+}
+
+func validate(n *Node, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("document is nested deeper than %d levels", maxDepth)
+	}
+	if !allowedNodes[n.Type] {
+		return fmt.Errorf("node type %q is not allowed", n.Type)
+	}
+	if n.Type == "doc" && depth > 0 {
+		return fmt.Errorf("doc node is only allowed at the root")
+	}
+	if n.Type != "text" && n.Text != "" {
+		return fmt.Errorf("node type %q cannot carry text", n.Type)
+	}
+	if n.Type == "text" && len(n.Content) > 0 {
+		return fmt.Errorf("text nodes cannot have children")
+	}
+	for _, m := range n.Marks {
+		if !allowedMarks[m.Type] {
+			return fmt.Errorf("mark type %q is not allowed", m.Type)
+		}
+	}
+	for i := range n.Content {
+		if err := validate(&n.Content[i], depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package tiptap
+
+import (
+	"strings"
+	"testing"
+)
+
+// nested returns a doc with depth blockquotes inside each other around a paragraph
+func nested(depth int) string {
+	return `{"type":"doc","content":[` +
+		strings.Repeat(`{"type":"blockquote","content":[`, depth) +
+		`{"type":"paragraph"}` +
+		strings.Repeat(`]}`, depth) +
+		`]}`
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty string", "", ""},
+		{"null", "null", ""},
+		{"empty doc", `{"type":"doc"}`, ""},
+		{"allowed nodes and marks", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"hi","marks":[{"type":"bold"},{"type":"link","attrs":{"href":"/a"}}]}]}]}`, ""},
+		{"invalid json", `{"type":`, "not valid JSON"},
+		{"root not doc", `{"type":"paragraph"}`, "root node must be doc"},
+		{"disallowed node", `{"type":"doc","content":[{"type":"iframe"}]}`, `node type "iframe" is not allowed`},
+		{"disallowed nested node", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"script"}]}]}`, `node type "script" is not allowed`},
+		{"disallowed mark", `{"type":"doc","content":[{"type":"paragraph","content":[{"type":"text","text":"x","marks":[{"type":"onclick"}]}]}]}`, `mark type "onclick" is not allowed`},
+		{"nested doc", `{"type":"doc","content":[{"type":"doc"}]}`, "doc node is only allowed at the root"},
+		{"text on paragraph", `{"type":"doc","content":[{"type":"paragraph","text":"<script>"}]}`, `node type "paragraph" cannot carry text`},
+		{"text with children", `{"type":"doc","content":[{"type":"text","text":"x","content":[{"type":"text","text":"y"}]}]}`, "text nodes cannot have children"},
+		{"at max depth", nested(maxDepth - 1), ""},
+		{"beyond max depth", nested(maxDepth), "nested deeper than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.content)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Parse() error = %v, want nil", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("Parse() error = nil, want %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
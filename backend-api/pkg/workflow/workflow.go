@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"errors"
+	"strings"
+
+	"msc-backend-api/pkg/auth"
+)
+
+// Content statuses shared by posts and courses
+const (
+	StatusDraft         = "draft"
+	StatusPendingReview = "pending_review"
+	StatusScheduled     = "scheduled"
+	StatusPublished     = "published"
+	StatusArchived      = "archived"
+)
+
+// Workflow actions
+const (
+	ActionSubmit     = "submit"
+	ActionApprove    = "approve"
+	ActionReject     = "reject"
+	ActionSchedule   = "schedule"
+	ActionUnschedule = "unschedule"
+	ActionPublish    = "publish"
+	ActionUnpublish  = "unpublish"
+	ActionArchive    = "archive"
+	ActionRestore    = "restore"
+
+	// ActionEdit is recorded when an edit sends reviewed content back to review; it is
+	// not a transition anyone can request
+	ActionEdit = "edit"
+)
+
+// RoleSystem is used by background jobs such as the publishing scheduler
+const RoleSystem = "system"
+
+var (
+	ErrUnknownAction     = errors.New("unknown workflow action")
+	ErrInvalidTransition = errors.New("action not allowed from the current status")
+	ErrForbidden         = errors.New("insufficient permissions for this action")
+	ErrReasonRequired    = errors.New("a reason is required for this action")
+	ErrInvalidStatus     = errors.New("invalid initial status")
+)
+
+// Transition describes one allowed move of the state machine
+type Transition struct {
+	Action         string   `json:"action"`
+	From           []string `json:"from"`
+	To             string   `json:"to"`
+	Roles          []string `json:"roles"`
+	RequiresReason bool     `json:"requires_reason"`
+}
+
+var (
+	reviewers          = []string{"admin", "editor"}
+	reviewersAndSystem = []string{"admin", "editor", RoleSystem}
+)
+
+var transitions = []Transition{
+	{Action: ActionSubmit, From: []string{StatusDraft}, To: StatusPendingReview, Roles: []string{"admin", "editor", "partner"}},
+	{Action: ActionApprove, From: []string{StatusPendingReview}, To: StatusPublished, Roles: reviewers},
+	{Action: ActionReject, From: []string{StatusPendingReview}, To: StatusDraft, Roles: reviewers, RequiresReason: true},
+	{Action: ActionSchedule, From: []string{StatusDraft, StatusPendingReview}, To: StatusScheduled, Roles: reviewers},
+	{Action: ActionUnschedule, From: []string{StatusScheduled}, To: StatusDraft, Roles: reviewers},
+	{Action: ActionPublish, From: []string{StatusDraft, StatusScheduled}, To: StatusPublished, Roles: reviewersAndSystem},
+	{Action: ActionUnpublish, From: []string{StatusPublished}, To: StatusDraft, Roles: reviewersAndSystem},
+	{Action: ActionArchive, From: []string{StatusDraft, StatusPendingReview, StatusScheduled, StatusPublished}, To: StatusArchived, Roles: reviewersAndSystem},
+	{Action: ActionRestore, From: []string{StatusArchived}, To: StatusDraft, Roles: reviewers},
+}
+
+// Transitions returns the full transition table
+func Transitions() []Transition {
+	return transitions
+}
+
+// Apply validates an action against the current status and the caller's roles and
+// returns the resulting status
+func Apply(from, action string, roles []string, reason string) (string, error) {
+	var known bool
+	for _, t := range transitions {
+		if t.Action != action {
+			continue
+		}
+		known = true
+		if !contains(t.From, from) {
+			continue
+		}
+		if !auth.HasAnyRole(roles, t.Roles) {
+			return "", ErrForbidden
+		}
+		if t.RequiresReason && strings.TrimSpace(reason) == "" {
+			return "", ErrReasonRequired
+		}
+		return t.To, nil
+	}
+
+	if !known {
+		return "", ErrUnknownAction
+	}
+	return "", ErrInvalidTransition
+}
+
+// Available returns the actions the given roles may perform from a status
+func Available(from string, roles []string) []Transition {
+	available := []Transition{}
+	for _, t := range transitions {
+		if contains(t.From, from) && auth.HasAnyRole(roles, t.Roles) {
+			available = append(available, t)
+		}
+	}
+	return available
+}
+
+// InitialStatus resolves the status of newly created content. Content can only start
+// as a draft or in review; publishing always goes through a transition.
+func InitialStatus(requested string, roles []string) (string, error) {
+	switch requested {
+	case "":
+		if auth.HasRole(roles, "partner") && !auth.HasAnyRole(roles, reviewers) {
+			return StatusPendingReview, nil
+		}
+		return StatusDraft, nil
+	case StatusDraft, StatusPendingReview:
+		return requested, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+// AfterEdit returns the status content moves to when someone with roles edits it.
+// Reviewers edit in place. Partners' edits to scheduled or published content go back to
+// review, so nothing they write reaches the public site unreviewed.
+func AfterEdit(status string, roles []string) string {
+	if (status == StatusScheduled || status == StatusPublished) && !auth.HasAnyRole(roles, reviewers) {
+		return StatusPendingReview
+	}
+	return status
+}
+
+func contains(list []string, item string) bool {
+	for _, s := range list {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
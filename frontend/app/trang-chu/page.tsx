@@ -1,6 +1,9 @@
 "use client"
 
+import { useEffect, useState } from "react"
 import { useAuth } from "@/contexts/auth-context"
+import { api, Enrollment } from "@/lib/api"
+import { formatRating } from "@/lib/utils"
 import { motion } from "framer-motion"
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
 import { Avatar, AvatarFallback } from "@/components/ui/avatar"
@@ -18,6 +21,14 @@ import Image from "next/image"
 
 export default function HomePage() {
   const { user, isLoading, isAuthenticated, logout } = useAuth()
+  const [enrollments, setEnrollments] = useState<Enrollment[]>([])
+
+  useEffect(() => {
+    if (!isAuthenticated) return
+    api.getEnrolledCourses().then((result) => {
+      if (result.success && result.data) setEnrollments(result.data)
+    })
+  }, [isAuthenticated])
 
   if (isLoading) {
     return (
@@ -85,47 +96,14 @@ export default function HomePage() {
     { icon: <TrendingUp className="h-6 w-6" />, label: "Điểm trung bình", value: "8.7", total: 10, color: "text-orange-600", bg: "bg-orange-100" }
   ]
 
-  const recentCourses = [
-    {
-      id: 1,
-      title: "React & Next.js Masterclass",
-      instructor: "Quách Thành Long",
-      progress: 75,
-      totalLessons: 24,
-      completedLessons: 18,
-      thumbnail: "/courses/react.webp",
-      category: "Frontend",
-      level: "Nâng cao",
-      duration: "45h",
-      rating: 4.8
-    },
-    {
-      id: 2,
-      title: "Node.js Backend Development",
-      instructor: "Nguyễn Tuấn Dũng",
-      progress: 45,
-      totalLessons: 32,
-      completedLessons: 14,
-      thumbnail: "/courses/nodejs.webp",
-      category: "Backend",
-      level: "Trung bình",
-      duration: "38h",
-      rating: 4.9
-    },
-    {
-      id: 3,
-      title: "UI/UX Design Fundamentals",
-      instructor: "Lê Minh Tân",
-      progress: 30,
-      totalLessons: 20,
-      completedLessons: 6,
-      thumbnail: "/courses/uiux.webp",
-      category: "Design",
-      level: "Cơ bản",
-      duration: "25h",
-      rating: 4.7
-    }
-  ]
+  const recentCourses = enrollments.slice(0, 3).map((enrollment) => ({
+    id: enrollment.course.id,
+    title: enrollment.course.title,
+    instructor: enrollment.course.author?.name,
+    category: enrollment.course.categories?.[0]?.name,
+    progress: Math.round(enrollment.progress * 100),
+    rating: formatRating(enrollment.course.rating_average, enrollment.course.rating_count),
+  }))
 
   const popularCourses = [
     { title: "Python for Data Science", students: 1234, icon: <Brain className="h-5 w-5" />, category: "Data Science" },
@@ -306,15 +284,15 @@ export default function HomePage() {
                             <h3 className="font-semibold text-gray-900 dark:text-white group-hover:text-blue-600 transition-colors">
                               {course.title}
                             </h3>
-                            <Badge variant="secondary">{course.category}</Badge>
+                            {course.category && <Badge variant="secondary">{course.category}</Badge>}
                           </div>
                           <div className="flex items-center space-x-4 text-sm text-gray-600 dark:text-gray-300 mb-2">
-                            <span>Bởi {course.instructor}</span>
-                            <span>•</span>
-                            <span>{course.completedLessons}/{course.totalLessons} bài</span>
-                            <span>•</span>
-                            <span>{course.duration}</span>
-                            <span>•</span>
+                            {course.instructor && (
+                              <>
+                                <span>Bởi {course.instructor}</span>
+                                <span>•</span>
+                              </>
+                            )}
                             <div className="flex items-center space-x-1">
                               <Star className="h-3 w-3 fill-yellow-400 text-yellow-400" />
                               <span>{course.rating}</span>
@@ -324,6 +302,9 @@ export default function HomePage() {
                         </div>
                       </div>
                     ))}
+                    {recentCourses.length === 0 && (
+                      <p className="text-sm text-gray-600 dark:text-gray-300">Bạn chưa đăng ký khóa học nào.</p>
+                    )}
                   </div>
                 </CardContent>
               </Card>
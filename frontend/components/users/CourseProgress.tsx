@@ -1,19 +1,17 @@
 "use client"
 
-import { useState } from "react"
+import { useEffect, useState } from "react"
 import { motion } from "framer-motion"
 import {
   BookOpen,
-  Clock,
+  Circle,
   Play,
   CheckCircle,
-  Award,
   TrendingUp,
   Calendar,
   Filter,
   Search,
   Star,
-  Users,
   BarChart3,
 } from "lucide-react"
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card"
@@ -23,6 +21,8 @@ import { Badge } from "@/components/ui/badge"
 import { Input } from "@/components/ui/input"
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select"
 import Image from "next/image"
+import { api, Enrollment } from "@/lib/api"
+import { formatRating } from "@/lib/utils"
 
 interface Course {
   id: string
@@ -30,98 +30,38 @@ interface Course {
   instructor: string
   thumbnail: string
   category: string
-  level: string
   progress: number
-  completedLessons: number
-  totalLessons: number
-  duration: number
-  lastAccessed: string
-  rating: number
-  enrolledStudents: number
-  certificate?: {
-    id: string
-    issuedAt: string
-    url: string
-  }
+  enrolledAt: string
+  rating: string
   status: "not_started" | "in_progress" | "completed"
 }
 
+const toCourse = (enrollment: Enrollment): Course => {
+  const progress = Math.round(enrollment.progress * 100)
+  return {
+    id: enrollment.course.id,
+    title: enrollment.course.title,
+    instructor: enrollment.course.author?.name ?? "",
+    thumbnail: enrollment.course.thumbnail_url ?? "",
+    category: enrollment.course.categories?.[0]?.name ?? "",
+    progress,
+    enrolledAt: enrollment.enrolled_at,
+    rating: formatRating(enrollment.course.rating_average, enrollment.course.rating_count),
+    status: progress >= 100 ? "completed" : progress > 0 ? "in_progress" : "not_started",
+  }
+}
+
 const CourseProgress = () => {
   const [searchTerm, setSearchTerm] = useState("")
   const [filterStatus, setFilterStatus] = useState("all")
   const [sortBy, setSortBy] = useState("recent")
+  const [courses, setCourses] = useState<Course[]>([])
 
-  const courses: Course[] = [
-    {
-      id: "1",
-      title: "Advanced React Patterns & Performance",
-      instructor: "Nguyễn Minh Khôi",
-      thumbnail: "/placeholder.svg?height=200&width=300&text=React+Course",
-      category: "Frontend Development",
-      level: "Advanced",
-      progress: 100,
-      completedLessons: 24,
-      totalLessons: 24,
-      duration: 18,
-      lastAccessed: "2024-01-20T09:00:00Z",
-      rating: 4.9,
-      enrolledStudents: 1250,
-      certificate: {
-        id: "cert-001",
-        issuedAt: "2024-01-20T10:00:00Z",
-        url: "/certificates/react-advanced.pdf",
-      },
-      status: "completed",
-    },
-    {
-      id: "2",
-      title: "Full-Stack TypeScript Development",
-      instructor: "Trần Thị Mai",
-      thumbnail: "/placeholder.svg?height=200&width=300&text=TypeScript+Course",
-      category: "Full-Stack Development",
-      level: "Intermediate",
-      progress: 65,
-      completedLessons: 13,
-      totalLessons: 20,
-      duration: 25,
-      lastAccessed: "2024-01-19T15:30:00Z",
-      rating: 4.8,
-      enrolledStudents: 890,
-      status: "in_progress",
-    },
-    {
-      id: "3",
-      title: "AWS Cloud Architecture Fundamentals",
-      instructor: "Lê Hoàng Nam",
-      thumbnail: "/placeholder.svg?height=200&width=300&text=AWS+Course",
-      category: "Cloud Computing",
-      level: "Beginner",
-      progress: 30,
-      completedLessons: 6,
-      totalLessons: 20,
-      duration: 22,
-      lastAccessed: "2024-01-18T11:00:00Z",
-      rating: 4.7,
-      enrolledStudents: 2100,
-      status: "in_progress",
-    },
-    {
-      id: "4",
-      title: "Machine Learning with Python",
-      instructor: "Phạm Văn Đức",
-      thumbnail: "/placeholder.svg?height=200&width=300&text=ML+Course",
-      category: "Data Science",
-      level: "Intermediate",
-      progress: 0,
-      completedLessons: 0,
-      totalLessons: 28,
-      duration: 35,
-      lastAccessed: "2024-01-15T08:00:00Z",
-      rating: 4.6,
-      enrolledStudents: 1580,
-      status: "not_started",
-    },
-  ]
+  useEffect(() => {
+    api.getEnrolledCourses().then((result) => {
+      if (result.success && result.data) setCourses(result.data.map(toCourse))
+    })
+  }, [])
 
   const filteredCourses = courses.filter((course) => {
     const matchesSearch =
@@ -134,7 +74,7 @@ const CourseProgress = () => {
   const sortedCourses = [...filteredCourses].sort((a, b) => {
     switch (sortBy) {
       case "recent":
-        return new Date(b.lastAccessed).getTime() - new Date(a.lastAccessed).getTime()
+        return new Date(b.enrolledAt).getTime() - new Date(a.enrolledAt).getTime()
       case "progress":
         return b.progress - a.progress
       case "title":
@@ -184,7 +124,7 @@ const CourseProgress = () => {
   return (
     <div className="space-y-6">
       {/* Course Statistics */}
-      <div className="grid grid-cols-1 md:grid-cols-4 gap-4">
+      <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
         <Card className="text-center p-4">
           <div className="flex items-center justify-center mb-2">
             <CheckCircle className="h-6 w-6 text-green-600" />
@@ -207,20 +147,12 @@ const CourseProgress = () => {
 
         <Card className="text-center p-4">
           <div className="flex items-center justify-center mb-2">
-            <Clock className="h-6 w-6 text-orange-600" />
-          </div>
-          <div className="text-2xl font-bold text-orange-600">
-            {courses.reduce((total, course) => total + course.duration, 0)}
+            <Circle className="h-6 w-6 text-gray-600" />
           </div>
-          <div className="text-sm text-gray-600">Tổng giờ học</div>
-        </Card>
-
-        <Card className="text-center p-4">
-          <div className="flex items-center justify-center mb-2">
-            <Award className="h-6 w-6 text-yellow-600" />
+          <div className="text-2xl font-bold text-gray-600">
+            {courses.filter((c) => c.status === "not_started").length}
           </div>
-          <div className="text-2xl font-bold text-yellow-600">{courses.filter((c) => c.certificate).length}</div>
-          <div className="text-sm text-gray-600">Chứng chỉ</div>
+          <div className="text-sm text-gray-600">Chưa bắt đầu</div>
         </Card>
       </div>
 
@@ -291,31 +223,27 @@ const CourseProgress = () => {
                     <div className="absolute top-3 left-3">
                       <Badge className={getStatusColor(course.status)}>{getStatusText(course.status)}</Badge>
                     </div>
-                    <div className="absolute top-3 right-3">
-                      <Badge variant="secondary" className="bg-black/50 text-white">
-                        {course.level}
-                      </Badge>
-                    </div>
+                    {course.category && (
+                      <div className="absolute top-3 right-3">
+                        <Badge variant="secondary" className="bg-black/50 text-white">
+                          {course.category}
+                        </Badge>
+                      </div>
+                    )}
                   </div>
 
                   <CardContent className="p-4 flex flex-col h-full">
                     <div className="flex-1">
                       <h3 className="font-semibold text-lg mb-2 line-clamp-2">{course.title}</h3>
-                      <p className="text-sm text-gray-600 mb-3">Giảng viên: {course.instructor}</p>
+                      {course.instructor && (
+                        <p className="text-sm text-gray-600 mb-3">Giảng viên: {course.instructor}</p>
+                      )}
 
                       <div className="flex items-center gap-4 text-sm text-gray-500 mb-3">
                         <div className="flex items-center gap-1">
                           <Star className="h-4 w-4 text-yellow-500" />
                           <span>{course.rating}</span>
                         </div>
-                        <div className="flex items-center gap-1">
-                          <Users className="h-4 w-4" />
-                          <span>{course.enrolledStudents.toLocaleString()}</span>
-                        </div>
-                        <div className="flex items-center gap-1">
-                          <Clock className="h-4 w-4" />
-                          <span>{course.duration}h</span>
-                        </div>
                       </div>
 
                       <div className="mb-4">
@@ -324,31 +252,21 @@ const CourseProgress = () => {
                           <span className="font-medium">{course.progress}%</span>
                         </div>
                         <Progress value={course.progress} className="h-2" />
-                        <p className="text-xs text-gray-500 mt-1">
-                          {course.completedLessons}/{course.totalLessons} bài học
-                        </p>
                       </div>
 
                       <div className="text-xs text-gray-500 mb-4">
                         <div className="flex items-center gap-1">
                           <Calendar className="h-3 w-3" />
-                          <span>Truy cập: {formatTimeAgo(course.lastAccessed)}</span>
+                          <span>Đăng ký: {formatTimeAgo(course.enrolledAt)}</span>
                         </div>
                       </div>
                     </div>
 
                     <div className="flex gap-2 mt-auto">
-                      {course.status === "completed" && course.certificate ? (
-                        <Button variant="outline" size="sm" className="flex-1 bg-transparent">
-                          <Award className="h-4 w-4 mr-2" />
-                          Xem chứng chỉ
-                        </Button>
-                      ) : (
-                        <Button size="sm" className="flex-1 btn-primary">
-                          <Play className="h-4 w-4 mr-2" />
-                          {course.status === "not_started" ? "Bắt đầu học" : "Tiếp tục học"}
-                        </Button>
-                      )}
+                      <Button size="sm" className="flex-1 btn-primary">
+                        <Play className="h-4 w-4 mr-2" />
+                        {course.status === "not_started" ? "Bắt đầu học" : course.status === "completed" ? "Xem lại" : "Tiếp tục học"}
+                      </Button>
                       <Button variant="outline" size="sm">
                         <BarChart3 className="h-4 w-4" />
                       </Button>
@@ -108,6 +108,18 @@ export interface BlogPost {
   read_time: string;
   views: number;
   likes: number;
+  comments_count: number;
+}
+
+export interface BlogComment {
+  id: string;
+  blog_post_id: number;
+  parent_id?: string;
+  content: string;
+  status: 'pending' | 'approved' | 'rejected' | 'spam';
+  user?: { id: string; name: string };
+  replies?: BlogComment[];
+  created_at: string;
 }
 
 export interface BlogPostsResponse {
@@ -122,6 +134,96 @@ export interface BlogPostsResponse {
   error?: string;
 }
 
+export type SearchHitType = 'program' | 'project' | 'blog' | 'mentor';
+
+export interface SearchHit {
+  type: SearchHitType;
+  id: string;
+  title: string;
+  slug?: string;
+  url: string;
+  snippet?: string; // HTML-escaped, matches wrapped in <mark>
+  rank: number;
+}
+
+export interface SearchGroup {
+  type: SearchHitType;
+  total: number;
+  hits: SearchHit[];
+}
+
+export interface SearchSuggestion {
+  type: SearchHitType;
+  title: string;
+  url: string;
+}
+
+// Published alumni profile for the MSCer pages
+export interface MSCer {
+  id: string;
+  slug: string;
+  name: string;
+  company?: string;
+  position?: string;
+  avatar_url?: string;
+  achievement?: string;
+  testimonial?: string;
+  graduation_year?: number;
+  promotion?: string;
+  social_impact?: string;
+  course?: string;
+  skills: string[];
+  achievements: string[];
+  mentoring?: string;
+  background: { education?: string; previous_role?: string; experience?: string };
+  program?: { id: string; slug: string; title: string };
+}
+
+// Course as shown to learners, with the average of its approved reviews
+export interface Course {
+  id: string;
+  slug: string;
+  title: string;
+  description?: string;
+  thumbnail_url?: string;
+  status: string;
+  rating_average: number; // 0 until the first review is approved
+  rating_count: number;
+  author?: { id: string; name: string };
+  categories?: Array<{ id: string; name: string; slug: string }>;
+}
+
+// Enrollment of the signed-in learner in a course
+export interface Enrollment {
+  id: string;
+  course_id: string;
+  enrolled_at: string;
+  progress: number; // 0 to 1
+  course: Course;
+}
+
+export type ReviewTarget = 'courses' | 'mentors';
+
+// Learner rating of a course or mentor; lists hold approved reviews only
+export interface Review {
+  id: string;
+  entity_type: 'course' | 'mentor';
+  entity_id: string;
+  rating: number; // 1 to 5
+  content?: string;
+  status: 'pending' | 'approved' | 'rejected';
+  user?: { id: string; name: string };
+  created_at: string;
+}
+
+export type EngagementType = 'allblogposts' | 'programs' | 'projects';
+
+export interface Engagement {
+  views: number;
+  likes: number;
+  liked: boolean; // whether the current visitor likes the item
+}
+
 export const api = {
   async getBlogPosts(page: number = 1, limit: number = 10, category?: string): Promise<BlogPostsResponse> {
     try {
@@ -220,6 +322,143 @@ export const api = {
       console.error('Error fetching project by slug:', error);
       return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
     }
+  },
+
+  async search(q: string, limit: number = 5): Promise<{ success: boolean; data?: { query: string; groups: SearchGroup[] }; error?: string }> {
+    try {
+      const params = new URLSearchParams({ q, limit: limit.toString() });
+      const response = await fetch(`${API_URL}/search?${params.toString()}`);
+      return await response.json();
+    } catch (error) {
+      console.error('Error searching:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  async searchSuggest(q: string, limit: number = 8): Promise<{ success: boolean; data?: SearchSuggestion[]; error?: string }> {
+    try {
+      const params = new URLSearchParams({ q, limit: limit.toString() });
+      const response = await fetch(`${API_URL}/search/suggest?${params.toString()}`);
+      return await response.json();
+    } catch (error) {
+      console.error('Error fetching search suggestions:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  async getBlogComments(blogPostId: number): Promise<{ success: boolean; data?: BlogComment[]; error?: string }> {
+    try {
+      const response = await fetch(`${API_URL}/allblogposts/${blogPostId}/comments`);
+      return await response.json();
+    } catch (error) {
+      console.error('Error fetching comments:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  // Requires a signed-in user; new comments wait for moderation
+  async postBlogComment(blogPostId: number, content: string, parentId?: string): Promise<{ success: boolean; data?: BlogComment; message?: string; error?: string }> {
+    try {
+      const token = localStorage.getItem('token');
+      const response = await fetch(`${API_URL}/allblogposts/${blogPostId}/comments`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${token}`,
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ content, parent_id: parentId }),
+      });
+      return await response.json();
+    } catch (error) {
+      console.error('Error posting comment:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  // course matches the course name or the slug of the program the alumni took
+  async getMSCers(params: { page?: number; limit?: number; course?: string; graduationYear?: number } = {}): Promise<{ success: boolean; data?: { data: MSCer[]; total: number; page: number; limit: number; total_pages: number }; error?: string }> {
+    try {
+      const query = new URLSearchParams();
+      if (params.page) query.set('page', String(params.page));
+      if (params.limit) query.set('limit', String(params.limit));
+      if (params.course) query.set('course', params.course);
+      if (params.graduationYear) query.set('graduation_year', String(params.graduationYear));
+      const response = await fetch(`${API_URL}/mscers?${query.toString()}`);
+      return await response.json();
+    } catch (error) {
+      console.error('Error fetching MSCers:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  async getMSCerBySlug(slug: string): Promise<{ success: boolean; data?: MSCer; error?: string }> {
+    try {
+      const response = await fetch(`${API_URL}/mscers/slug/${encodeURIComponent(slug)}`);
+      return await response.json();
+    } catch (error) {
+      console.error('Error fetching MSCer:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  async getMentorReviews(mentorId: string, page: number = 1): Promise<{ success: boolean; data?: { data: Review[]; total: number; page: number; limit: number; total_pages: number }; error?: string }> {
+    try {
+      const response = await fetch(`${API_URL}/mentors/${mentorId}/reviews?page=${page}`);
+      return await response.json();
+    } catch (error) {
+      console.error('Error fetching reviews:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  // Courses of the signed-in learner, most recently enrolled first
+  async getEnrolledCourses(): Promise<{ success: boolean; data?: Enrollment[]; error?: string }> {
+    try {
+      const token = localStorage.getItem('token');
+      const response = await fetch(`${API_URL}/v1/courses/enrolled`, {
+        headers: { 'Authorization': `Bearer ${token}` },
+      });
+      return await response.json();
+    } catch (error) {
+      console.error('Error fetching enrolled courses:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  // Requires a signed-in learner of the course or mentor; replaces their earlier review,
+  // which then waits for moderation again
+  async submitReview(target: ReviewTarget, id: string, rating: number, content?: string): Promise<{ success: boolean; data?: Review; message?: string; error?: string }> {
+    try {
+      const token = localStorage.getItem('token');
+      const response = await fetch(`${API_URL}/v1/${target}/${id}/reviews/mine`, {
+        method: 'PUT',
+        headers: {
+          'Authorization': `Bearer ${token}`,
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify({ rating, content }),
+      });
+      return await response.json();
+    } catch (error) {
+      console.error('Error submitting review:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
+  },
+
+  // Views and likes; signed-in users are recognised by their token, others by their browser
+  async engagement(type: EngagementType, id: string | number, action: 'get' | 'view' | 'like' | 'unlike' = 'get'): Promise<{ success: boolean; data?: Engagement; error?: string }> {
+    try {
+      const path = action === 'get' ? '' : action === 'unlike' ? '/like' : `/${action}`;
+      const token = typeof window !== 'undefined' ? localStorage.getItem('token') : null;
+      const response = await fetch(`${API_URL}/engagement/${type}/${id}${path}`, {
+        method: action === 'get' ? 'GET' : action === 'unlike' ? 'DELETE' : 'POST',
+        headers: token ? { 'Authorization': `Bearer ${token}` } : undefined,
+      });
+      return await response.json();
+    } catch (error) {
+      console.error('Error updating views and likes:', error);
+      return { success: false, error: error instanceof Error ? error.message : 'Unknown error' };
+    }
   }
 };
 
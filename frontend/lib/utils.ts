@@ -4,3 +4,8 @@ import { twMerge } from "tailwind-merge"
 export function cn(...inputs: ClassValue[]) {
   return twMerge(clsx(inputs))
 }
+
+// formatRating shows an average of approved reviews with their number, e.g. "4.8 (12)"
+export function formatRating(average: number, count: number) {
+  return count > 0 ? `${average.toFixed(1)} (${count})` : "Chưa có đánh giá"
+}
@@ -6,6 +6,16 @@ const nextConfig = {
     NEXT_PUBLIC_API_URL: process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api',
   },
 
+  // sitemap.xml và robots.txt được sinh từ dữ liệu ở backend
+  async rewrites() {
+    const apiOrigin = (process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api').replace(/\/api\/?$/, '')
+    return [
+      { source: '/sitemap.xml', destination: `${apiOrigin}/sitemap.xml` },
+      { source: '/sitemaps/:file', destination: `${apiOrigin}/sitemaps/:file` },
+      { source: '/robots.txt', destination: `${apiOrigin}/robots.txt` },
+    ]
+  },
+
   // Bật các header CORS cho API (giữ nguyên)
   async headers() {
     return [